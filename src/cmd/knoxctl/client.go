@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// =================== //
+// == gRPC Dialing  == //
+// =================== //
+
+// tokenCredentials sends the bearer token in the authorization header of each call
+type tokenCredentials struct {
+	token  string
+	secure bool
+}
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return t.secure
+}
+
+func (o *globalOptions) useTLS() bool {
+	return o.tls || o.caFile != "" || o.certFile != ""
+}
+
+// getTLSConfig builds the client tls config from the ca and client certificate flags
+func (o *globalOptions) getTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.insecureSkipVerify, // #nosec G402 -- explicitly requested by the user
+	}
+
+	if o.caFile != "" {
+		ca, err := os.ReadFile(o.caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("no valid certificate found in " + o.caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if o.certFile != "" || o.keyFile != "" {
+		if o.certFile == "" || o.keyFile == "" {
+			return nil, errors.New("--cert-file and --key-file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(o.certFile, o.keyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func (o *globalOptions) dialOptions() ([]grpc.DialOption, error) {
+	dialOpts := []grpc.DialOption{}
+
+	if o.useTLS() {
+		tlsConfig, err := o.getTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	if o.token != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(tokenCredentials{token: o.token, secure: o.useTLS()}))
+	}
+
+	return dialOpts, nil
+}
+
+// connect opens the connection to the discovery engine, the caller closes it
+func connect() (*grpc.ClientConn, error) {
+	dialOpts, err := opts.dialOptions()
+	if err != nil {
+		return nil, err
+	}
+	return grpc.Dial(opts.server, dialOpts...)
+}
+
+// unaryContext bounds a unary call by the --timeout flag
+func unaryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if opts.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, opts.timeout)
+}
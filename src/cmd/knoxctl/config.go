@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"sigs.k8s.io/yaml"
+
+	cpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/config"
+)
+
+// ==================== //
+// == Config Command == //
+// ==================== //
+
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configurations of the config store",
+	}
+
+	cmd.AddCommand(
+		newConfigGetCommand(),
+		newConfigWriteCommand("add", "Add a configuration from a yaml or json file", cpb.ConfigStoreClient.Add),
+		newConfigWriteCommand("update", "Update a configuration from a yaml or json file", cpb.ConfigStoreClient.Update),
+		newConfigNameCommand("delete", "Delete a configuration", cpb.ConfigStoreClient.Delete),
+		newConfigNameCommand("apply", "Apply a configuration to the running engine", cpb.ConfigStoreClient.Apply),
+	)
+
+	return cmd
+}
+
+type configCall func(cpb.ConfigStoreClient, context.Context, *cpb.ConfigRequest, ...grpc.CallOption) (*cpb.ConfigResponse, error)
+
+func configTable(resp *cpb.ConfigResponse) *table {
+	if len(resp.GetConfig()) == 0 {
+		t := &table{headers: []string{"RESULT"}}
+		t.addRow(orNone(resp.GetMsg()))
+		return t
+	}
+
+	t := &table{headers: []string{"NAME", "STATUS", "DB", "NETWORK MODE", "SYSTEM MODE"}}
+	for _, c := range resp.GetConfig() {
+		t.addRow(
+			c.GetConfigName(),
+			strconv.Itoa(int(c.GetStatus())),
+			orNone(c.GetConfigDb().GetDbDriver()),
+			strconv.Itoa(int(c.GetConfigNetworkPolicy().GetOperationMode())),
+			strconv.Itoa(int(c.GetConfigSystemPolicy().GetOperationMode())),
+		)
+	}
+	return t
+}
+
+func runConfigCall(cmd *cobra.Command, call configCall, req *cpb.ConfigRequest) error {
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := unaryContext(cmd.Context())
+	defer cancel()
+
+	resp, err := call(cpb.NewConfigStoreClient(conn), ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return printMessage(cmd.OutOrStdout(), opts.output, resp, func() *table { return configTable(resp) })
+}
+
+func newConfigGetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get [NAME]",
+		Short: "Show a configuration, or all the configurations",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &cpb.ConfigRequest{}
+			if len(args) > 0 {
+				req.ConfigName = args[0]
+			}
+			return runConfigCall(cmd, cpb.ConfigStoreClient.Get, req)
+		},
+	}
+}
+
+// readConfigFile reads a configuration in the json or yaml layout of the config proto
+func readConfigFile(path string) (*cpb.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// json is valid yaml, so both are converted the same way
+	data, err = yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &cpb.Config{}
+	if err := protojson.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func newConfigWriteCommand(use, short string, call configCall) *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   use + " [NAME] -f FILE",
+		Short: short,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := readConfigFile(file)
+			if err != nil {
+				return err
+			}
+
+			req := &cpb.ConfigRequest{Config: config}
+			if len(args) > 0 {
+				req.ConfigName = args[0]
+			}
+
+			return runConfigCall(cmd, call, req)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "configuration file")
+	_ = cmd.MarkFlagRequired("file")
+	_ = cmd.MarkFlagFilename("file", "yaml", "yml", "json")
+
+	return cmd
+}
+
+func newConfigNameCommand(use, short string, call configCall) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " NAME",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigCall(cmd, call, &cpb.ConfigRequest{ConfigName: args[0]})
+		},
+	}
+}
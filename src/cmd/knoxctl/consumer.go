@@ -0,0 +1,73 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	fpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/consumer"
+)
+
+// ====================== //
+// == Consumer Command == //
+// ====================== //
+
+func newConsumerCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "consumer",
+		Short: "Start and stop the feed consumer",
+	}
+
+	calls := []struct {
+		use   string
+		short string
+		call  func(c fpb.ConsumerClient, cmd *cobra.Command, req *fpb.ConsumerRequest) (*fpb.ConsumerResponse, error)
+	}{
+		{"start", "Start the feed consumer", func(c fpb.ConsumerClient, cmd *cobra.Command, req *fpb.ConsumerRequest) (*fpb.ConsumerResponse, error) {
+			ctx, cancel := unaryContext(cmd.Context())
+			defer cancel()
+			return c.Start(ctx, req)
+		}},
+		{"stop", "Stop the feed consumer", func(c fpb.ConsumerClient, cmd *cobra.Command, req *fpb.ConsumerRequest) (*fpb.ConsumerResponse, error) {
+			ctx, cancel := unaryContext(cmd.Context())
+			defer cancel()
+			return c.Stop(ctx, req)
+		}},
+		{"status", "Show the status of the feed consumer", func(c fpb.ConsumerClient, cmd *cobra.Command, req *fpb.ConsumerRequest) (*fpb.ConsumerResponse, error) {
+			ctx, cancel := unaryContext(cmd.Context())
+			defer cancel()
+			return c.GetConsumerStatus(ctx, req)
+		}},
+	}
+
+	for _, c := range calls {
+		call := c.call
+		sub := &cobra.Command{
+			Use:   c.use,
+			Short: c.short,
+			Args:  cobra.NoArgs,
+		}
+		feedType := sub.Flags().String("feed-type", "", "type of the feed")
+
+		sub.RunE = func(cmd *cobra.Command, args []string) error {
+			conn, err := connect()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			resp, err := call(fpb.NewConsumerClient(conn), cmd, &fpb.ConsumerRequest{Feedtype: *feedType})
+			if err != nil {
+				return err
+			}
+
+			return printMessage(cmd.OutOrStdout(), opts.output, resp, func() *table {
+				t := &table{headers: []string{"RESULT"}}
+				t.addRow(orNone(resp.GetRes()))
+				return t
+			})
+		}
+
+		cmd.AddCommand(sub)
+	}
+
+	return cmd
+}
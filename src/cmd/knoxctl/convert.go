@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	wpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/worker"
+)
+
+// ================== //
+// == Policy Files == //
+// ================== //
+
+// policyDocument is a converted policy with the metadata used to name its file
+type policyDocument struct {
+	Kind      string
+	Namespace string
+	Name      string
+	JSON      []byte
+}
+
+func newPolicyDocument(data []byte) (policyDocument, error) {
+	meta := struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+	}{}
+
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return policyDocument{}, err
+	}
+
+	return policyDocument{
+		Kind:      meta.Kind,
+		Namespace: meta.Metadata.Namespace,
+		Name:      meta.Metadata.Name,
+		JSON:      data,
+	}, nil
+}
+
+// policyFileName names the file of a policy, kind_namespace_name.yaml
+func policyFileName(kind, namespace, name string) string {
+	parts := []string{strings.ToLower(kind)}
+	if namespace != "" {
+		parts = append(parts, namespace)
+	}
+	parts = append(parts, name)
+
+	// the names come from the cluster, keep them from escaping the directory
+	fileName := strings.ReplaceAll(strings.Join(parts, "_"), string(filepath.Separator), "-")
+	return fileName + ".yaml"
+}
+
+// writePolicyFile writes the yaml of a policy to the directory and returns the file path
+func writePolicyFile(dir, kind, namespace, name string, yamlData []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, policyFileName(kind, namespace, name))
+	if err := os.WriteFile(path, yamlData, 0600); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// writePolicyDocuments prints the policies as a multi-document yaml
+func writePolicyDocuments(w io.Writer, docs []policyDocument) error {
+	for i, doc := range docs {
+		data, err := yaml.JSONToYAML(doc.JSON)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(w, "---")
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ===================== //
+// == Convert Command == //
+// ===================== //
+
+// convertTargets maps the target formats to the policy type of the Convert call
+var convertTargets = map[string]string{
+	"cilium":    "network-cilium",
+	"k8s":       "network-generic",
+	"calico":    "network-calico",
+	"istio":     "network-istio",
+	"admin":     "network-admin",
+	"kubearmor": "system",
+}
+
+func convertedPolicies(resp *wpb.WorkerResponse) []*wpb.Policy {
+	policies := []*wpb.Policy{}
+	policies = append(policies, resp.GetCiliumpolicy()...)
+	policies = append(policies, resp.GetK8SNetworkpolicy()...)
+	policies = append(policies, resp.GetCalicopolicy()...)
+	policies = append(policies, resp.GetIstiopolicy()...)
+	policies = append(policies, resp.GetAdminnetworkpolicy()...)
+	policies = append(policies, resp.GetKubearmorpolicy()...)
+	return policies
+}
+
+func newConvertCommand() *cobra.Command {
+	req := &wpb.WorkerRequest{}
+	var outDir string
+
+	cmd := &cobra.Command{
+		Use:   "convert cilium|k8s|calico|istio|admin|kubearmor",
+		Short: "Convert the discovered policies to Cilium, Kubernetes, Calico, Istio or KubeArmor YAML",
+		Long: "Convert the discovered policies to Cilium, Kubernetes, Calico, Istio or KubeArmor YAML.\n" +
+			"The admin target proposes cluster wide AdminNetworkPolicies and a BaselineAdminNetworkPolicy\n" +
+			"of the rules common to the namespaces, it ignores the namespace flag.",
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: []string{"cilium", "k8s", "calico", "istio", "admin", "kubearmor"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req.Policytype = convertTargets[args[0]]
+
+			conn, err := connect()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			ctx, cancel := unaryContext(cmd.Context())
+			defer cancel()
+
+			resp, err := wpb.NewWorkerClient(conn).Convert(ctx, req)
+			if err != nil {
+				return err
+			}
+
+			docs := []policyDocument{}
+			for _, policy := range convertedPolicies(resp) {
+				doc, err := newPolicyDocument(policy.GetData())
+				if err != nil {
+					return err
+				}
+				docs = append(docs, doc)
+			}
+
+			// the policies go to stdout, so they can be piped, the report to stderr
+			for _, rule := range resp.GetUnsupportedRules() {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s/%s %s rule [%s] not converted: %s\n",
+					rule.GetNamespace(), rule.GetPolicyName(), orNone(rule.GetDirection()), rule.GetRule(), rule.GetReason())
+			}
+
+			return printPolicyDocuments(cmd.OutOrStdout(), docs, outDir)
+		},
+	}
+
+	cmd.Flags().StringVar(&req.Clustername, "cluster", "", "cluster name")
+	cmd.Flags().StringVarP(&req.Namespace, "namespace", "n", "", "namespace")
+	cmd.Flags().StringVarP(&req.Labels, "labels", "l", "", "labels of the kubearmor policies")
+	cmd.Flags().StringVar(&req.Fromsource, "from-source", "", "binary path of the kubearmor policies")
+	cmd.Flags().StringVarP(&outDir, "out-dir", "d", "", "write one yaml file per policy to the directory")
+
+	_ = cmd.MarkFlagDirname("out-dir")
+
+	return cmd
+}
+
+// printPolicyDocuments writes the policies to the directory, or prints them in the requested format
+func printPolicyDocuments(w io.Writer, docs []policyDocument, outDir string) error {
+	t := &table{headers: []string{"KIND", "NAMESPACE", "NAME"}}
+
+	if outDir != "" {
+		t.headers = append(t.headers, "FILE")
+		for _, doc := range docs {
+			data, err := yaml.JSONToYAML(doc.JSON)
+			if err != nil {
+				return err
+			}
+			path, err := writePolicyFile(outDir, doc.Kind, doc.Namespace, doc.Name, data)
+			if err != nil {
+				return err
+			}
+			t.addRow(doc.Kind, orNone(doc.Namespace), doc.Name, path)
+		}
+		return t.write(w)
+	}
+
+	switch opts.output {
+	case OutputYAML:
+		return writePolicyDocuments(w, docs)
+	case OutputJSON:
+		raw := []json.RawMessage{}
+		for _, doc := range docs {
+			raw = append(raw, doc.JSON)
+		}
+		data, err := json.MarshalIndent(raw, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	for _, doc := range docs {
+		t.addRow(doc.Kind, orNone(doc.Namespace), doc.Name)
+	}
+	return t.write(w)
+}
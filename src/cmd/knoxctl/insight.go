@@ -0,0 +1,128 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	ipb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/insight"
+)
+
+// ===================== //
+// == Insight Command == //
+// ===================== //
+
+// insightTable summarizes each workload with the number of entries the insight found
+func insightTable(resp *ipb.Response) *table {
+	t := &table{headers: []string{"CLUSTER", "NAMESPACE", "LABELS", "SOURCE", "CONTAINER", "PROCESSES", "FILES", "PROTOCOLS", "INGRESS", "EGRESS"}}
+
+	for _, res := range resp.GetRes() {
+		for _, sys := range res.GetSystemResource() {
+			for _, data := range sys.GetSysResource() {
+				t.addRow(orNone(sys.GetClusterName()), orNone(sys.GetNamespace()), orNone(sys.GetLabels()), "system",
+					orNone(sys.GetContainerName()),
+					strconv.Itoa(len(data.GetProcessPaths())),
+					strconv.Itoa(len(data.GetFilePaths())),
+					strconv.Itoa(len(data.GetNetworkProtocol())),
+					"-", "-")
+			}
+		}
+
+		for _, net := range res.GetNetworkResource() {
+			for _, data := range net.GetNetResource() {
+				t.addRow(orNone(net.GetClusterName()), orNone(net.GetNamespace()), orNone(data.GetLabels()), "network",
+					"-", "-", "-", "-",
+					strconv.Itoa(len(data.GetIngressess())),
+					strconv.Itoa(len(data.GetEgressess())))
+			}
+		}
+	}
+
+	return t
+}
+
+func newInsightCommand() *cobra.Command {
+	req := &ipb.Request{}
+
+	cmd := &cobra.Command{
+		Use:   "insight",
+		Short: "Show what was observed for the workloads",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// dbclear has its own command
+			req.Request = "observe"
+
+			conn, err := connect()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			ctx, cancel := unaryContext(cmd.Context())
+			defer cancel()
+
+			resp, err := ipb.NewInsightClient(conn).GetInsightData(ctx, req)
+			if err != nil {
+				return err
+			}
+
+			return printMessage(cmd.OutOrStdout(), opts.output, resp, func() *table { return insightTable(resp) })
+		},
+	}
+
+	addInsightFlags(cmd, req)
+	cmd.AddCommand(newInsightClearCommand())
+
+	return cmd
+}
+
+func addInsightFlags(cmd *cobra.Command, req *ipb.Request) {
+	cmd.Flags().StringVar(&req.Source, "source", "all", "source of the insight: system, network or all")
+	cmd.Flags().StringVar(&req.ClusterName, "cluster", "", "cluster name")
+	cmd.Flags().StringVarP(&req.Namespace, "namespace", "n", "", "namespace")
+	cmd.Flags().StringVar(&req.ContainerName, "container", "", "container name")
+	cmd.Flags().StringVarP(&req.Labels, "labels", "l", "", "labels, for example app=mysql,tier=db")
+	cmd.Flags().StringVar(&req.FromSource, "from-source", "", "binary path the system insight is restricted to")
+	cmd.Flags().StringVar(&req.Type, "type", "", "network traffic direction: ingress or egress")
+	cmd.Flags().StringVar(&req.Rule, "rule", "", "network rule type, for example matchLabels or toCIDRs")
+
+	_ = cmd.RegisterFlagCompletionFunc("source", completeValues("system", "network", "all"))
+	_ = cmd.RegisterFlagCompletionFunc("type", completeValues("ingress", "egress"))
+}
+
+func newInsightClearCommand() *cobra.Command {
+	req := &ipb.Request{}
+
+	cmd := &cobra.Command{
+		Use:   "dbclear",
+		Short: "Clear the observed data matching the filters",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req.Request = "dbclear"
+
+			conn, err := connect()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			ctx, cancel := unaryContext(cmd.Context())
+			defer cancel()
+
+			resp, err := ipb.NewInsightClient(conn).GetInsightData(ctx, req)
+			if err != nil {
+				return err
+			}
+
+			return printMessage(cmd.OutOrStdout(), opts.output, resp, func() *table {
+				t := &table{headers: []string{"RESULT"}}
+				t.addRow("cleared")
+				return t
+			})
+		},
+	}
+
+	addInsightFlags(cmd, req)
+
+	return cmd
+}
@@ -0,0 +1,98 @@
+// knoxctl is the command-line client of the discovery engine
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// ============= //
+// == Options == //
+// ============= //
+
+// globalOptions holds the flags shared by all the commands
+type globalOptions struct {
+	server             string
+	token              string
+	tls                bool
+	caFile             string
+	certFile           string
+	keyFile            string
+	insecureSkipVerify bool
+	timeout            time.Duration
+	output             string
+}
+
+var opts globalOptions
+
+func envOrDefault(key, def string) string {
+	if val, ok := os.LookupEnv(key); ok && val != "" {
+		return val
+	}
+	return def
+}
+
+// completeValues completes a flag or an argument from a fixed set of values
+func completeValues(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// ================== //
+// == Root Command == //
+// ================== //
+
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "knoxctl",
+		Short:         "Command-line client of the discovery engine",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateOutput(opts.output)
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVarP(&opts.server, "server", "s", envOrDefault("KNOXCTL_SERVER", "localhost:9089"), "address of the discovery engine [$KNOXCTL_SERVER]")
+	flags.StringVar(&opts.token, "token", os.Getenv("KNOXCTL_TOKEN"), "bearer token sent with each call [$KNOXCTL_TOKEN]")
+	flags.BoolVar(&opts.tls, "tls", false, "connect over tls")
+	flags.StringVar(&opts.caFile, "ca-file", "", "ca certificate to verify the server, implies --tls")
+	flags.StringVar(&opts.certFile, "cert-file", "", "client certificate for mutual tls, implies --tls")
+	flags.StringVar(&opts.keyFile, "key-file", "", "client key for mutual tls")
+	flags.BoolVar(&opts.insecureSkipVerify, "insecure-skip-verify", false, "skip the verification of the server certificate")
+	flags.DurationVar(&opts.timeout, "timeout", 30*time.Second, "timeout of each unary call")
+	flags.StringVarP(&opts.output, "output", "o", OutputTable, "output format: table, json or yaml")
+
+	_ = root.RegisterFlagCompletionFunc("output", completeValues(OutputTable, OutputJSON, OutputYAML))
+
+	root.AddCommand(
+		newWorkerCommand(),
+		newConsumerCommand(),
+		newPolicyCommand(),
+		newConvertCommand(),
+		newInsightCommand(),
+		newObserveCommand(),
+		newConfigCommand(),
+	)
+
+	return root
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := newRootCommand().ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		stop()
+		os.Exit(1)
+	}
+}
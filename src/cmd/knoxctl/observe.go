@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+
+	opb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/observability"
+	ppb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/publisher"
+)
+
+// ===================== //
+// == Observe Command == //
+// ===================== //
+
+func newObserveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "observe",
+		Short: "Show the observability summaries of the pods",
+	}
+
+	cmd.AddCommand(
+		newObserveSummaryCommand(),
+		newObservePodsCommand(),
+		newObserveStreamCommand(),
+	)
+
+	return cmd
+}
+
+func addObservabilityFlags(cmd *cobra.Command, req *opb.Request) {
+	cmd.Flags().StringVar(&req.ClusterName, "cluster", "", "cluster name")
+	cmd.Flags().StringVarP(&req.NameSpace, "namespace", "n", "", "namespace")
+	cmd.Flags().StringVar(&req.PodName, "pod", "", "pod name")
+	cmd.Flags().StringVarP(&req.Label, "labels", "l", "", "labels, for example app=mysql,tier=db")
+	cmd.Flags().StringVar(&req.ContainerName, "container", "", "container name")
+}
+
+// summaryTable flattens the process, file and network summaries into one table
+func summaryTable(resp *opb.Response) *table {
+	t := &table{headers: []string{"TYPE", "SOURCE", "TARGET", "PROTOCOL", "PORT", "COUNT", "STATUS", "UPDATED"}}
+
+	for _, data := range resp.GetProcessData() {
+		t.addRow("process", orNone(data.GetParentProcName()), data.GetProcName(), "-", "-", data.GetCount(), orNone(data.GetStatus()), orNone(data.GetUpdatedTime()))
+	}
+	for _, data := range resp.GetFileData() {
+		t.addRow("file", orNone(data.GetParentProcName()), data.GetProcName(), "-", "-", data.GetCount(), orNone(data.GetStatus()), orNone(data.GetUpdatedTime()))
+	}
+	for _, data := range resp.GetInNwData() {
+		t.addRow("network-in", orNone(data.GetIP()), orNone(data.GetCommand()), data.GetProtocol(), orNone(data.GetPort()), data.GetCount(), "-", orNone(data.GetUpdatedTime()))
+	}
+	for _, data := range resp.GetOutNwData() {
+		t.addRow("network-out", orNone(data.GetCommand()), orNone(data.GetIP()), data.GetProtocol(), orNone(data.GetPort()), data.GetCount(), "-", orNone(data.GetUpdatedTime()))
+	}
+	for _, data := range resp.GetIngressData() {
+		t.addRow("ingress", orNone(data.GetSrcPod()), orNone(data.GetDestPod()), data.GetProtocol(), orNone(data.GetPort()), data.GetCount(), orNone(data.GetStatus()), orNone(data.GetUpdatedTime()))
+	}
+	for _, data := range resp.GetEgressData() {
+		t.addRow("egress", orNone(data.GetSrcPod()), orNone(data.GetDestPod()), data.GetProtocol(), orNone(data.GetPort()), data.GetCount(), orNone(data.GetStatus()), orNone(data.GetUpdatedTime()))
+	}
+
+	return t
+}
+
+func newObserveSummaryCommand() *cobra.Command {
+	req := &opb.Request{}
+
+	cmd := &cobra.Command{
+		Use:   "summary",
+		Short: "Show the process, file and network summary of a pod",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := connect()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			ctx, cancel := unaryContext(cmd.Context())
+			defer cancel()
+
+			resp, err := opb.NewObservabilityClient(conn).Summary(ctx, req)
+			if err != nil {
+				return err
+			}
+
+			return printMessage(cmd.OutOrStdout(), opts.output, resp, func() *table { return summaryTable(resp) })
+		},
+	}
+
+	addObservabilityFlags(cmd, req)
+	cmd.Flags().StringVar(&req.Type, "type", "process,file,network,ingress,egress", "comma separated summaries: process, file, network, ingress, egress")
+	cmd.Flags().BoolVar(&req.Aggregate, "aggregate", false, "aggregate the file paths")
+
+	return cmd
+}
+
+func newObservePodsCommand() *cobra.Command {
+	req := &opb.Request{}
+
+	cmd := &cobra.Command{
+		Use:   "pods",
+		Short: "List the pods with observability data",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := connect()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			ctx, cancel := unaryContext(cmd.Context())
+			defer cancel()
+
+			resp, err := opb.NewObservabilityClient(conn).GetPodNames(ctx, req)
+			if err != nil {
+				return err
+			}
+
+			return printMessage(cmd.OutOrStdout(), opts.output, resp, func() *table {
+				t := &table{headers: []string{"POD"}}
+				for _, pod := range resp.GetPodName() {
+					t.addRow(pod)
+				}
+				return t
+			})
+		},
+	}
+
+	addObservabilityFlags(cmd, req)
+
+	return cmd
+}
+
+func newObserveStreamCommand() *cobra.Command {
+	req := &ppb.SummaryRequest{}
+
+	cmd := &cobra.Command{
+		Use:   "stream",
+		Short: "Stream the summary events as they are published",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := connect()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			stream, err := ppb.NewPublisherClient(conn).GetSummary(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+
+			printer := &streamPrinter{
+				w:       cmd.OutOrStdout(),
+				format:  opts.output,
+				headers: []string{"NAMESPACE", "POD", "OPERATION", "SOURCE", "DESTINATION", "PROTOCOL", "PORT", "ACTION", "COUNT"},
+				toRow: func(msg proto.Message) []string {
+					event := msg.(*ppb.SummaryResponse)
+					return []string{
+						event.GetNamespaceName(), event.GetPodName(), event.GetOperation(),
+						orNone(event.GetSource()), orNone(event.GetDestination()), orNone(event.GetProtocol()),
+						strconv.Itoa(int(event.GetPort())), orNone(event.GetAction()), strconv.Itoa(int(event.GetCount())),
+					}
+				},
+			}
+
+			for {
+				event, err := stream.Recv()
+				if errors.Is(err, io.EOF) || cmd.Context().Err() != nil {
+					return nil
+				} else if err != nil {
+					return err
+				}
+				if err := printer.print(event); err != nil {
+					return err
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&req.ClusterName, "cluster", "", "cluster name")
+	cmd.Flags().StringVarP(&req.Namespace, "namespace", "n", "", "namespace")
+	cmd.Flags().StringVar(&req.PodName, "pod", "", "pod name")
+	cmd.Flags().StringVarP(&req.Labels, "labels", "l", "", "labels, for example app=mysql,tier=db")
+	cmd.Flags().StringVar(&req.DeploymentName, "deployment", "", "deployment name")
+	cmd.Flags().StringVar(&req.Operation, "operation", "", "operation: process, file or network")
+
+	return cmd
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/yaml"
+)
+
+// ==================== //
+// == Output Formats == //
+// ==================== //
+
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+func validateOutput(format string) error {
+	switch format {
+	case OutputTable, OutputJSON, OutputYAML:
+		return nil
+	}
+	return fmt.Errorf("unknown output format [%s], choose table, json or yaml", format)
+}
+
+// table is the tabular view of a response
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+func (t *table) addRow(values ...string) {
+	t.rows = append(t.rows, values)
+}
+
+func (t *table) write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.headers, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// printMessage prints a response in the requested format, the table is only built for the table output
+func printMessage(w io.Writer, format string, msg proto.Message, toTable func() *table) error {
+	switch format {
+	case OutputJSON:
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		// protojson randomizes its whitespace, the indentation is done here to keep the output stable
+		indented := &bytes.Buffer{}
+		if err := json.Indent(indented, data, "", "  "); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, indented.String())
+		return err
+	case OutputYAML:
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		data, err = yaml.JSONToYAML(data)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	return toTable().write(w)
+}
+
+// streamPrinter prints the messages of a stream as they arrive
+type streamPrinter struct {
+	w       io.Writer
+	format  string
+	headers []string
+	toRow   func(proto.Message) []string
+	count   int
+}
+
+func (p *streamPrinter) print(msg proto.Message) error {
+	defer func() { p.count++ }()
+
+	switch p.format {
+	case OutputJSON:
+		// one object per line, so the output can be piped to line-oriented tools
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.w, string(data))
+		return err
+	case OutputYAML:
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		data, err = yaml.JSONToYAML(data)
+		if err != nil {
+			return err
+		}
+		if p.count > 0 {
+			fmt.Fprintln(p.w, "---")
+		}
+		_, err = p.w.Write(data)
+		return err
+	}
+
+	// the rows are flushed one by one, a wide min width keeps the columns mostly aligned
+	tw := tabwriter.NewWriter(p.w, 16, 8, 2, ' ', 0)
+	if p.count == 0 {
+		fmt.Fprintln(tw, strings.Join(p.headers, "\t"))
+	}
+	fmt.Fprintln(tw, strings.Join(p.toRow(msg), "\t"))
+	return tw.Flush()
+}
+
+// ============= //
+// == Helpers == //
+// ============= //
+
+func orNone(val string) string {
+	if val == "" {
+		return "-"
+	}
+	return val
+}
+
+func joinOrNone(vals []string) string {
+	return orNone(strings.Join(vals, ","))
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	wpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/worker"
+)
+
+func TestValidateOutput(t *testing.T) {
+	assert.NoError(t, validateOutput(OutputTable))
+	assert.NoError(t, validateOutput(OutputJSON))
+	assert.NoError(t, validateOutput(OutputYAML))
+	assert.Error(t, validateOutput("xml"))
+}
+
+func TestPrintMessage(t *testing.T) {
+	run := &wpb.DiscoveryRun{Id: 7, Policytype: "network", Trigger: "cron", Status: "completed", PoliciesCreated: 3}
+	toTable := func() *table { return runsTable([]*wpb.DiscoveryRun{run}) }
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printMessage(out, OutputTable, run, toTable))
+	assert.Contains(t, out.String(), "ID")
+	assert.Contains(t, out.String(), "completed")
+
+	out.Reset()
+	assert.NoError(t, printMessage(out, OutputJSON, run, toTable))
+	assert.Contains(t, out.String(), `"policies_created": "3"`)
+
+	out.Reset()
+	assert.NoError(t, printMessage(out, OutputYAML, run, toTable))
+	assert.Contains(t, out.String(), "policytype: network\n")
+}
+
+func TestStreamPrinterYAML(t *testing.T) {
+	out := &bytes.Buffer{}
+	printer := &streamPrinter{w: out, format: OutputYAML}
+
+	assert.NoError(t, printer.print(&wpb.DiscoveryRun{Id: 1}))
+	assert.NoError(t, printer.print(&wpb.DiscoveryRun{Id: 2}))
+	assert.Equal(t, "id: \"1\"\n---\nid: \"2\"\n", out.String())
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+
+	dpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/discovery"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// reconnectInterval is the wait before a watch reconnects after the stream broke
+const reconnectInterval = 2 * time.Second
+
+var policyKinds = []string{
+	types.KindCiliumNetworkPolicy,
+	types.KindCiliumClusterwideNetworkPolicy,
+	types.KindCalicoNetworkPolicy,
+	types.KindCalicoGlobalNetworkPolicy,
+	types.KindIstioAuthorizationPolicy,
+	types.KindKubeArmorPolicy,
+	types.KindKubeArmorHostPolicy,
+}
+
+// ==================== //
+// == Policy Command == //
+// ==================== //
+
+func newPolicyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Get and watch the discovered policies",
+	}
+
+	cmd.AddCommand(
+		newPolicyGetCommand(),
+		newPolicyWatchCommand(),
+	)
+
+	return cmd
+}
+
+func addPolicyFlags(cmd *cobra.Command, req *dpb.GetPolicyRequest, outDir *string) {
+	cmd.Flags().StringSliceVar(&req.Kind, "kind", nil, "policy kinds, all the kinds if empty")
+	cmd.Flags().StringVar(&req.Cluster, "cluster", "", "cluster name")
+	cmd.Flags().StringVarP(&req.Namespace, "namespace", "n", "", "namespace")
+	cmd.Flags().StringSliceVarP(&req.Label, "labels", "l", nil, "labels of the policies, for example app=mysql")
+	cmd.Flags().StringVarP(outDir, "out-dir", "d", "", "keep one yaml file per live policy in the directory")
+
+	_ = cmd.RegisterFlagCompletionFunc("kind", completeValues(policyKinds...))
+	_ = cmd.MarkFlagDirname("out-dir")
+}
+
+// policyEventWriter prints the policy events and mirrors them in the output directory
+type policyEventWriter struct {
+	printer *streamPrinter
+	outDir  string
+}
+
+func newPolicyEventWriter(w io.Writer, outDir string) *policyEventWriter {
+	return &policyEventWriter{
+		outDir: outDir,
+		printer: &streamPrinter{
+			w:       w,
+			format:  opts.output,
+			headers: []string{"REVISION", "EVENT", "KIND", "NAMESPACE", "NAME", "REPLACES", "REPLACED BY"},
+			toRow: func(msg proto.Message) []string {
+				policy := msg.(*dpb.GetPolicyResponse)
+				return []string{
+					strconv.FormatInt(policy.GetRevision(), 10), orNone(policy.GetEvent()), policy.GetKind(),
+					orNone(policy.GetNamespace()), policy.GetName(), orNone(policy.GetReplaces()), orNone(policy.GetReplacedBy()),
+				}
+			},
+		},
+	}
+}
+
+func (pw *policyEventWriter) write(policy *dpb.GetPolicyResponse) error {
+	if pw.outDir != "" {
+		live := policy.GetEvent() != types.PolicyEventOutdated && policy.GetEvent() != types.PolicyEventDeleted
+
+		if live {
+			if _, err := writePolicyFile(pw.outDir, policy.GetKind(), policy.GetNamespace(), policy.GetName(), policy.GetYaml()); err != nil {
+				return err
+			}
+		} else {
+			path := filepath.Join(pw.outDir, policyFileName(policy.GetKind(), policy.GetNamespace(), policy.GetName()))
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	if opts.output == OutputYAML {
+		// the policy itself rather than its envelope, so the output can be applied
+		if pw.printer.count > 0 {
+			fmt.Fprintln(pw.printer.w, "---")
+		}
+		pw.printer.count++
+		fmt.Fprintf(pw.printer.w, "# revision: %d, event: %s\n", policy.GetRevision(), orNone(policy.GetEvent()))
+		_, err := pw.printer.w.Write(policy.GetYaml())
+		return err
+	}
+
+	return pw.printer.print(policy)
+}
+
+// receivePolicies writes the policies of the stream until it ends, and returns the last revision received
+func receivePolicies(stream dpb.Discovery_GetPolicyClient, pw *policyEventWriter, revision int64) (int64, error) {
+	for {
+		policy, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return revision, nil
+		} else if err != nil {
+			return revision, err
+		}
+
+		if policy.GetRevision() > revision {
+			revision = policy.GetRevision()
+		}
+
+		// the server dropped policies of the stream, and ends it
+		if policy.GetEvent() == types.PolicyEventResync {
+			return revision, errPolicyResync
+		}
+
+		if err := pw.write(policy); err != nil {
+			return revision, outputError{err}
+		}
+	}
+}
+
+// errPolicyResync is returned when the server ends the stream since its queue dropped policies
+var errPolicyResync = errors.New("the server dropped policies of the stream")
+
+// outputError is a failure to write a policy locally, a reconnection does not fix it
+type outputError struct {
+	err error
+}
+
+func (e outputError) Error() string {
+	return e.err.Error()
+}
+
+func newPolicyGetCommand() *cobra.Command {
+	req := &dpb.GetPolicyRequest{}
+	var outDir string
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get the live discovered policies",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := connect()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			ctx, cancel := unaryContext(cmd.Context())
+			defer cancel()
+
+			stream, err := dpb.NewDiscoveryClient(conn).GetPolicy(ctx, req)
+			if err != nil {
+				return err
+			}
+
+			_, err = receivePolicies(stream, newPolicyEventWriter(cmd.OutOrStdout(), outDir), 0)
+			return err
+		},
+	}
+
+	addPolicyFlags(cmd, req, &outDir)
+
+	return cmd
+}
+
+func newPolicyWatchCommand() *cobra.Command {
+	req := &dpb.GetPolicyRequest{Follow: true}
+	var outDir string
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Watch the discovered policies, reconnecting from the last revision received",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := connect()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			client := dpb.NewDiscoveryClient(conn)
+			pw := newPolicyEventWriter(cmd.OutOrStdout(), outDir)
+			revision := req.ResumeRevision
+
+			for {
+				req.ResumeRevision = revision
+
+				stream, err := client.GetPolicy(cmd.Context(), req)
+				if err == nil {
+					revision, err = receivePolicies(stream, pw, revision)
+				}
+
+				if cmd.Context().Err() != nil {
+					return nil
+				}
+				if errors.As(err, &outputError{}) {
+					return err
+				} else if err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "watch interrupted: %v, resuming from revision %d\n", err, revision)
+				}
+
+				select {
+				case <-cmd.Context().Done():
+					return nil
+				case <-time.After(reconnectInterval):
+				}
+			}
+		},
+	}
+
+	addPolicyFlags(cmd, req, &outDir)
+	cmd.Flags().Int64Var(&req.ResumeRevision, "resume-revision", 0, "only the policy events after the revision, 0 for the live policies first")
+
+	return cmd
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	dpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/discovery"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+func TestPolicyFileName(t *testing.T) {
+	assert.Equal(t, "ciliumnetworkpolicy_default_p1.yaml", policyFileName(types.KindCiliumNetworkPolicy, "default", "p1"))
+	assert.Equal(t, "kubearmorhostpolicy_p1.yaml", policyFileName(types.KindKubeArmorHostPolicy, "", "p1"))
+	assert.Equal(t, "kubearmorpolicy_default_..-p1.yaml", policyFileName(types.KindKubeArmorPolicy, "default", "../p1"))
+}
+
+func TestPolicyEventWriterOutDir(t *testing.T) {
+	opts.output = OutputTable
+	dir := t.TempDir()
+	out := &bytes.Buffer{}
+	pw := newPolicyEventWriter(out, dir)
+	path := filepath.Join(dir, "ciliumnetworkpolicy_default_p1.yaml")
+
+	policy := &dpb.GetPolicyResponse{Kind: types.KindCiliumNetworkPolicy, Namespace: "default", Name: "p1",
+		Yaml: []byte("kind: CiliumNetworkPolicy\n"), Revision: 1, Event: types.PolicyEventAdded}
+	assert.NoError(t, pw.write(policy))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "kind: CiliumNetworkPolicy\n", string(data))
+
+	// the outdated policy is removed from the directory
+	policy = &dpb.GetPolicyResponse{Kind: types.KindCiliumNetworkPolicy, Namespace: "default", Name: "p1",
+		Revision: 2, Event: types.PolicyEventOutdated, ReplacedBy: "p2"}
+	assert.NoError(t, pw.write(policy))
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Contains(t, out.String(), "REVISION")
+	assert.Contains(t, out.String(), types.PolicyEventOutdated)
+}
+
+func TestPrintPolicyDocuments(t *testing.T) {
+	doc, err := newPolicyDocument([]byte(`{"apiVersion":"security.kubearmor.com/v1","kind":"KubeArmorPolicy","metadata":{"name":"p1","namespace":"wordpress"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", doc.Name)
+	assert.Equal(t, "wordpress", doc.Namespace)
+
+	opts.output = OutputYAML
+	defer func() { opts.output = OutputTable }()
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printPolicyDocuments(out, []policyDocument{doc, doc}, ""))
+	assert.Contains(t, out.String(), "kind: KubeArmorPolicy\n")
+	assert.Contains(t, out.String(), "---\n")
+
+	dir := t.TempDir()
+	out.Reset()
+	assert.NoError(t, printPolicyDocuments(out, []policyDocument{doc}, dir))
+	_, err = os.Stat(filepath.Join(dir, "kubearmorpolicy_wordpress_p1.yaml"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	wpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/worker"
+)
+
+var policyTypes = []string{"network", "system"}
+
+func validatePolicyType(policyType string) error {
+	for _, t := range policyTypes {
+		if t == policyType {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown policy type [%s], choose network or system", policyType)
+}
+
+func formatTime(unix int64) string {
+	if unix == 0 {
+		return "-"
+	}
+	return time.Unix(unix, 0).Format(time.RFC3339)
+}
+
+func workerResponseTable(resp *wpb.WorkerResponse) *table {
+	t := &table{headers: []string{"RESULT"}}
+	t.addRow(orNone(resp.GetRes()))
+	return t
+}
+
+// ==================== //
+// == Worker Command == //
+// ==================== //
+
+func newWorkerCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "worker",
+		Short: "Start, stop and inspect the discovery workers",
+	}
+
+	cmd.AddCommand(
+		newWorkerStartCommand(),
+		newWorkerStopCommand(),
+		newWorkerStatusCommand(),
+		newWorkerRunsCommand(),
+		newWorkerRunCommand(),
+	)
+
+	return cmd
+}
+
+// callWorker runs a unary worker call on the request of the policy type argument
+func callWorker(cmd *cobra.Command, req *wpb.WorkerRequest, call func(wpb.WorkerClient, *wpb.WorkerRequest) (*wpb.WorkerResponse, error)) error {
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := call(wpb.NewWorkerClient(conn), req)
+	if err != nil {
+		return err
+	}
+
+	return printMessage(cmd.OutOrStdout(), opts.output, resp, func() *table { return workerResponseTable(resp) })
+}
+
+func newWorkerStartCommand() *cobra.Command {
+	var logFile string
+	var dbClear bool
+
+	cmd := &cobra.Command{
+		Use:       "start network|system",
+		Short:     "Start a discovery run",
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: policyTypes,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &wpb.WorkerRequest{Policytype: args[0], Logfile: logFile}
+			if dbClear {
+				req.Req = "dbclear"
+			}
+
+			return callWorker(cmd, req, func(c wpb.WorkerClient, req *wpb.WorkerRequest) (*wpb.WorkerResponse, error) {
+				ctx, cancel := unaryContext(cmd.Context())
+				defer cancel()
+				return c.Start(ctx, req)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&logFile, "logfile", "", "log file the worker reads the flows or events from")
+	cmd.Flags().BoolVar(&dbClear, "dbclear", false, "clear the discovered policies and logs before the run")
+
+	return cmd
+}
+
+func newWorkerStopCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:       "stop network|system",
+		Short:     "Stop the discovery worker",
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: policyTypes,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return callWorker(cmd, &wpb.WorkerRequest{Policytype: args[0]}, func(c wpb.WorkerClient, req *wpb.WorkerRequest) (*wpb.WorkerResponse, error) {
+				ctx, cancel := unaryContext(cmd.Context())
+				defer cancel()
+				return c.Stop(ctx, req)
+			})
+		},
+	}
+}
+
+func newWorkerStatusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:       "status network|system",
+		Short:     "Show the status of the discovery worker",
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: policyTypes,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return callWorker(cmd, &wpb.WorkerRequest{Policytype: args[0]}, func(c wpb.WorkerClient, req *wpb.WorkerRequest) (*wpb.WorkerResponse, error) {
+				ctx, cancel := unaryContext(cmd.Context())
+				defer cancel()
+				return c.GetWorkerStatus(ctx, req)
+			})
+		},
+	}
+}
+
+// ================== //
+// == Run Commands == //
+// ================== //
+
+func runsTable(runs []*wpb.DiscoveryRun) *table {
+	t := &table{headers: []string{"ID", "TYPE", "TRIGGER", "STATUS", "STARTED", "ENDED", "LOGS", "CREATED", "UPDATED", "ERRORS"}}
+	for _, run := range runs {
+		t.addRow(
+			strconv.FormatInt(run.GetId(), 10),
+			run.GetPolicytype(),
+			run.GetTrigger(),
+			run.GetStatus(),
+			formatTime(run.GetStartTime()),
+			formatTime(run.GetEndTime()),
+			strconv.FormatInt(run.GetLogsConsumed(), 10),
+			strconv.FormatInt(run.GetPoliciesCreated(), 10),
+			strconv.FormatInt(run.GetPoliciesUpdated(), 10),
+			strconv.Itoa(len(run.GetErrors())),
+		)
+	}
+	return t
+}
+
+func newWorkerRunsCommand() *cobra.Command {
+	req := &wpb.ListRunsRequest{}
+
+	cmd := &cobra.Command{
+		Use:   "runs",
+		Short: "List the recent discovery runs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if req.Policytype != "" {
+				if err := validatePolicyType(req.Policytype); err != nil {
+					return err
+				}
+			}
+
+			conn, err := connect()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			ctx, cancel := unaryContext(cmd.Context())
+			defer cancel()
+
+			resp, err := wpb.NewWorkerClient(conn).ListRuns(ctx, req)
+			if err != nil {
+				return err
+			}
+
+			return printMessage(cmd.OutOrStdout(), opts.output, resp, func() *table { return runsTable(resp.GetRuns()) })
+		},
+	}
+
+	cmd.Flags().StringVar(&req.Policytype, "type", "", "only the runs of the policy type: network or system")
+	cmd.Flags().StringVar(&req.Trigger, "trigger", "", "only the runs started by the trigger: cron, onetime, rpc or shutdown")
+	cmd.Flags().StringVar(&req.Status, "status", "", "only the runs in the status: running, completed or failed")
+	cmd.Flags().Int32Var(&req.Limit, "limit", 0, "maximum number of runs, the server default if 0")
+
+	_ = cmd.RegisterFlagCompletionFunc("type", completeValues(policyTypes...))
+	_ = cmd.RegisterFlagCompletionFunc("trigger", completeValues("cron", "onetime", "rpc", "shutdown"))
+	_ = cmd.RegisterFlagCompletionFunc("status", completeValues("running", "completed", "failed"))
+
+	return cmd
+}
+
+func newWorkerRunCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run ID",
+		Short: "Show a discovery run",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid run id [%s]", args[0])
+			}
+
+			conn, err := connect()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			ctx, cancel := unaryContext(cmd.Context())
+			defer cancel()
+
+			run, err := wpb.NewWorkerClient(conn).GetRun(ctx, &wpb.GetRunRequest{Id: id})
+			if err != nil {
+				return err
+			}
+
+			return printMessage(cmd.OutOrStdout(), opts.output, run, func() *table { return runsTable([]*wpb.DiscoveryRun{run}) })
+		},
+	}
+}
@@ -1,9 +1,14 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"regexp"
+	"strings"
 
 	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/robfig/cron"
 	"github.com/spf13/viper"
 )
 
@@ -15,15 +20,17 @@ import (
 //                       all           : 3
 
 // network rule types:   matchLabels: 1
-//                       toPorts    : 2
-//                       toHTTPs    : 4
-//                       toCIDRs    : 8
-//                       toEntities : 16
-//                       toServices : 32
-//                       toFQDNs    : 64
-//                       fromCIDRs  : 128
-//                       fromEntities : 256
-//                       all        : 511
+//                       toICMPs    : 2
+//                       toPorts    : 4
+//                       toHTTPs    : 8
+//                       toCIDRs    : 16
+//                       toEntities : 32
+//                       toServices : 64
+//                       toFQDNs    : 128
+//                       fromCIDRs  : 256
+//                       fromEntities : 512
+//                       toKafkas   : 1024
+//                       all        : 2047
 
 // system policy types: process     : 1
 //                      file        : 2
@@ -40,6 +47,21 @@ var NetworkPlugIn string
 var IgnoringNetworkNamespaces []string
 var HTTPUrlThreshold int
 
+// the widest ipv4 and ipv6 supernets of the cidr aggregation, if the configuration does not set them
+const (
+	DefaultCIDRAggregationMaxPrefix   = 24
+	DefaultCIDRAggregationMaxPrefixV6 = 64
+)
+
+// the prefix lengths of the cidrs of the external addresses, if the configuration does not set them
+const (
+	DefaultCIDRBits   = 32
+	DefaultCIDRv6Bits = 128
+)
+
+// DefaultClusterDomain is the dns domain of the cluster, if the configuration does not set it
+const DefaultClusterDomain = "cluster.local"
+
 func init() {
 	IgnoringNetworkNamespaces = []string{"kube-system"}
 	HTTPUrlThreshold = 5
@@ -111,6 +133,73 @@ func LoadConfigKubeArmor() types.ConfigKubeArmorRelay {
 	return cfgKubeArmor
 }
 
+func LoadConfigTLS() types.ConfigTLS {
+	cfgTLS := types.ConfigTLS{}
+
+	cfgTLS.Enable = viper.GetBool("tls.enable")
+	cfgTLS.CertFile = viper.GetString("tls.cert-file")
+	cfgTLS.KeyFile = viper.GetString("tls.key-file")
+	cfgTLS.CAFile = viper.GetString("tls.ca-file")
+	cfgTLS.VerifyClientCert = viper.GetBool("tls.verify-client-cert")
+	cfgTLS.ReloadInterval = viper.GetString("tls.reload-interval")
+
+	return cfgTLS
+}
+
+func LoadConfigAuthz() types.ConfigAuthz {
+	cfgAuthz := types.ConfigAuthz{}
+
+	cfgAuthz.Enable = viper.GetBool("authz.enable")
+
+	// list of {name, token, subject, role}
+	if err := viper.UnmarshalKey("authz.identities", &cfgAuthz.Identities); err != nil {
+		cfgAuthz.Identities = nil
+	}
+
+	// list of {method, role}, a map is not used since viper splits and lowercases the keys
+	if err := viper.UnmarshalKey("authz.permissions", &cfgAuthz.Permissions); err != nil {
+		cfgAuthz.Permissions = nil
+	}
+
+	return cfgAuthz
+}
+
+func LoadConfigHTTPGateway() types.ConfigHTTPGateway {
+	cfgGateway := types.ConfigHTTPGateway{}
+
+	cfgGateway.Enable = viper.GetBool("http-gateway.enable")
+	cfgGateway.Port = viper.GetString("http-gateway.port")
+
+	return cfgGateway
+}
+
+func LoadConfigMetrics() types.ConfigMetrics {
+	cfgMetrics := types.ConfigMetrics{}
+
+	cfgMetrics.Enable = viper.GetBool("metrics.enable")
+	cfgMetrics.Port = viper.GetString("metrics.port")
+
+	return cfgMetrics
+}
+
+func LoadConfigShutdown() types.ConfigShutdown {
+	cfgShutdown := types.ConfigShutdown{}
+
+	cfgShutdown.Timeout = viper.GetString("shutdown.timeout")
+	cfgShutdown.FinalDiscovery = viper.GetBool("shutdown.final-discovery")
+
+	return cfgShutdown
+}
+
+func LoadConfigPolicyStream() types.ConfigPolicyStream {
+	cfgPolicyStream := types.ConfigPolicyStream{}
+
+	cfgPolicyStream.QueueSize = viper.GetInt("policy-stream.queue-size")
+	cfgPolicyStream.OverflowPolicy = viper.GetString("policy-stream.overflow-policy")
+
+	return cfgPolicyStream
+}
+
 func LoadConfigFromFile() {
 	CurrentCfg = types.Configuration{}
 
@@ -136,9 +225,37 @@ func LoadConfigFromFile() {
 		NetworkPolicyTo:  viper.GetString("application.network.network-policy-to"),
 		NetworkPolicyDir: viper.GetString("application.network.network-policy-dir"),
 
+		NetworkPolicyFormats: viper.GetStringSlice("application.network.network-policy-formats"),
+
+		AdminPolicyMinNamespaces: viper.GetInt("application.network.admin-policy-min-namespaces"),
+
 		NetPolicyTypes:     3,
-		NetPolicyRuleTypes: 1023,
-		NetPolicyCIDRBits:  32,
+		NetPolicyRuleTypes: 2047,
+		NetPolicyCIDRBits:  viper.GetInt("application.network.cidr-bits"),
+
+		NetPolicyCIDRv6Bits: viper.GetInt("application.network.cidr-v6-bits"),
+		WorldCIDRs:          viper.GetBool("application.network.world-cidrs"),
+
+		CIDRAggregationMinIPs:      viper.GetInt("application.network.cidr-aggregation.min-ips"),
+		CIDRAggregationMaxPrefix:   viper.GetInt("application.network.cidr-aggregation.max-prefix"),
+		CIDRAggregationMaxPrefixV6: viper.GetInt("application.network.cidr-aggregation.max-prefix-v6"),
+		CIDRProviderRangesFile:     viper.GetString("application.network.cidr-aggregation.provider-ranges-file"),
+
+		HTTPOpenAPISpecs: getConfigHTTPOpenAPISpecs("application.network.http-openapi-specs"),
+
+		FQDNPatternMinNames: viper.GetInt("application.network.fqdn-pattern.min-names"),
+		ClusterDomain:       viper.GetString("application.network.cluster-domain"),
+
+		HTTPRuleHost:    viper.GetBool("application.network.http-rules.host"),
+		HTTPRuleHeaders: viper.GetStringSlice("application.network.http-rules.headers"),
+		HTTPSkipErrors:  viper.GetBool("application.network.http-rules.skip-errors"),
+
+		PortRangeMinPorts: viper.GetInt("application.network.port-range.min-ports"),
+		PortRangeMaxGap:   viper.GetInt("application.network.port-range.max-gap"),
+
+		LabelPolicyInclude:       viper.GetStringSlice("application.network.label-policy.include"),
+		LabelPolicyExclude:       viper.GetStringSlice("application.network.label-policy.exclude"),
+		LabelPolicyPreferredKeys: viper.GetStringSlice("application.network.label-policy.preferred-keys"),
 
 		NetLogFilters: []types.NetworkLogFilter{},
 
@@ -203,6 +320,24 @@ func LoadConfigFromFile() {
 
 	// load kubearmor relay config
 	CurrentCfg.ConfigKubeArmorRelay = LoadConfigKubeArmor()
+
+	// load grpc server tls config
+	CurrentCfg.ConfigTLS = LoadConfigTLS()
+
+	// load grpc authorization config
+	CurrentCfg.ConfigAuthz = LoadConfigAuthz()
+
+	// load rest gateway config
+	CurrentCfg.ConfigHTTPGateway = LoadConfigHTTPGateway()
+
+	// load prometheus metrics config
+	CurrentCfg.ConfigMetrics = LoadConfigMetrics()
+
+	// load graceful shutdown config
+	CurrentCfg.ConfigShutdown = LoadConfigShutdown()
+
+	// load policy stream config
+	CurrentCfg.ConfigPolicyStream = LoadConfigPolicyStream()
 }
 
 // ============================ //
@@ -213,6 +348,154 @@ func SetLogFile(file string) {
 	CurrentCfg.ConfigNetPolicy.NetworkLogFile = file
 }
 
+// SetCurrentCfg replaces the current configuration, but keeps the server settings loaded from the file
+func SetCurrentCfg(newCfg types.Configuration) {
+	newCfg.ConfigTLS = CurrentCfg.ConfigTLS
+	newCfg.ConfigAuthz = CurrentCfg.ConfigAuthz
+	newCfg.ConfigHTTPGateway = CurrentCfg.ConfigHTTPGateway
+	newCfg.ConfigMetrics = CurrentCfg.ConfigMetrics
+	newCfg.ConfigShutdown = CurrentCfg.ConfigShutdown
+	newCfg.ConfigPolicyStream = CurrentCfg.ConfigPolicyStream
+	CurrentCfg = newCfg
+}
+
+// ============================ //
+// == Get Server Config Info == //
+// ============================ //
+
+func GetCfgTLS() types.ConfigTLS {
+	return CurrentCfg.ConfigTLS
+}
+
+func GetCfgAuthz() types.ConfigAuthz {
+	return CurrentCfg.ConfigAuthz
+}
+
+func GetCfgHTTPGateway() types.ConfigHTTPGateway {
+	return CurrentCfg.ConfigHTTPGateway
+}
+
+func GetCfgMetrics() types.ConfigMetrics {
+	return CurrentCfg.ConfigMetrics
+}
+
+func GetCfgShutdown() types.ConfigShutdown {
+	return CurrentCfg.ConfigShutdown
+}
+
+func GetCfgPolicyStream() types.ConfigPolicyStream {
+	return CurrentCfg.ConfigPolicyStream
+}
+
+// ============================== //
+// == Configuration Validation == //
+// ============================== //
+
+func validateOperation(name string, mode int, cronJobTime string) error {
+	switch mode {
+	case 0, 2: // noop, onetime job
+	case 1: // cronjob
+		if _, err := cron.Parse(cronJobTime); err != nil {
+			return fmt.Errorf("invalid %s cron job time interval [%s]: %v", name, cronJobTime, err)
+		}
+	default:
+		return fmt.Errorf("invalid %s operation mode [%d]", name, mode)
+	}
+	return nil
+}
+
+// SetConfigurationDefaults sets the settings the stored configurations written before they existed do not have
+func SetConfigurationDefaults(c *types.Configuration) {
+	if c.ConfigNetPolicy.NetPolicyCIDRBits == 0 {
+		c.ConfigNetPolicy.NetPolicyCIDRBits = DefaultCIDRBits
+	}
+	if c.ConfigNetPolicy.NetPolicyCIDRv6Bits == 0 {
+		c.ConfigNetPolicy.NetPolicyCIDRv6Bits = DefaultCIDRv6Bits
+	}
+}
+
+// ValidateConfiguration checks whether the given configuration can be applied
+func ValidateConfiguration(c types.Configuration) error {
+	if c.ConfigName == "" {
+		return errors.New("configuration name is empty")
+	}
+
+	switch c.ConfigDB.DBDriver {
+	case "mysql", "sqlite3":
+	default:
+		return fmt.Errorf("invalid db driver [%s]", c.ConfigDB.DBDriver)
+	}
+
+	// network policy discovery
+	netCfg := c.ConfigNetPolicy
+	if err := validateOperation("network", netCfg.OperationMode, netCfg.CronJobTimeInterval); err != nil {
+		return err
+	}
+	if netCfg.NetPolicyTypes < 1 || netCfg.NetPolicyTypes > 3 {
+		return fmt.Errorf("invalid network policy types [%d]", netCfg.NetPolicyTypes)
+	}
+	if netCfg.NetPolicyRuleTypes < 0 || netCfg.NetPolicyRuleTypes > 2047 {
+		return fmt.Errorf("invalid network policy rule types [%d]", netCfg.NetPolicyRuleTypes)
+	}
+	// a 0 prefix would allow all the addresses
+	if netCfg.NetPolicyCIDRBits < 1 || netCfg.NetPolicyCIDRBits > 32 {
+		return fmt.Errorf("invalid network policy cidr bits [%d]", netCfg.NetPolicyCIDRBits)
+	}
+	if netCfg.NetPolicyCIDRv6Bits < 1 || netCfg.NetPolicyCIDRv6Bits > 128 {
+		return fmt.Errorf("invalid network policy ipv6 cidr bits [%d]", netCfg.NetPolicyCIDRv6Bits)
+	}
+	if netCfg.CIDRAggregationMinIPs < 0 {
+		return fmt.Errorf("invalid cidr aggregation min ips [%d]", netCfg.CIDRAggregationMinIPs)
+	}
+	// 0 is the default prefix, the records written before the field existed
+	if netCfg.CIDRAggregationMaxPrefix != 0 && (netCfg.CIDRAggregationMaxPrefix < 8 || netCfg.CIDRAggregationMaxPrefix > 32) {
+		return fmt.Errorf("invalid cidr aggregation max prefix [%d]", netCfg.CIDRAggregationMaxPrefix)
+	}
+	if netCfg.CIDRAggregationMaxPrefixV6 != 0 && (netCfg.CIDRAggregationMaxPrefixV6 < 16 || netCfg.CIDRAggregationMaxPrefixV6 > 128) {
+		return fmt.Errorf("invalid cidr aggregation ipv6 max prefix [%d]", netCfg.CIDRAggregationMaxPrefixV6)
+	}
+	if netCfg.FQDNPatternMinNames < 0 {
+		return fmt.Errorf("invalid fqdn pattern min names [%d]", netCfg.FQDNPatternMinNames)
+	}
+	if strings.HasPrefix(netCfg.ClusterDomain, ".") || strings.HasSuffix(netCfg.ClusterDomain, ".") || strings.ContainsAny(netCfg.ClusterDomain, "* ") {
+		return fmt.Errorf("invalid cluster domain [%s]", netCfg.ClusterDomain)
+	}
+	if netCfg.PortRangeMinPorts < 0 {
+		return fmt.Errorf("invalid port range min ports [%d]", netCfg.PortRangeMinPorts)
+	}
+	if netCfg.PortRangeMaxGap < 0 || netCfg.PortRangeMaxGap > 65535 {
+		return fmt.Errorf("invalid port range max gap [%d]", netCfg.PortRangeMaxGap)
+	}
+	for _, exprs := range [][]string{netCfg.LabelPolicyInclude, netCfg.LabelPolicyExclude} {
+		for _, expr := range exprs {
+			if _, err := regexp.Compile(expr); err != nil {
+				return fmt.Errorf("invalid label policy regex [%s]: %v", expr, err)
+			}
+		}
+	}
+	for _, header := range netCfg.HTTPRuleHeaders {
+		if header == "" || strings.ContainsAny(header, " \t:") {
+			return fmt.Errorf("invalid http rule header [%s]", header)
+		}
+	}
+	for _, spec := range netCfg.HTTPOpenAPISpecs {
+		if spec.File == "" || (spec.Service == "" && len(spec.Labels) == 0) {
+			return fmt.Errorf("invalid http openapi spec [%s], a file and a service or labels are required", spec.File)
+		}
+	}
+
+	// system policy discovery
+	sysCfg := c.ConfigSysPolicy
+	if err := validateOperation("system", sysCfg.OperationMode, sysCfg.CronJobTimeInterval); err != nil {
+		return err
+	}
+	if sysCfg.SysPolicyTypes < 0 || sysCfg.SysPolicyTypes > 7 {
+		return fmt.Errorf("invalid system policy types [%d]", sysCfg.SysPolicyTypes)
+	}
+
+	return nil
+}
+
 // ============================ //
 // == Get Configuration Info == //
 // ============================ //
@@ -287,10 +570,107 @@ func GetCfgNetworkPolicyTo() string {
 	return CurrentCfg.ConfigNetPolicy.NetworkPolicyTo
 }
 
+// GetCfgNetworkPolicyFormats returns the formats the discovered network policies are stored in
+func GetCfgNetworkPolicyFormats() []string {
+	if len(CurrentCfg.ConfigNetPolicy.NetworkPolicyFormats) == 0 {
+		return []string{types.NetworkPolicyFormatCilium}
+	}
+	return CurrentCfg.ConfigNetPolicy.NetworkPolicyFormats
+}
+
+// GetCfgAdminPolicyMinNamespaces returns the number of namespaces a rule is common to, to propose an admin network policy
+func GetCfgAdminPolicyMinNamespaces() int {
+	return CurrentCfg.ConfigNetPolicy.AdminPolicyMinNamespaces
+}
+
+// GetCfgCIDRBits returns the prefix length of the ipv4 cidrs of the external addresses, the default one if it is not set
 func GetCfgCIDRBits() int {
+	if CurrentCfg.ConfigNetPolicy.NetPolicyCIDRBits == 0 {
+		return DefaultCIDRBits
+	}
 	return CurrentCfg.ConfigNetPolicy.NetPolicyCIDRBits
 }
 
+// GetCfgCIDRv6Bits returns the prefix length of the ipv6 cidrs of the external addresses, the default one if it is not set
+func GetCfgCIDRv6Bits() int {
+	if CurrentCfg.ConfigNetPolicy.NetPolicyCIDRv6Bits == 0 {
+		return DefaultCIDRv6Bits
+	}
+	return CurrentCfg.ConfigNetPolicy.NetPolicyCIDRv6Bits
+}
+
+// GetCfgWorldCIDRs returns true if the addresses out of the cluster are discovered as cidrs rather than the world entity
+func GetCfgWorldCIDRs() bool {
+	return CurrentCfg.ConfigNetPolicy.WorldCIDRs
+}
+
+// GetCfgCIDRAggregation returns the minimum addresses of a supernet and the widest ipv4 and ipv6 supernet prefixes,
+// the unset prefixes are the default ones
+func GetCfgCIDRAggregation() (int, int, int) {
+	netCfg := CurrentCfg.ConfigNetPolicy
+
+	maxPrefix, maxPrefixV6 := netCfg.CIDRAggregationMaxPrefix, netCfg.CIDRAggregationMaxPrefixV6
+	if maxPrefix == 0 {
+		maxPrefix = DefaultCIDRAggregationMaxPrefix
+	}
+	if maxPrefixV6 == 0 {
+		maxPrefixV6 = DefaultCIDRAggregationMaxPrefixV6
+	}
+
+	return netCfg.CIDRAggregationMinIPs, maxPrefix, maxPrefixV6
+}
+
+// GetCfgHTTPOpenAPISpecs returns the openapi documents the observed http paths are matched to
+func GetCfgHTTPOpenAPISpecs() []types.HTTPOpenAPISpec {
+	return CurrentCfg.ConfigNetPolicy.HTTPOpenAPISpecs
+}
+
+// GetCfgFQDNPatternMinNames returns the number of names of a domain replaced by a wildcard pattern
+func GetCfgFQDNPatternMinNames() int {
+	return CurrentCfg.ConfigNetPolicy.FQDNPatternMinNames
+}
+
+// GetCfgClusterDomain returns the dns domain of the cluster, the default one if it is not set
+func GetCfgClusterDomain() string {
+	if CurrentCfg.ConfigNetPolicy.ClusterDomain == "" {
+		return DefaultClusterDomain
+	}
+	return CurrentCfg.ConfigNetPolicy.ClusterDomain
+}
+
+// GetCfgHTTPRuleHost returns true if the http rules match the host of the requests
+func GetCfgHTTPRuleHost() bool {
+	return CurrentCfg.ConfigNetPolicy.HTTPRuleHost
+}
+
+// GetCfgHTTPRuleHeaders returns the names of the request headers the http rules match
+func GetCfgHTTPRuleHeaders() []string {
+	return CurrentCfg.ConfigNetPolicy.HTTPRuleHeaders
+}
+
+// GetCfgHTTPSkipErrors returns true if the requests answered by 4xx/5xx are not discovered
+func GetCfgHTTPSkipErrors() bool {
+	return CurrentCfg.ConfigNetPolicy.HTTPSkipErrors
+}
+
+// GetCfgPortRange returns the number of ports collapsed into a range, and the unobserved ports a range may span
+// between two observed ports
+func GetCfgPortRange() (int, int) {
+	return CurrentCfg.ConfigNetPolicy.PortRangeMinPorts, CurrentCfg.ConfigNetPolicy.PortRangeMaxGap
+}
+
+// GetCfgLabelPolicy returns the regexes of the label keys included and excluded from the selectors,
+// and the label keys the selectors are built from only, if present
+func GetCfgLabelPolicy() ([]string, []string, []string) {
+	netCfg := CurrentCfg.ConfigNetPolicy
+	return netCfg.LabelPolicyInclude, netCfg.LabelPolicyExclude, netCfg.LabelPolicyPreferredKeys
+}
+
+// GetCfgCIDRProviderRangesFile returns the file of the named provider ranges the external addresses are widened to
+func GetCfgCIDRProviderRangesFile() string {
+	return CurrentCfg.ConfigNetPolicy.CIDRProviderRangesFile
+}
+
 func GetCfgNetworkPolicyTypes() int {
 	return CurrentCfg.ConfigNetPolicy.NetPolicyTypes
 }
@@ -433,6 +813,15 @@ func GetCfgObservabilityWriteLogsToDB() bool {
 // == Extract NS Filter == //
 // ======================= //
 
+// getConfigHTTPOpenAPISpecs loads the list of {namespace, service, labels, file}
+func getConfigHTTPOpenAPISpecs(config string) []types.HTTPOpenAPISpec {
+	specs := []types.HTTPOpenAPISpec{}
+	if err := viper.UnmarshalKey(config, &specs); err != nil {
+		return nil
+	}
+	return specs
+}
+
 func getConfigNsFilter(config string) ([]string, []string) {
 	var ns, notNs []string
 	namespaces := viper.GetStringSlice(config)
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/accuknox/auto-policy-discovery/src/types"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -20,6 +21,8 @@ application:
     network-policy-dir: "./"
     network-policy-types: 3
     network-policy-rule-types: 511
+    cidr-bits: 32
+    cidr-v6-bits: 128
     network-policy-ignoring-namespaces: "kube-system"
   system:
     system-log-from: db
@@ -142,6 +145,7 @@ func TestLoadDefaultConfig(t *testing.T) {
 	assert.NotEmpty(t, CurrentCfg.ConfigNetPolicy.NetPolicyTypes, "Network policy types should not be empty")
 	assert.NotEmpty(t, CurrentCfg.ConfigNetPolicy.NetPolicyRuleTypes, "Network policy rule types should not be empty")
 	assert.NotEmpty(t, CurrentCfg.ConfigNetPolicy.NetPolicyCIDRBits, "Network Policy cidr bits should not be empty")
+	assert.Equal(t, 128, CurrentCfg.ConfigNetPolicy.NetPolicyCIDRv6Bits, "Network Policy ipv6 cidr bits should be read from the yaml")
 
 	assert.NotEmpty(t, CurrentCfg.ConfigNetPolicy.NetPolicyL3Level, "Network policy L3 level should not be empty")
 	assert.NotEmpty(t, CurrentCfg.ConfigNetPolicy.NetPolicyL4Level, "Network policy L4 level should not be empty")
@@ -158,3 +162,106 @@ func TestSetLogFile(t *testing.T) {
 
 	assert.Equal(t, CurrentCfg.ConfigNetPolicy.NetworkLogFile, "test_log.log", "network log file should be \"test_log.log\"")
 }
+
+func TestValidateConfiguration(t *testing.T) {
+	initMockYaml()
+
+	LoadConfigFromFile()
+
+	valid := CurrentCfg
+	valid.ConfigNetPolicy.CronJobTimeInterval = "@every 0h0m10s"
+	assert.NoError(t, ValidateConfiguration(valid))
+
+	noName := valid
+	noName.ConfigName = ""
+	assert.Error(t, ValidateConfiguration(noName), "configuration name should be required")
+
+	badDriver := valid
+	badDriver.ConfigDB.DBDriver = "mongo"
+	assert.Error(t, ValidateConfiguration(badDriver), "db driver should be mysql or sqlite3")
+
+	badCron := valid
+	badCron.ConfigNetPolicy.OperationMode = 1
+	badCron.ConfigNetPolicy.CronJobTimeInterval = "@every"
+	assert.Error(t, ValidateConfiguration(badCron), "cron job time interval should be parsable")
+
+	badCIDR := valid
+	badCIDR.ConfigNetPolicy.NetPolicyCIDRBits = 33
+	assert.Error(t, ValidateConfiguration(badCIDR), "cidr bits should be in [1, 32]")
+
+	allIPv6 := valid
+	allIPv6.ConfigNetPolicy.NetPolicyCIDRv6Bits = 0
+	assert.Error(t, ValidateConfiguration(allIPv6), "ipv6 cidr bits should not allow all the addresses")
+
+	badCIDRv6 := valid
+	badCIDRv6.ConfigNetPolicy.NetPolicyCIDRv6Bits = 129
+	assert.Error(t, ValidateConfiguration(badCIDRv6), "ipv6 cidr bits should be in [1, 128]")
+
+	badAggregation := valid
+	badAggregation.ConfigNetPolicy.CIDRAggregationMaxPrefixV6 = 129
+	assert.Error(t, ValidateConfiguration(badAggregation), "ipv6 cidr aggregation max prefix should be in [16, 128]")
+
+	wideAggregation := valid
+	wideAggregation.ConfigNetPolicy.CIDRAggregationMaxPrefix = 4
+	assert.Error(t, ValidateConfiguration(wideAggregation), "cidr aggregation max prefix should be in [8, 32]")
+
+	badOpenAPI := valid
+	badOpenAPI.ConfigNetPolicy.HTTPOpenAPISpecs = []types.HTTPOpenAPISpec{{File: "./petstore.yaml"}}
+	assert.Error(t, ValidateConfiguration(badOpenAPI), "an openapi spec should select a service or labels")
+
+	badFQDNPattern := valid
+	badFQDNPattern.ConfigNetPolicy.FQDNPatternMinNames = -1
+	assert.Error(t, ValidateConfiguration(badFQDNPattern), "fqdn pattern min names should not be negative")
+
+	badClusterDomain := valid
+	badClusterDomain.ConfigNetPolicy.ClusterDomain = "cluster.local."
+	assert.Error(t, ValidateConfiguration(badClusterDomain), "cluster domain should not end with a dot")
+
+	badHeader := valid
+	badHeader.ConfigNetPolicy.HTTPRuleHeaders = []string{"X-Tenant: blue"}
+	assert.Error(t, ValidateConfiguration(badHeader), "http rule headers should be header names")
+
+	badPortRange := valid
+	badPortRange.ConfigNetPolicy.PortRangeMaxGap = 65536
+	assert.Error(t, ValidateConfiguration(badPortRange), "port range max gap should be in [0, 65535]")
+
+	badLabelPolicy := valid
+	badLabelPolicy.ConfigNetPolicy.LabelPolicyExclude = []string{"app.kubernetes.io/("}
+	assert.Error(t, ValidateConfiguration(badLabelPolicy), "label policy regexes should compile")
+}
+
+func TestSetConfigurationDefaults(t *testing.T) {
+	saved := CurrentCfg
+	t.Cleanup(func() {
+		CurrentCfg = saved
+	})
+
+	// the records written before the ipv6 bits existed
+	stored := types.Configuration{ConfigName: "stored"}
+	stored.ConfigNetPolicy.NetPolicyCIDRBits = 24
+
+	CurrentCfg = stored
+	assert.Equal(t, 24, GetCfgCIDRBits())
+	assert.Equal(t, DefaultCIDRv6Bits, GetCfgCIDRv6Bits())
+
+	SetConfigurationDefaults(&stored)
+	assert.Equal(t, 24, stored.ConfigNetPolicy.NetPolicyCIDRBits)
+	assert.Equal(t, DefaultCIDRv6Bits, stored.ConfigNetPolicy.NetPolicyCIDRv6Bits)
+}
+
+func TestGetCfgCIDRAggregationDefaults(t *testing.T) {
+	saved := CurrentCfg
+	t.Cleanup(func() {
+		CurrentCfg = saved
+	})
+
+	// the records without the prefixes keep the default supernets
+	CurrentCfg.ConfigNetPolicy.CIDRAggregationMinIPs = 4
+	CurrentCfg.ConfigNetPolicy.CIDRAggregationMaxPrefix = 0
+	CurrentCfg.ConfigNetPolicy.CIDRAggregationMaxPrefixV6 = 0
+
+	minIPs, maxPrefix, maxPrefixV6 := GetCfgCIDRAggregation()
+	assert.Equal(t, 4, minIPs)
+	assert.Equal(t, DefaultCIDRAggregationMaxPrefix, maxPrefix)
+	assert.Equal(t, DefaultCIDRAggregationMaxPrefixV6, maxPrefixV6)
+}
@@ -17,6 +17,7 @@ import (
 	cfg "github.com/accuknox/auto-policy-discovery/src/config"
 	"github.com/accuknox/auto-policy-discovery/src/libs"
 	logger "github.com/accuknox/auto-policy-discovery/src/logging"
+	"github.com/accuknox/auto-policy-discovery/src/metrics"
 	"github.com/accuknox/auto-policy-discovery/src/plugin"
 	types "github.com/accuknox/auto-policy-discovery/src/types"
 	cilium "github.com/cilium/cilium/api/v1/flow"
@@ -344,6 +345,7 @@ func (cfc *KnoxFeedConsumer) processNetworkLogMessage(message []byte) error {
 	event.ClusterName = clusterNameStr
 	cfc.netLogEvents = append(cfc.netLogEvents, event)
 	cfc.netLogEventsCount++
+	metrics.AddBufferedLogs(metrics.BufferFeedConsumerNet, 1)
 
 	if cfc.netLogEventsCount == cfc.eventsBuffer {
 		if len(cfc.netLogEvents) > 0 {
@@ -379,6 +381,7 @@ func (cfc *KnoxFeedConsumer) processNetworkLogMessage(message []byte) error {
 					knoxFlow.ClusterName = netLog.ClusterName
 					plugin.CiliumFlowsFCMutex.Lock()
 					plugin.CiliumFlowsFC = append(plugin.CiliumFlowsFC, &knoxFlow)
+					metrics.SetBufferedLogs(metrics.BufferCiliumFlowsFC, len(plugin.CiliumFlowsFC))
 					plugin.CiliumFlowsFCMutex.Unlock()
 				}
 			}
@@ -386,6 +389,7 @@ func (cfc *KnoxFeedConsumer) processNetworkLogMessage(message []byte) error {
 			cfc.netLogEvents = make([]types.NetworkLogEvent, 0, cfc.eventsBuffer)
 		}
 
+		metrics.AddBufferedLogs(metrics.BufferFeedConsumerNet, -cfc.netLogEventsCount)
 		cfc.netLogEventsCount = 0
 	}
 
@@ -405,6 +409,7 @@ func (cfc *KnoxFeedConsumer) processSystemLogMessage(message []byte) error {
 
 	cfc.syslogEvents = append(cfc.syslogEvents, syslogEvent)
 	cfc.syslogEventsCount++
+	metrics.AddBufferedLogs(metrics.BufferFeedConsumerSys, 1)
 
 	if cfc.syslogEventsCount == cfc.eventsBuffer {
 		if len(cfc.syslogEvents) > 0 {
@@ -429,12 +434,14 @@ func (cfc *KnoxFeedConsumer) processSystemLogMessage(message []byte) error {
 				knoxLog.ClusterName = syslog.Clustername
 				plugin.KubeArmorFCLogsMutex.Lock()
 				plugin.KubeArmorFCLogs = append(plugin.KubeArmorFCLogs, &knoxLog)
+				metrics.SetBufferedLogs(metrics.BufferKubeArmorLogsFC, len(plugin.KubeArmorFCLogs))
 				plugin.KubeArmorFCLogsMutex.Unlock()
 			}
 			cfc.syslogEvents = nil
 			cfc.syslogEvents = make([]types.SystemLogEvent, 0, cfc.eventsBuffer)
 		}
 
+		metrics.AddBufferedLogs(metrics.BufferFeedConsumerSys, -cfc.syslogEventsCount)
 		cfc.syslogEventsCount = 0
 	}
 
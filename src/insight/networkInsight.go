@@ -11,6 +11,7 @@ import (
 
 func ClearNetworkDB() {
 	libs.ClearNetworkDBTable(network.CfgDB)
+	network.PublishDeletedPolicies(network.CfgDB)
 }
 
 func ConvertNetInsDataToInsResponse(netdata ipb.NetworkInsightData) ipb.InsightResponse {
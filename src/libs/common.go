@@ -46,8 +46,19 @@ const (
 )
 
 const (
-	L7ProtocolDNS  = "dns"
-	L7ProtocolHTTP = "http"
+	L7ProtocolDNS   = "dns"
+	L7ProtocolHTTP  = "http"
+	L7ProtocolKafka = "kafka"
+)
+
+const (
+	EtherTypeIPv4 = 0x0800
+	EtherTypeIPv6 = 0x86DD
+)
+
+const (
+	ICMPFamilyIPv4 = "IPv4"
+	ICMPFamilyIPv6 = "IPv6"
 )
 
 var protocolMap = map[int]string{
@@ -65,6 +76,11 @@ var ICMPReplyType = []int{
 	0, // EchoReply
 }
 
+// Array for ICMPv6 type which can be considered as ICMPv6 reply packets.
+var ICMPv6ReplyType = []int{
+	129, // EchoReply
+}
+
 func printBuildDetails() {
 	if GitCommit == "" {
 		return
@@ -95,6 +111,21 @@ func SetDefaultConfig() {
 	viper.SetDefault("application.network.network-log-from", "hubble")
 	viper.SetDefault("application.network.network-policy-to", "db|file")
 	viper.SetDefault("application.network.network-policy-dir", "./")
+	viper.SetDefault("application.network.network-policy-formats", []string{"cilium"})
+	viper.SetDefault("application.network.admin-policy-min-namespaces", 3)
+	viper.SetDefault("application.network.cidr-bits", 32)
+	viper.SetDefault("application.network.cidr-v6-bits", 128)
+	viper.SetDefault("application.network.world-cidrs", false)
+	viper.SetDefault("application.network.cidr-aggregation.min-ips", 0)
+	viper.SetDefault("application.network.cidr-aggregation.max-prefix", 24)
+	viper.SetDefault("application.network.cidr-aggregation.max-prefix-v6", 64)
+	viper.SetDefault("application.network.fqdn-pattern.min-names", 0)
+	viper.SetDefault("application.network.cluster-domain", "cluster.local")
+	viper.SetDefault("application.network.http-rules.host", false)
+	viper.SetDefault("application.network.http-rules.skip-errors", false)
+	viper.SetDefault("application.network.port-range.min-ports", 0)
+	viper.SetDefault("application.network.port-range.max-gap", 10)
+	viper.SetDefault("application.network.label-policy.preferred-keys", []string{"app.kubernetes.io/name"})
 	viper.SetDefault("application.network.skip-cert-verification", true)
 
 	// Application->System config
@@ -132,6 +163,27 @@ func SetDefaultConfig() {
 	viper.SetDefault("kubearmor.url", "localhost")
 	viper.SetDefault("kubearmor.port", "32767")
 
+	// grpc server tls config
+	viper.SetDefault("tls.enable", false)
+	viper.SetDefault("tls.verify-client-cert", false)
+	viper.SetDefault("tls.reload-interval", "30s")
+
+	// rest gateway config
+	viper.SetDefault("http-gateway.enable", false)
+	viper.SetDefault("http-gateway.port", "9090")
+
+	// prometheus metrics config
+	viper.SetDefault("metrics.enable", false)
+	viper.SetDefault("metrics.port", "9091")
+
+	// graceful shutdown config
+	viper.SetDefault("shutdown.timeout", "30s")
+	viper.SetDefault("shutdown.final-discovery", true)
+
+	// policy stream config
+	viper.SetDefault("policy-stream.queue-size", 64)
+	viper.SetDefault("policy-stream.overflow-policy", "drop-oldest")
+
 	// feed-consumer config
 	viper.SetDefault("feed-consumer.number-of-consumers", "1")
 	viper.SetDefault("feed-consumer.event-buffer-size", "50")
@@ -269,11 +321,31 @@ func IsICMP(protocol int) bool {
 	return false
 }
 
-func IsReplyICMP(icmpType int) bool {
-	if ContainsElement(ICMPReplyType, icmpType) {
-		return true
+func IsReplyICMP(protocol, icmpType int) bool {
+	if protocol == IPProtocolICMPv6 {
+		return ContainsElement(ICMPv6ReplyType, icmpType)
 	}
-	return false
+	return ContainsElement(ICMPReplyType, icmpType)
+}
+
+// GetICMPFamily returns the family of the icmp protocol, IPv4 or IPv6
+func GetICMPFamily(protocol int) string {
+	if protocol == IPProtocolICMPv6 {
+		return ICMPFamilyIPv6
+	}
+	return ICMPFamilyIPv4
+}
+
+// GetEtherType returns the ether type of the ip address, 0 if it is invalid
+func GetEtherType(ip string) int {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return 0
+	}
+	if addr.To4() != nil {
+		return EtherTypeIPv4
+	}
+	return EtherTypeIPv6
 }
 
 // ============ //
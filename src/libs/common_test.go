@@ -30,6 +30,19 @@ func TestGetProtocol(t *testing.T) {
 	assert.Equal(t, "ICMP", actual, ShouldBeEqual)
 }
 
+func TestGetEtherType(t *testing.T) {
+	assert.Equal(t, EtherTypeIPv4, GetEtherType("10.0.1.31"), ShouldBeEqual)
+	assert.Equal(t, EtherTypeIPv6, GetEtherType("fd00::1"), ShouldBeEqual)
+	assert.Equal(t, 0, GetEtherType("invalid"), ShouldBeEqual)
+}
+
+func TestIsReplyICMP(t *testing.T) {
+	assert.True(t, IsReplyICMP(IPProtocolICMP, 0))
+	assert.False(t, IsReplyICMP(IPProtocolICMP, 8))
+	assert.True(t, IsReplyICMP(IPProtocolICMPv6, 129))
+	assert.False(t, IsReplyICMP(IPProtocolICMPv6, 128))
+}
+
 // ============ //
 // == Common == //
 // ============ //
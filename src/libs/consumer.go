@@ -1,20 +1,48 @@
 package libs
 
 import (
+	"errors"
+	"sort"
 	"sync"
+	"sync/atomic"
 
+	"github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/metrics"
 	"github.com/accuknox/auto-policy-discovery/src/types"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	dpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/discovery"
 )
 
+// overflow policies of the consumer queues
+const (
+	OverflowDropOldest = "drop-oldest"
+	OverflowDisconnect = "disconnect"
+)
+
+// DefaultPolicyQueueSize is used when policy-stream.queue-size is not positive
+const DefaultPolicyQueueSize = 64
+
+// ErrConsumerTooSlow is returned to a consumer disconnected because its queue overflowed
+var ErrConsumerTooSlow = status.Error(codes.ResourceExhausted, "policy consumer too slow, queue overflowed")
+
 // PolicyConsumer stores filter information provided in v1.Discovery.GetFlow RPC request
 type PolicyConsumer struct {
 	policyType []string
 	Kind       []string
 	Filter     types.PolicyFilter
 	Events     chan *types.PolicyYaml
+
+	// latest revision sent in the stream, only used by the stream goroutine
+	sentRevision int64
+
+	overflowPolicy string
+	lagging        int32
+	dropped        uint64
+	disconnected   chan struct{}
+	disconnectOnce sync.Once
 }
 
 func (pc *PolicyConsumer) IsTypeNetwork() bool {
@@ -25,13 +53,86 @@ func (pc *PolicyConsumer) IsTypeSystem() bool {
 	return ContainsElement(pc.policyType, types.PolicyTypeSystem)
 }
 
+// Lagging returns true if policies have been dropped from the queue
+func (pc *PolicyConsumer) Lagging() bool {
+	return atomic.LoadInt32(&pc.lagging) == 1
+}
+
+// Dropped returns the number of policies dropped from the queue
+func (pc *PolicyConsumer) Dropped() uint64 {
+	return atomic.LoadUint64(&pc.dropped)
+}
+
+// push queues the policy without blocking, a full queue is handled according to the overflow policy
+func (pc *PolicyConsumer) push(policy *types.PolicyYaml) {
+	select {
+	case pc.Events <- policy:
+		return
+	default:
+	}
+
+	if pc.overflowPolicy == OverflowDisconnect {
+		pc.disconnectOnce.Do(func() {
+			log.Warn().Msgf("Policy consumer queue full (%d policies), disconnecting it", cap(pc.Events))
+			metrics.PolicyStreamDisconnected.Inc()
+			close(pc.disconnected)
+		})
+		return
+	}
+
+	if atomic.CompareAndSwapInt32(&pc.lagging, 0, 1) {
+		log.Warn().Msgf("Policy consumer queue full (%d policies), dropping the oldest policies", cap(pc.Events))
+		metrics.PolicyStreamLagging.Inc()
+	}
+
+	// make room for the latest policy, the consumer may be fed by the network and system stores at once
+	select {
+	case <-pc.Events:
+		pc.drop()
+	default:
+	}
+
+	select {
+	case pc.Events <- policy:
+	default:
+		pc.drop()
+	}
+}
+
+func (pc *PolicyConsumer) drop() {
+	atomic.AddUint64(&pc.dropped, 1)
+	metrics.PolicyStreamDropped.Inc()
+}
+
+// clearLagging resets the lagging flag once the consumer has caught up, or when it is gone
+func (pc *PolicyConsumer) clearLagging() {
+	if atomic.CompareAndSwapInt32(&pc.lagging, 1, 0) {
+		metrics.PolicyStreamLagging.Dec()
+	}
+}
+
 func NewPolicyConsumer(req *dpb.GetPolicyRequest) *PolicyConsumer {
 	kind := req.GetKind()
+
+	streamCfg := config.GetCfgPolicyStream()
+
+	queueSize := streamCfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultPolicyQueueSize
+	}
+
+	overflowPolicy := streamCfg.OverflowPolicy
+	if overflowPolicy != OverflowDisconnect {
+		overflowPolicy = OverflowDropOldest
+	}
+
 	return &PolicyConsumer{
-		Kind:       kind,
-		policyType: getPolicyTypeFromKind(kind),
-		Filter:     convertGrpcRequestToPolicyFilter(req),
-		Events:     make(chan *types.PolicyYaml, 64),
+		Kind:           kind,
+		policyType:     getPolicyTypeFromKind(kind),
+		Filter:         convertGrpcRequestToPolicyFilter(req),
+		Events:         make(chan *types.PolicyYaml, queueSize),
+		overflowPolicy: overflowPolicy,
+		disconnected:   make(chan struct{}),
 	}
 }
 
@@ -43,7 +144,10 @@ func getPolicyTypeFromKind(kind []string) []string {
 		switch k {
 		case types.KindCiliumNetworkPolicy,
 			types.KindK8sNetworkPolicy,
-			types.KindCiliumClusterwideNetworkPolicy:
+			types.KindCiliumClusterwideNetworkPolicy,
+			types.KindCalicoNetworkPolicy,
+			types.KindCalicoGlobalNetworkPolicy,
+			types.KindIstioAuthorizationPolicy:
 			isTypeNetwork = true
 		case types.KindKubeArmorPolicy,
 			types.KindKubeArmorHostPolicy:
@@ -85,22 +189,84 @@ func (pc *PolicyStore) RemoveConsumer(c *PolicyConsumer) {
 	delete(pc.Consumers, c)
 }
 
-// Publish converts the given KnoxPolicy to PolicyYaml and pushes to consumer's channels
+// Publish pushes the PolicyYaml to the queue of each matching consumer, it never blocks on a slow consumer
 func (pc *PolicyStore) Publish(policy *types.PolicyYaml) {
 	pc.Mutex.Lock()
 	defer pc.Mutex.Unlock()
 
 	for consumer := range pc.Consumers {
 		if matchPolicyYaml(policy, consumer) {
-			consumer.Events <- policy
+			consumer.push(policy)
 		}
 	}
 }
 
+// publishMutex publishes the policies in the order of their revisions
+var publishMutex sync.Mutex
+
+// UpdateAndPublish writes the policies in the db, then publishes them with their revisions,
+// the policies failed to be written are not published, a client resuming from their revisions would miss them
+func (pc *PolicyStore) UpdateAndPublish(cfg types.ConfigDB, policies []types.PolicyYaml) error {
+	publishMutex.Lock()
+	defer publishMutex.Unlock()
+
+	failed := map[int]bool{}
+	err := UpdateOrInsertPolicyYamls(cfg, policies)
+	if err != nil {
+		var writeErr *PolicyYamlsWriteError
+		if !errors.As(err, &writeErr) {
+			return err
+		}
+		for _, i := range writeErr.Indexes {
+			failed[i] = true
+		}
+	}
+
+	for i := range policies {
+		if !failed[i] {
+			pc.Publish(&policies[i])
+		}
+	}
+
+	return err
+}
+
+// MarkPolicyYamls writes and publishes the outdated or deleted event of the live policies.
+// replacedBy maps the name of each marked policy to the name of the policy replacing it, all the policies are marked if it is nil.
+func (pc *PolicyStore) MarkPolicyYamls(cfg types.ConfigDB, policyType, event string, replacedBy map[string]string) error {
+	policies, err := GetPolicyYamls(cfg, policyType)
+	if err != nil {
+		return err
+	}
+
+	marked := []types.PolicyYaml{}
+	for _, policy := range policies {
+		newName, ok := replacedBy[policy.Name]
+		if (replacedBy != nil && !ok) || !policy.IsLive() {
+			continue
+		}
+
+		policy.Event = event
+		policy.Replaces = ""
+		policy.ReplacedBy = newName
+		marked = append(marked, policy)
+	}
+
+	if len(marked) == 0 {
+		return nil
+	}
+
+	return pc.UpdateAndPublish(cfg, marked)
+}
+
+// FilterPolicyYamls returns the policies requested by the consumer, only the live ones unless it resumes from a revision
 func FilterPolicyYamls(policyYamls []types.PolicyYaml, consumer *PolicyConsumer) []types.PolicyYaml {
 	result := []types.PolicyYaml{}
 
 	for i := range policyYamls {
+		if consumer.Filter.Revision == 0 && !policyYamls[i].IsLive() {
+			continue
+		}
 		if matchPolicyYaml(&policyYamls[i], consumer) {
 			result = append(result, policyYamls[i])
 		}
@@ -128,6 +294,10 @@ func matchPolicyYaml(p *types.PolicyYaml, c *PolicyConsumer) bool {
 		return false
 	}
 
+	if p.Revision <= filter.Revision {
+		return false
+	}
+
 	return true
 }
 
@@ -136,17 +306,22 @@ func convertGrpcRequestToPolicyFilter(req *dpb.GetPolicyRequest) types.PolicyFil
 		Cluster:   req.GetCluster(),
 		Namespace: req.GetNamespace(),
 		Labels:    LabelMapFromLabelArray(req.GetLabel()),
+		Revision:  req.GetResumeRevision(),
 	}
 }
 
 func convertPolicyYamlToGrpcResponse(p *types.PolicyYaml) *dpb.GetPolicyResponse {
 	return &dpb.GetPolicyResponse{
-		Kind:      p.Kind,
-		Name:      p.Name,
-		Cluster:   p.Cluster,
-		Namespace: p.Namespace,
-		Label:     LabelMapToLabelArray(p.Labels),
-		Yaml:      p.Yaml,
+		Kind:       p.Kind,
+		Name:       p.Name,
+		Cluster:    p.Cluster,
+		Namespace:  p.Namespace,
+		Label:      LabelMapToLabelArray(p.Labels),
+		Yaml:       p.Yaml,
+		Revision:   p.Revision,
+		Event:      p.Event,
+		Replaces:   p.Replaces,
+		ReplacedBy: p.ReplacedBy,
 	}
 }
 
@@ -160,21 +335,53 @@ func SendPolicyYamlInGrpcStream(stream grpc.ServerStream, policy *types.PolicyYa
 	return nil
 }
 
+// SendPolicyYamlsInGrpcStream sends the policies in the order of their revisions
+func SendPolicyYamlsInGrpcStream(stream grpc.ServerStream, consumer *PolicyConsumer, policies []types.PolicyYaml) error {
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].Revision < policies[j].Revision
+	})
+
+	for i := range policies {
+		if err := SendPolicyYamlInGrpcStream(stream, &policies[i]); err != nil {
+			return err
+		}
+		if policies[i].Revision > consumer.sentRevision {
+			consumer.sentRevision = policies[i].Revision
+		}
+	}
+
+	return nil
+}
+
 func RelayPolicyEventToGrpcStream(stream grpc.ServerStream, consumer *PolicyConsumer) error {
+	defer consumer.clearLagging()
+
 	for {
 		select {
 		case <-stream.Context().Done():
 			// client disconnected
 			return nil
+		case <-consumer.disconnected:
+			return ErrConsumerTooSlow
 		case policy, ok := <-consumer.Events:
 			if !ok {
 				// channel closed and all items are consumed
 				return nil
 			}
+			// the dropped policies would be skipped for good, the client resumes from the latest revision sent
+			if consumer.Lagging() {
+				log.Info().Msgf("Policy consumer lagging, [%d] policies dropped, ending the stream to resync", consumer.Dropped())
+				return SendPolicyYamlInGrpcStream(stream, &types.PolicyYaml{Event: types.PolicyEventResync, Revision: consumer.sentRevision})
+			}
+			// already sent from the db, the consumer is added before the db is read
+			if policy.Revision <= consumer.sentRevision {
+				continue
+			}
 			err := SendPolicyYamlInGrpcStream(stream, policy)
 			if err != nil {
 				return err
 			}
+			consumer.sentRevision = policy.Revision
 		}
 	}
 }
@@ -0,0 +1,164 @@
+package libs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	dpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/discovery"
+)
+
+func newTestPolicyStore(queueSize int, overflowPolicy string) (*PolicyStore, *PolicyConsumer) {
+	config.CurrentCfg.ConfigPolicyStream = types.ConfigPolicyStream{QueueSize: queueSize, OverflowPolicy: overflowPolicy}
+
+	store := &PolicyStore{Consumers: make(map[*PolicyConsumer]struct{})}
+	consumer := NewPolicyConsumer(&dpb.GetPolicyRequest{Kind: []string{types.KindCiliumNetworkPolicy}})
+	store.AddConsumer(consumer)
+
+	return store, consumer
+}
+
+func TestPublishDropOldest(t *testing.T) {
+	store, consumer := newTestPolicyStore(2, OverflowDropOldest)
+
+	for i, name := range []string{"p1", "p2", "p3"} {
+		store.Publish(&types.PolicyYaml{Kind: types.KindCiliumNetworkPolicy, Name: name, Revision: int64(i + 1)})
+	}
+
+	assert.True(t, consumer.Lagging())
+	assert.Equal(t, uint64(1), consumer.Dropped())
+	assert.Equal(t, "p2", (<-consumer.Events).Name)
+	assert.Equal(t, "p3", (<-consumer.Events).Name)
+
+	consumer.clearLagging()
+	assert.False(t, consumer.Lagging())
+}
+
+// fakeServerStream records the messages sent in the stream
+type fakeServerStream struct {
+	grpc.ServerStream
+	sent []*dpb.GetPolicyResponse
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeServerStream) SendMsg(m interface{}) error {
+	s.sent = append(s.sent, m.(*dpb.GetPolicyResponse))
+	return nil
+}
+
+func TestRelayPolicyEventResync(t *testing.T) {
+	store, consumer := newTestPolicyStore(1, OverflowDropOldest)
+	consumer.sentRevision = 1
+
+	store.Publish(&types.PolicyYaml{Kind: types.KindCiliumNetworkPolicy, Name: "p2", Revision: 2})
+	store.Publish(&types.PolicyYaml{Kind: types.KindCiliumNetworkPolicy, Name: "p3", Revision: 3})
+
+	// p2 is dropped, the stream ends with a resync event of the latest revision sent
+	stream := &fakeServerStream{}
+	assert.NoError(t, RelayPolicyEventToGrpcStream(stream, consumer))
+	assert.Len(t, stream.sent, 1)
+	assert.Equal(t, types.PolicyEventResync, stream.sent[0].Event)
+	assert.Equal(t, int64(1), stream.sent[0].Revision)
+	assert.False(t, consumer.Lagging())
+}
+
+func TestPublishDisconnect(t *testing.T) {
+	store, consumer := newTestPolicyStore(1, OverflowDisconnect)
+
+	store.Publish(&types.PolicyYaml{Kind: types.KindCiliumNetworkPolicy, Name: "p1", Revision: 1})
+	store.Publish(&types.PolicyYaml{Kind: types.KindCiliumNetworkPolicy, Name: "p2", Revision: 2})
+	store.Publish(&types.PolicyYaml{Kind: types.KindCiliumNetworkPolicy, Name: "p3", Revision: 3})
+
+	select {
+	case <-consumer.disconnected:
+	default:
+		t.Error("the consumer should be disconnected")
+	}
+	assert.Equal(t, "p1", (<-consumer.Events).Name)
+}
+
+func TestPublishNonBlocking(t *testing.T) {
+	store, _ := newTestPolicyStore(1, OverflowDropOldest)
+
+	// nobody reads the queue, the publishers must not block
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.Publish(&types.PolicyYaml{Kind: types.KindCiliumNetworkPolicy, Revision: 1})
+		}()
+	}
+	wg.Wait()
+}
+
+func TestUpdateAndPublishWriteError(t *testing.T) {
+	store, consumer := newTestPolicyStore(2, OverflowDropOldest)
+
+	// prepare mock mysql
+	_, mock := NewMock()
+
+	mock.ExpectQuery("SELECT MAX\\(revision\\) FROM policy_yaml").
+		WillReturnRows(mock.NewRows([]string{"max"}).AddRow(nil))
+	mock.ExpectPrepare("UPDATE policy_yaml").ExpectExec().WillReturnError(errors.New("db error"))
+	mock.ExpectPrepare("UPDATE policy_yaml").ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+
+	policies := []types.PolicyYaml{
+		{Kind: types.KindCiliumNetworkPolicy, Name: "p1"},
+		{Kind: types.KindCiliumNetworkPolicy, Name: "p2"},
+	}
+
+	// only the written policies are published
+	err := store.UpdateAndPublish(types.ConfigDB{DBDriver: "mysql"}, policies)
+	var writeErr *PolicyYamlsWriteError
+	assert.True(t, errors.As(err, &writeErr))
+	assert.Equal(t, []int{0}, writeErr.Indexes)
+
+	assert.Len(t, consumer.Events, 1)
+	assert.Equal(t, "p2", (<-consumer.Events).Name)
+}
+
+func TestFilterPolicyYamlsResumeRevision(t *testing.T) {
+	consumer := NewPolicyConsumer(&dpb.GetPolicyRequest{Kind: []string{types.KindCiliumNetworkPolicy}, ResumeRevision: 2})
+
+	policies := []types.PolicyYaml{
+		{Kind: types.KindCiliumNetworkPolicy, Name: "p1", Revision: 1},
+		{Kind: types.KindCiliumNetworkPolicy, Name: "p2", Revision: 2},
+		{Kind: types.KindCiliumNetworkPolicy, Name: "p3", Revision: 3},
+	}
+
+	result := FilterPolicyYamls(policies, consumer)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "p3", result[0].Name)
+}
+
+func TestFilterPolicyYamlsLive(t *testing.T) {
+	policies := []types.PolicyYaml{
+		{Kind: types.KindCiliumNetworkPolicy, Name: "p1", Revision: 1, Event: types.PolicyEventDeleted},
+		{Kind: types.KindCiliumNetworkPolicy, Name: "p2", Revision: 2, Event: types.PolicyEventOutdated, ReplacedBy: "p3"},
+		{Kind: types.KindCiliumNetworkPolicy, Name: "p3", Revision: 3, Event: types.PolicyEventAdded, Replaces: "p2"},
+	}
+
+	// a listing only returns the live policies
+	consumer := NewPolicyConsumer(&dpb.GetPolicyRequest{Kind: []string{types.KindCiliumNetworkPolicy}})
+	result := FilterPolicyYamls(policies, consumer)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "p3", result[0].Name)
+
+	// a resumed watch replays the lifecycle events it missed
+	consumer = NewPolicyConsumer(&dpb.GetPolicyRequest{Kind: []string{types.KindCiliumNetworkPolicy}, ResumeRevision: 1})
+	result = FilterPolicyYamls(policies, consumer)
+	assert.Len(t, result, 2)
+	assert.Equal(t, types.PolicyEventOutdated, result[0].Event)
+	assert.Equal(t, "p3", result[0].ReplacedBy)
+}
@@ -2,8 +2,13 @@ package libs
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/accuknox/auto-policy-discovery/src/metrics"
 	"github.com/accuknox/auto-policy-discovery/src/types"
 )
 
@@ -19,6 +24,8 @@ var LastFlowID int64 = 0
 // ==================== //
 
 func GetNetworkPolicies(cfg types.ConfigDB, cluster, namespace, status, nwtype, rule string) []types.KnoxNetworkPolicy {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "GetNetworkPolicies", time.Now())
+
 	results := []types.KnoxNetworkPolicy{}
 
 	if cfg.DBDriver == "mysql" {
@@ -39,6 +46,8 @@ func GetNetworkPolicies(cfg types.ConfigDB, cluster, namespace, status, nwtype,
 }
 
 func GetNetworkPoliciesBySelector(cfg types.ConfigDB, cluster, namespace, status string, selector map[string]string) ([]types.KnoxNetworkPolicy, error) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "GetNetworkPoliciesBySelector", time.Now())
+
 	results := []types.KnoxNetworkPolicy{}
 
 	if cfg.DBDriver == "mysql" {
@@ -77,6 +86,8 @@ func GetNetworkPoliciesBySelector(cfg types.ConfigDB, cluster, namespace, status
 }
 
 func UpdateOutdatedNetworkPolicy(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "UpdateOutdatedNetworkPolicy", time.Now())
+
 	if cfg.DBDriver == "mysql" {
 		if err := UpdateOutdatedNetworkPolicyFromMySQL(cfg, outdatedPolicy, latestPolicy); err != nil {
 			log.Error().Msg(err.Error())
@@ -89,6 +100,8 @@ func UpdateOutdatedNetworkPolicy(cfg types.ConfigDB, outdatedPolicy string, late
 }
 
 func UpdateNetworkPolicies(cfg types.ConfigDB, policies []types.KnoxNetworkPolicy) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "UpdateNetworkPolicies", time.Now())
+
 	for _, policy := range policies {
 		UpdateNetworkPolicy(cfg, policy)
 	}
@@ -107,6 +120,8 @@ func UpdateNetworkPolicy(cfg types.ConfigDB, policy types.KnoxNetworkPolicy) {
 }
 
 func InsertNetworkPolicies(cfg types.ConfigDB, policies []types.KnoxNetworkPolicy) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "InsertNetworkPolicies", time.Now())
+
 	if cfg.DBDriver == "mysql" {
 		if err := InsertNetworkPoliciesToMySQL(cfg, policies); err != nil {
 			log.Error().Msg(err.Error())
@@ -138,6 +153,8 @@ var LastSysAlertID int64 = 0
 // =================== //
 
 func UpdateOutdatedSystemPolicy(cfg types.ConfigDB, outdatedPolicy string, latestPolicy string) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "UpdateOutdatedSystemPolicy", time.Now())
+
 	if cfg.DBDriver == "mysql" {
 		if err := UpdateOutdatedNetworkPolicyFromMySQL(cfg, outdatedPolicy, latestPolicy); err != nil {
 			log.Error().Msg(err.Error())
@@ -150,6 +167,8 @@ func UpdateOutdatedSystemPolicy(cfg types.ConfigDB, outdatedPolicy string, lates
 }
 
 func GetSystemPolicies(cfg types.ConfigDB, namespace, status string) []types.KnoxSystemPolicy {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "GetSystemPolicies", time.Now())
+
 	results := []types.KnoxSystemPolicy{}
 
 	if cfg.DBDriver == "mysql" {
@@ -170,6 +189,8 @@ func GetSystemPolicies(cfg types.ConfigDB, namespace, status string) []types.Kno
 }
 
 func InsertSystemPolicies(cfg types.ConfigDB, policies []types.KnoxSystemPolicy) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "InsertSystemPolicies", time.Now())
+
 	if cfg.DBDriver == "mysql" {
 		if err := InsertSystemPoliciesToMySQL(cfg, policies); err != nil {
 			log.Error().Msg(err.Error())
@@ -182,6 +203,8 @@ func InsertSystemPolicies(cfg types.ConfigDB, policies []types.KnoxSystemPolicy)
 }
 
 func UpdateSystemPolicy(cfg types.ConfigDB, policy types.KnoxSystemPolicy) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "UpdateSystemPolicy", time.Now())
+
 	if cfg.DBDriver == "mysql" {
 		if err := UpdateSystemPolicyToMySQL(cfg, policy); err != nil {
 			log.Error().Msg(err.Error())
@@ -195,6 +218,8 @@ func UpdateSystemPolicy(cfg types.ConfigDB, policy types.KnoxSystemPolicy) {
 }
 
 func GetWorkloadProcessFileSet(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet) (map[types.WorkloadProcessFileSet][]string, types.PolicyNameMap, error) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "GetWorkloadProcessFileSet", time.Now())
+
 	if cfg.DBDriver == "mysql" {
 		res, pnMap, err := GetWorkloadProcessFileSetMySQL(cfg, wpfs)
 		if err != nil {
@@ -212,6 +237,8 @@ func GetWorkloadProcessFileSet(cfg types.ConfigDB, wpfs types.WorkloadProcessFil
 }
 
 func InsertWorkloadProcessFileSet(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, fs []string) error {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "InsertWorkloadProcessFileSet", time.Now())
+
 	if cfg.DBDriver == "mysql" {
 		return InsertWorkloadProcessFileSetMySQL(cfg, wpfs, fs)
 	} else if cfg.DBDriver == "sqlite3" {
@@ -221,6 +248,8 @@ func InsertWorkloadProcessFileSet(cfg types.ConfigDB, wpfs types.WorkloadProcess
 }
 
 func ClearWPFSDb(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, duration int64) error {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "ClearWPFSDb", time.Now())
+
 	if cfg.DBDriver == "mysql" {
 		return ClearWPFSDbMySQL(cfg, wpfs, duration)
 	} else if cfg.DBDriver == "sqlite3" {
@@ -229,11 +258,27 @@ func ClearWPFSDb(cfg types.ConfigDB, wpfs types.WorkloadProcessFileSet, duration
 	return errors.New("no db driver")
 }
 
+// ============ //
+// == Health == //
+// ============ //
+
+// PingDB checks whether the db is reachable within the timeout
+func PingDB(cfg types.ConfigDB, timeout time.Duration) error {
+	if cfg.DBDriver == "mysql" {
+		return PingMySQL(cfg, timeout)
+	} else if cfg.DBDriver == "sqlite3" {
+		return PingSQLite(cfg, timeout)
+	}
+	return errors.New("no db driver")
+}
+
 // =========== //
 // == Table == //
 // =========== //
 
 func ClearDBTables(cfg types.ConfigDB) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "ClearDBTables", time.Now())
+
 	if cfg.DBDriver == "mysql" {
 		if err := ClearDBTablesMySQL(cfg); err != nil {
 			log.Error().Msg(err.Error())
@@ -246,6 +291,8 @@ func ClearDBTables(cfg types.ConfigDB) {
 }
 
 func ClearNetworkDBTable(cfg types.ConfigDB) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "ClearNetworkDBTable", time.Now())
+
 	if cfg.DBDriver == "mysql" {
 		if err := ClearNetworkDBTableMySQL(cfg); err != nil {
 			log.Error().Msg(err.Error())
@@ -254,6 +301,8 @@ func ClearNetworkDBTable(cfg types.ConfigDB) {
 }
 
 func CreateTablesIfNotExist(cfg types.ConfigDB) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "CreateTablesIfNotExist", time.Now())
+
 	if cfg.DBDriver == "mysql" {
 		if err := CreateTableNetworkPolicyMySQL(cfg); err != nil {
 			log.Error().Msg(err.Error())
@@ -273,6 +322,12 @@ func CreateTablesIfNotExist(cfg types.ConfigDB) {
 		if err := CreatePolicyTableMySQL(cfg); err != nil {
 			log.Error().Msg(err.Error())
 		}
+		if err := CreateTableConfigurationMySQL(cfg); err != nil {
+			log.Error().Msg(err.Error())
+		}
+		if err := CreateTableDiscoveryRunMySQL(cfg); err != nil {
+			log.Error().Msg(err.Error())
+		}
 	} else if cfg.DBDriver == "sqlite3" {
 		if err := CreateTableNetworkPolicySQLite(cfg); err != nil {
 			log.Error().Msg(err.Error())
@@ -295,6 +350,12 @@ func CreateTablesIfNotExist(cfg types.ConfigDB) {
 		if err := CreateSystemSummaryTableSQLite(cfg); err != nil {
 			log.Error().Msg(err.Error())
 		}
+		if err := CreateTableConfigurationSQLite(cfg); err != nil {
+			log.Error().Msg(err.Error())
+		}
+		if err := CreateTableDiscoveryRunSQLite(cfg); err != nil {
+			log.Error().Msg(err.Error())
+		}
 	}
 }
 
@@ -302,6 +363,8 @@ func CreateTablesIfNotExist(cfg types.ConfigDB) {
 // == Observability == //
 // =================== //
 func UpdateOrInsertKubearmorLogs(cfg types.ConfigDB, kubearmorLogMap map[types.KubeArmorLog]int) error {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "UpdateOrInsertKubearmorLogs", time.Now())
+
 	var err = errors.New("unknown db driver")
 	if cfg.DBDriver == "mysql" {
 		err = UpdateOrInsertKubearmorLogsMySQL(cfg, kubearmorLogMap)
@@ -312,6 +375,8 @@ func UpdateOrInsertKubearmorLogs(cfg types.ConfigDB, kubearmorLogMap map[types.K
 }
 
 func GetKubearmorLogs(cfg types.ConfigDB, filterLog types.KubeArmorLog) ([]types.KubeArmorLog, []uint32, error) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "GetKubearmorLogs", time.Now())
+
 	kubearmorLog := []types.KubeArmorLog{}
 	totalCount := []uint32{}
 	var err = errors.New("unknown db driver")
@@ -324,6 +389,8 @@ func GetKubearmorLogs(cfg types.ConfigDB, filterLog types.KubeArmorLog) ([]types
 }
 
 func UpdateOrInsertCiliumLogs(cfg types.ConfigDB, ciliumLogs []types.CiliumLog) error {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "UpdateOrInsertCiliumLogs", time.Now())
+
 	var err = errors.New("unknown db driver")
 	if cfg.DBDriver == "mysql" {
 		err = UpdateOrInsertCiliumLogsMySQL(cfg, ciliumLogs)
@@ -334,6 +401,8 @@ func UpdateOrInsertCiliumLogs(cfg types.ConfigDB, ciliumLogs []types.CiliumLog)
 }
 
 func GetCiliumLogs(cfg types.ConfigDB, ciliumFilter types.CiliumLog) ([]types.CiliumLog, []uint32, error) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "GetCiliumLogs", time.Now())
+
 	ciliumLogs := []types.CiliumLog{}
 	ciliumTotalCount := []uint32{}
 	var err = errors.New("unknown db driver")
@@ -346,6 +415,8 @@ func GetCiliumLogs(cfg types.ConfigDB, ciliumFilter types.CiliumLog) ([]types.Ci
 }
 
 func GetPodNames(cfg types.ConfigDB, filter types.ObsPodDetail) ([]string, error) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "GetPodNames", time.Now())
+
 	res := []string{}
 	var err = errors.New("unknown db driver")
 	if cfg.DBDriver == "mysql" {
@@ -360,6 +431,8 @@ func GetPodNames(cfg types.ConfigDB, filter types.ObsPodDetail) ([]string, error
 // == Policy DB == //
 // =============== //
 func GetPolicyYamls(cfg types.ConfigDB, policyType string) ([]types.PolicyYaml, error) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "GetPolicyYamls", time.Now())
+
 	var err error
 	var results []types.PolicyYaml
 
@@ -377,7 +450,16 @@ func GetPolicyYamls(cfg types.ConfigDB, policyType string) ([]types.PolicyYaml,
 	return results, nil
 }
 
+// policyRevisionMutex serializes the policy yaml writes, so that the revisions are unique and increasing
+var policyRevisionMutex sync.Mutex
+
+// UpdateOrInsertPolicyYamls writes the policies with the next revisions, the revisions are set in the given slice
 func UpdateOrInsertPolicyYamls(cfg types.ConfigDB, policies []types.PolicyYaml) error {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "UpdateOrInsertPolicyYamls", time.Now())
+
+	policyRevisionMutex.Lock()
+	defer policyRevisionMutex.Unlock()
+
 	var err = errors.New("unknown db driver")
 	if cfg.DBDriver == "mysql" {
 		err = UpdateOrInsertPolicyYamlsMySQL(cfg, policies)
@@ -387,10 +469,488 @@ func UpdateOrInsertPolicyYamls(cfg types.ConfigDB, policies []types.PolicyYaml)
 	return err
 }
 
+// PolicyYamlsWriteError is the error of the policies not written in the db, the other policies are written
+type PolicyYamlsWriteError struct {
+	Indexes []int
+	Errs    []error
+}
+
+func (e *PolicyYamlsWriteError) add(index int, err error) {
+	e.Indexes = append(e.Indexes, index)
+	e.Errs = append(e.Errs, err)
+}
+
+func (e *PolicyYamlsWriteError) Error() string {
+	return "failed to write " + strconv.Itoa(len(e.Indexes)) + " policy yamls: " + e.Errs[0].Error()
+}
+
+// setPolicyYamlRevisionsSQL sets the revisions following the latest one written in the table
+func setPolicyYamlRevisionsSQL(db *sql.DB, tableName string, policies []types.PolicyYaml) error {
+	var latest sql.NullInt64
+	if err := db.QueryRow("SELECT MAX(revision) FROM " + tableName).Scan(&latest); err != nil {
+		return err
+	}
+
+	for i := range policies {
+		policies[i].Revision = latest.Int64 + int64(i) + 1
+	}
+
+	return nil
+}
+
+// addColumnIfNotExistsSQL adds the column to a table created by a previous version
+func addColumnIfNotExistsSQL(db *sql.DB, tableName, column, definition string) error {
+	rows, err := db.Query("SELECT " + column + " FROM " + tableName + " LIMIT 0")
+	if err == nil {
+		return rows.Close()
+	}
+
+	_, err = db.Exec("ALTER TABLE " + tableName + " ADD COLUMN " + column + " " + definition)
+	return err
+}
+
+// =================== //
+// == Configuration == //
+// =================== //
+
+var ErrConfigNotFound = errors.New("configuration not found")
+
+func AddConfiguration(cfg types.ConfigDB, newConfig types.Configuration) error {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "AddConfiguration", time.Now())
+
+	var err = errors.New("unknown db driver")
+	if cfg.DBDriver == "mysql" {
+		err = AddConfigurationToMySQL(cfg, newConfig)
+	} else if cfg.DBDriver == "sqlite3" {
+		err = AddConfigurationToSQLite(cfg, newConfig)
+	}
+	return err
+}
+
+// GetConfigurations returns the configuration with the given name, or all of them if the name is empty
+func GetConfigurations(cfg types.ConfigDB, configName string) ([]types.Configuration, error) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "GetConfigurations", time.Now())
+
+	return getConfigurations(cfg, configName)
+}
+
+func getConfigurations(cfg types.ConfigDB, configName string) ([]types.Configuration, error) {
+	var err = errors.New("unknown db driver")
+	var results []types.Configuration
+	if cfg.DBDriver == "mysql" {
+		results, err = GetConfigurationsFromMySQL(cfg, configName)
+	} else if cfg.DBDriver == "sqlite3" {
+		results, err = GetConfigurationsFromSQLite(cfg, configName)
+	}
+	return results, err
+}
+
+// GetAppliedConfiguration returns the configuration marked as applied, if any
+func GetAppliedConfiguration(cfg types.ConfigDB) (*types.Configuration, error) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "GetAppliedConfiguration", time.Now())
+
+	configs, err := getConfigurations(cfg, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range configs {
+		if configs[i].Status == 1 {
+			return &configs[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func UpdateConfiguration(cfg types.ConfigDB, configName string, updateConfig types.Configuration) error {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "UpdateConfiguration", time.Now())
+
+	var err = errors.New("unknown db driver")
+	if cfg.DBDriver == "mysql" {
+		err = UpdateConfigurationToMySQL(cfg, configName, updateConfig)
+	} else if cfg.DBDriver == "sqlite3" {
+		err = UpdateConfigurationToSQLite(cfg, configName, updateConfig)
+	}
+	return err
+}
+
+func DeleteConfiguration(cfg types.ConfigDB, configName string) error {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "DeleteConfiguration", time.Now())
+
+	var err = errors.New("unknown db driver")
+	if cfg.DBDriver == "mysql" {
+		err = DeleteConfigurationFromMySQL(cfg, configName)
+	} else if cfg.DBDriver == "sqlite3" {
+		err = DeleteConfigurationFromSQLite(cfg, configName)
+	}
+	return err
+}
+
+// ApplyConfiguration marks the given configuration as the applied one, all the others become inactive
+func ApplyConfiguration(cfg types.ConfigDB, configName string) error {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "ApplyConfiguration", time.Now())
+
+	var err = errors.New("unknown db driver")
+	if cfg.DBDriver == "mysql" {
+		err = ApplyConfigurationToMySQL(cfg, configName)
+	} else if cfg.DBDriver == "sqlite3" {
+		err = ApplyConfigurationToSQLite(cfg, configName)
+	}
+	return err
+}
+
+func addConfigurationSQL(db *sql.DB, tableName string, newConfig types.Configuration) error {
+	existing, err := getConfigurationsSQL(db, tableName, newConfig.ConfigName)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return errors.New("configuration [" + newConfig.ConfigName + "] already exists")
+	}
+
+	configData, err := json.Marshal(newConfig)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := db.Prepare("INSERT INTO " + tableName + "(config_name,status,config,updated_time) values(?,?,?,?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(newConfig.ConfigName, newConfig.Status, configData, ConvertStrToUnixTime("now"))
+	return err
+}
+
+func getConfigurationsSQL(db *sql.DB, tableName, configName string) ([]types.Configuration, error) {
+	configs := []types.Configuration{}
+
+	var results *sql.Rows
+	var err error
+
+	query := "SELECT config_name,status,config FROM " + tableName
+	if configName != "" {
+		query = query + " WHERE config_name = ?"
+		results, err = db.Query(query, configName)
+	} else {
+		results, err = db.Query(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var configData []byte
+		c := types.Configuration{}
+
+		if err := results.Scan(&c.ConfigName, &c.Status, &configData); err != nil {
+			return nil, err
+		}
+
+		name, status := c.ConfigName, c.Status
+		if err := json.Unmarshal(configData, &c); err != nil {
+			return nil, err
+		}
+		// columns are the source of truth for name and status
+		c.ConfigName, c.Status = name, status
+
+		configs = append(configs, c)
+	}
+
+	return configs, results.Err()
+}
+
+func updateConfigurationSQL(db *sql.DB, tableName, configName string, updateConfig types.Configuration) error {
+	updateConfig.ConfigName = configName
+
+	configData, err := json.Marshal(updateConfig)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := db.Prepare("UPDATE " + tableName + " SET config=?, updated_time=? WHERE config_name=?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(configData, ConvertStrToUnixTime("now"), configName)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return ErrConfigNotFound
+	}
+
+	return nil
+}
+
+func deleteConfigurationSQL(db *sql.DB, tableName, configName string) error {
+	stmt, err := db.Prepare("DELETE FROM " + tableName + " WHERE config_name=?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(configName)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return ErrConfigNotFound
+	}
+
+	return nil
+}
+
+func applyConfigurationSQL(db *sql.DB, tableName, configName string) error {
+	existing, err := getConfigurationsSQL(db, tableName, configName)
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return ErrConfigNotFound
+	}
+
+	if _, err := db.Exec("UPDATE "+tableName+" SET status=? WHERE config_name!=?", 0, configName); err != nil {
+		return err
+	}
+
+	_, err = db.Exec("UPDATE "+tableName+" SET status=?, updated_time=? WHERE config_name=?", 1, ConvertStrToUnixTime("now"), configName)
+	return err
+}
+
+// =================== //
+// == Discovery Run == //
+// =================== //
+
+var ErrDiscoveryRunNotFound = errors.New("discovery run not found")
+
+// AddDiscoveryRun inserts the run, and sets its id
+func AddDiscoveryRun(cfg types.ConfigDB, run *types.DiscoveryRun) error {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "AddDiscoveryRun", time.Now())
+
+	var err = errors.New("unknown db driver")
+	if cfg.DBDriver == "mysql" {
+		err = AddDiscoveryRunToMySQL(cfg, run)
+	} else if cfg.DBDriver == "sqlite3" {
+		err = AddDiscoveryRunToSQLite(cfg, run)
+	}
+	return err
+}
+
+func UpdateDiscoveryRun(cfg types.ConfigDB, run *types.DiscoveryRun) error {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "UpdateDiscoveryRun", time.Now())
+
+	var err = errors.New("unknown db driver")
+	if cfg.DBDriver == "mysql" {
+		err = UpdateDiscoveryRunToMySQL(cfg, run)
+	} else if cfg.DBDriver == "sqlite3" {
+		err = UpdateDiscoveryRunToSQLite(cfg, run)
+	}
+	return err
+}
+
+// GetDiscoveryRuns returns the latest runs first, filtered by the non-empty id, type, trigger and status of the filter.
+// All the runs are returned if the limit is not positive.
+func GetDiscoveryRuns(cfg types.ConfigDB, filter types.DiscoveryRun, limit int) ([]types.DiscoveryRun, error) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "GetDiscoveryRuns", time.Now())
+
+	return getDiscoveryRuns(cfg, filter, limit)
+}
+
+func getDiscoveryRuns(cfg types.ConfigDB, filter types.DiscoveryRun, limit int) ([]types.DiscoveryRun, error) {
+	var err = errors.New("unknown db driver")
+	var results []types.DiscoveryRun
+	if cfg.DBDriver == "mysql" {
+		results, err = GetDiscoveryRunsFromMySQL(cfg, filter, limit)
+	} else if cfg.DBDriver == "sqlite3" {
+		results, err = GetDiscoveryRunsFromSQLite(cfg, filter, limit)
+	}
+	return results, err
+}
+
+func GetDiscoveryRun(cfg types.ConfigDB, id int64) (*types.DiscoveryRun, error) {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "GetDiscoveryRun", time.Now())
+
+	runs, err := getDiscoveryRuns(cfg, types.DiscoveryRun{ID: id}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, ErrDiscoveryRunNotFound
+	}
+	return &runs[0], nil
+}
+
+// StartDiscoveryRun records a running discovery job, the run is still returned if it cannot be stored
+func StartDiscoveryRun(cfg types.ConfigDB, runType, trigger string, logsConsumed int) *types.DiscoveryRun {
+	run := &types.DiscoveryRun{
+		Type:         runType,
+		Trigger:      trigger,
+		Status:       types.DiscoveryRunRunning,
+		StartTime:    time.Now().Unix(),
+		LogsConsumed: logsConsumed,
+	}
+
+	if err := AddDiscoveryRun(cfg, run); err != nil {
+		log.Error().Msgf("Failed to record the %s discovery run: %v", runType, err)
+	}
+
+	return run
+}
+
+// SaveDiscoveryRun stores the progress of the run, or its result once it is finished
+func SaveDiscoveryRun(cfg types.ConfigDB, run *types.DiscoveryRun) {
+	// the run could not be stored at the start
+	if run == nil || run.ID == 0 {
+		return
+	}
+
+	if err := UpdateDiscoveryRun(cfg, run); err != nil {
+		log.Error().Msgf("Failed to update the discovery run [%d]: %v", run.ID, err)
+	}
+}
+
+func addDiscoveryRunSQL(db *sql.DB, tableName string, run *types.DiscoveryRun) error {
+	clusters, namespaces, runErrors, err := marshalDiscoveryRunLists(run)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := db.Prepare("INSERT INTO " + tableName + "(type,run_trigger,status,start_time,end_time,logs_consumed,clusters,namespaces,policies_created,policies_updated,errors) values(?,?,?,?,?,?,?,?,?,?,?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(run.Type, run.Trigger, run.Status, run.StartTime, run.EndTime, run.LogsConsumed,
+		clusters, namespaces, run.PoliciesCreated, run.PoliciesUpdated, runErrors)
+	if err != nil {
+		return err
+	}
+
+	run.ID, err = result.LastInsertId()
+	return err
+}
+
+func updateDiscoveryRunSQL(db *sql.DB, tableName string, run *types.DiscoveryRun) error {
+	clusters, namespaces, runErrors, err := marshalDiscoveryRunLists(run)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := db.Prepare("UPDATE " + tableName + " SET status=?, end_time=?, logs_consumed=?, clusters=?, namespaces=?, policies_created=?, policies_updated=?, errors=? WHERE id=?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(run.Status, run.EndTime, run.LogsConsumed, clusters, namespaces,
+		run.PoliciesCreated, run.PoliciesUpdated, runErrors, run.ID)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return ErrDiscoveryRunNotFound
+	}
+
+	return nil
+}
+
+func getDiscoveryRunsSQL(db *sql.DB, tableName string, filter types.DiscoveryRun, limit int) ([]types.DiscoveryRun, error) {
+	runs := []types.DiscoveryRun{}
+
+	var whereClause string
+	var args []interface{}
+
+	if filter.ID != 0 {
+		concatWhereClause(&whereClause, "id")
+		args = append(args, filter.ID)
+	}
+	if filter.Type != "" {
+		concatWhereClause(&whereClause, "type")
+		args = append(args, filter.Type)
+	}
+	if filter.Trigger != "" {
+		concatWhereClause(&whereClause, "run_trigger")
+		args = append(args, filter.Trigger)
+	}
+	if filter.Status != "" {
+		concatWhereClause(&whereClause, "status")
+		args = append(args, filter.Status)
+	}
+
+	query := "SELECT id,type,run_trigger,status,start_time,end_time,logs_consumed,clusters,namespaces,policies_created,policies_updated,errors FROM " +
+		tableName + whereClause + " ORDER BY id DESC"
+	if limit > 0 {
+		query = query + " LIMIT " + strconv.Itoa(limit)
+	}
+
+	results, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var clusters, namespaces, runErrors []byte
+		run := types.DiscoveryRun{}
+
+		if err := results.Scan(&run.ID, &run.Type, &run.Trigger, &run.Status, &run.StartTime, &run.EndTime, &run.LogsConsumed,
+			&clusters, &namespaces, &run.PoliciesCreated, &run.PoliciesUpdated, &runErrors); err != nil {
+			return nil, err
+		}
+
+		if err := unmarshalDiscoveryRunLists(&run, clusters, namespaces, runErrors); err != nil {
+			return nil, err
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, results.Err()
+}
+
+func marshalDiscoveryRunLists(run *types.DiscoveryRun) (clusters, namespaces, runErrors []byte, err error) {
+	if clusters, err = json.Marshal(run.Clusters); err != nil {
+		return
+	}
+	if namespaces, err = json.Marshal(run.Namespaces); err != nil {
+		return
+	}
+	runErrors, err = json.Marshal(run.Errors)
+	return
+}
+
+func unmarshalDiscoveryRunLists(run *types.DiscoveryRun, clusters, namespaces, runErrors []byte) error {
+	for _, list := range []struct {
+		data []byte
+		dst  *[]string
+	}{{clusters, &run.Clusters}, {namespaces, &run.Namespaces}, {runErrors, &run.Errors}} {
+		// the null json of an empty list is left empty
+		if len(list.data) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(list.data, list.dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ============= //
 // == Summary == //
 // ============= //
 func UpsertSystemSummary(cfg types.ConfigDB, summaryMap map[types.SystemSummary]types.SysSummaryTimeCount) error {
+	defer metrics.ObserveDBQuery(cfg.DBDriver, "UpsertSystemSummary", time.Now())
+
 	var err = errors.New("unknown db driver")
 	if cfg.DBDriver == "mysql" {
 		err = UpsertSystemSummaryMySQL(cfg, summaryMap)
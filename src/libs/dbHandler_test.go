@@ -2,9 +2,14 @@ package libs
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/accuknox/auto-policy-discovery/src/metrics"
 	"github.com/accuknox/auto-policy-discovery/src/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -144,3 +149,248 @@ func TestInsertNetworkPoliciesSQLite(t *testing.T) {
 		t.Errorf(Unmet+"%s", err)
 	}
 }
+
+// =============== //
+// == Policy DB == //
+// =============== //
+
+func TestGetPolicyYamls(t *testing.T) {
+	// prepare mock mysql
+	_, mock := NewMock()
+
+	rows := mock.NewRows([]string{"type", "kind", "cluster_name", "namespace", "labels", "policy_name",
+		"policy_yaml", "revision", "event", "replaces", "replaced_by"}).
+		AddRow("network", "CiliumNetworkPolicy", "default", "wordpress-mysql", "app=wordpress", "p1", []byte("yaml"), 3, types.PolicyEventAdded, "", "")
+
+	query := "SELECT type,kind,cluster_name,namespace,labels,policy_name,policy_yaml,revision,event,replaces,replaced_by FROM policy_yaml WHERE type = ?"
+	mock.ExpectQuery("^" + regexp.QuoteMeta(query) + "$").
+		WithArgs("network").
+		WillReturnRows(rows)
+
+	results, err := GetPolicyYamls(types.ConfigDB{DBDriver: "mysql"}, "network")
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "p1", results[0].Name)
+	assert.Equal(t, int64(3), results[0].Revision)
+	assert.Equal(t, map[string]string{"app": "wordpress"}, results[0].Labels)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestUpdateOrInsertPolicyYamlsAdded(t *testing.T) {
+	// prepare mock mysql
+	_, mock := NewMock()
+
+	policies := []types.PolicyYaml{{Name: "p1", Replaces: "p0"}}
+
+	mock.ExpectQuery("SELECT MAX\\(revision\\) FROM policy_yaml").
+		WillReturnRows(mock.NewRows([]string{"max"}).AddRow(nil))
+
+	prep := mock.ExpectPrepare("UPDATE policy_yaml")
+	prep.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 0))
+
+	prep = mock.ExpectPrepare("INSERT INTO policy_yaml")
+	prep.ExpectExec().
+		WithArgs("", "", "", "", "", "p1", []byte(nil), sqlmock.AnyArg(), int64(1), types.PolicyEventAdded, "p0", "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := UpdateOrInsertPolicyYamls(types.ConfigDB{DBDriver: "mysql"}, policies)
+	assert.NoError(t, err)
+	assert.Equal(t, types.PolicyEventAdded, policies[0].Event)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestUpdateOrInsertPolicyYamlsRevision(t *testing.T) {
+	// prepare mock mysql
+	_, mock := NewMock()
+
+	// the policies of the same name in different formats are distinct rows
+	policies := []types.PolicyYaml{{Name: "p1", Kind: "CiliumNetworkPolicy"}, {Name: "p1", Kind: types.KindCalicoNetworkPolicy}}
+
+	mock.ExpectQuery("SELECT MAX\\(revision\\) FROM policy_yaml").
+		WillReturnRows(mock.NewRows([]string{"max"}).AddRow(5))
+
+	for i, pol := range policies {
+		prep := mock.ExpectPrepare("UPDATE policy_yaml")
+		prep.ExpectExec().
+			WithArgs(pol.Yaml, sqlmock.AnyArg(), int64(6+i), types.PolicyEventUpdated, "", "", pol.Name, pol.Kind).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	err := UpdateOrInsertPolicyYamls(types.ConfigDB{DBDriver: "mysql"}, policies)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), policies[0].Revision)
+	assert.Equal(t, int64(7), policies[1].Revision)
+	assert.Equal(t, types.PolicyEventUpdated, policies[0].Event)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+// =================== //
+// == Configuration == //
+// =================== //
+
+func TestAddConfiguration(t *testing.T) {
+	// prepare mock mysql
+	_, mock := NewMock()
+
+	newConfig := types.Configuration{ConfigName: "test"}
+	configData, _ := json.Marshal(newConfig)
+
+	mock.ExpectQuery("^SELECT (.+) FROM auto_policy_config WHERE config_name").
+		WithArgs("test").
+		WillReturnRows(mock.NewRows([]string{"config_name", "status", "config"}))
+
+	prep := mock.ExpectPrepare("INSERT INTO auto_policy_config")
+	prep.ExpectExec().
+		WithArgs(
+			"test",           // str
+			0,                // int
+			configData,       // []byte
+			sqlmock.AnyArg(), // int64
+		).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := AddConfiguration(types.ConfigDB{DBDriver: "mysql"}, newConfig)
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestGetConfigurations(t *testing.T) {
+	// prepare mock mysql
+	_, mock := NewMock()
+
+	configData, _ := json.Marshal(types.Configuration{
+		ConfigName:      "stale-name",
+		ConfigNetPolicy: types.ConfigNetworkPolicy{OperationMode: 2},
+	})
+
+	rows := mock.NewRows([]string{"config_name", "status", "config"}).
+		AddRow("test", 1, configData)
+
+	mock.ExpectQuery("^SELECT (.+) FROM auto_policy_config").
+		WillReturnRows(rows)
+
+	results, err := GetConfigurations(types.ConfigDB{DBDriver: "mysql"}, "")
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "test", results[0].ConfigName)
+	assert.Equal(t, 1, results[0].Status)
+	assert.Equal(t, 2, results[0].ConfigNetPolicy.OperationMode)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestGetAppliedConfigurationMetrics(t *testing.T) {
+	_, err := GetAppliedConfiguration(types.ConfigDB{DBDriver: "none"})
+	assert.Error(t, err)
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// the nested handler is not counted twice
+	body := w.Body.String()
+	assert.True(t, strings.Contains(body, `knoxautopolicy_db_query_duration_seconds_count{driver="none",handler="GetAppliedConfiguration"} 1`))
+	assert.False(t, strings.Contains(body, `driver="none",handler="GetConfigurations"`))
+}
+
+func TestDeleteConfigurationNotFound(t *testing.T) {
+	// prepare mock mysql
+	_, mock := NewMock()
+
+	prep := mock.ExpectPrepare("DELETE FROM auto_policy_config")
+	prep.ExpectExec().
+		WithArgs("test").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := DeleteConfiguration(types.ConfigDB{DBDriver: "mysql"}, "test")
+	assert.Equal(t, ErrConfigNotFound, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+// =================== //
+// == Discovery Run == //
+// =================== //
+
+func TestAddDiscoveryRun(t *testing.T) {
+	// prepare mock mysql
+	_, mock := NewMock()
+
+	run := &types.DiscoveryRun{
+		Type:         types.PolicyTypeNetwork,
+		Trigger:      types.DiscoveryTriggerCron,
+		Status:       types.DiscoveryRunRunning,
+		StartTime:    1,
+		LogsConsumed: 10,
+	}
+
+	prep := mock.ExpectPrepare("INSERT INTO discovery_run")
+	prep.ExpectExec().
+		WithArgs("network", "cron", "running", 1, 0, 10, []byte("null"), []byte("null"), 0, 0, []byte("null")).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	err := AddDiscoveryRun(types.ConfigDB{DBDriver: "mysql"}, run)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), run.ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestGetDiscoveryRuns(t *testing.T) {
+	// prepare mock mysql
+	_, mock := NewMock()
+
+	rows := mock.NewRows([]string{"id", "type", "run_trigger", "status", "start_time", "end_time", "logs_consumed",
+		"clusters", "namespaces", "policies_created", "policies_updated", "errors"}).
+		AddRow(2, "system", "rpc", "completed", 10, 20, 5, []byte(`["default"]`), []byte(`["default/wordpress"]`), 3, 1, []byte("null"))
+
+	mock.ExpectQuery("^SELECT (.+) FROM discovery_run WHERE type = \\? and status = \\? ORDER BY id DESC LIMIT 10").
+		WithArgs("system", "completed").
+		WillReturnRows(rows)
+
+	runs, err := GetDiscoveryRuns(types.ConfigDB{DBDriver: "mysql"}, types.DiscoveryRun{Type: "system", Status: "completed"}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 1)
+	assert.Equal(t, int64(2), runs[0].ID)
+	assert.Equal(t, "rpc", runs[0].Trigger)
+	assert.Equal(t, []string{"default"}, runs[0].Clusters)
+	assert.Equal(t, []string{"default/wordpress"}, runs[0].Namespaces)
+	assert.Equal(t, 3, runs[0].PoliciesCreated)
+	assert.Empty(t, runs[0].Errors)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
+
+func TestGetDiscoveryRunNotFound(t *testing.T) {
+	// prepare mock mysql
+	_, mock := NewMock()
+
+	mock.ExpectQuery("^SELECT (.+) FROM discovery_run WHERE id = \\?").
+		WithArgs(3).
+		WillReturnRows(mock.NewRows([]string{"id"}))
+
+	_, err := GetDiscoveryRun(types.ConfigDB{DBDriver: "mysql"}, 3)
+	assert.Equal(t, ErrDiscoveryRunNotFound, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf(Unmet+"%s", err)
+	}
+}
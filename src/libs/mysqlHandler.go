@@ -1,6 +1,7 @@
 package libs
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"strconv"
@@ -19,6 +20,8 @@ const TableSystemPolicy_TableName = "system_policy"
 const TableSystemLogs_TableName = "system_logs"
 const TableNetworkLogs_TableName = "network_logs"
 const PolicyYaml_TableName = "policy_yaml"
+const TableConfiguration_TableName = "auto_policy_config"
+const TableDiscoveryRun_TableName = "discovery_run"
 
 // ================ //
 // == Connection == //
@@ -51,12 +54,16 @@ func waitForDB(db *sql.DB) {
 	}
 }
 
+func getMySQLConn(cfg types.ConfigDB) string {
+	return cfg.DBUser + ":" + cfg.DBPass + "@tcp(" + cfg.DBHost + ":" + cfg.DBPort + ")/" + cfg.DBName
+}
+
 func connectMySQL(cfg types.ConfigDB) (db *sql.DB) {
 	if MockDB != nil {
 		return MockDB
 	}
 
-	dbconn := cfg.DBUser + ":" + cfg.DBPass + "@tcp(" + cfg.DBHost + ":" + cfg.DBPort + ")/" + cfg.DBName
+	dbconn := getMySQLConn(cfg)
 	db, err := sql.Open(cfg.DBDriver, dbconn)
 	for err != nil {
 		log.Error().Msgf("mysql driver:%s, user:%s, host:%s, port:%s, dbname:%s conn-error:%s",
@@ -72,6 +79,24 @@ func connectMySQL(cfg types.ConfigDB) (db *sql.DB) {
 	return db
 }
 
+// PingMySQL checks the db once, unlike connectMySQL which waits until the db is reachable
+func PingMySQL(cfg types.ConfigDB, timeout time.Duration) error {
+	if MockDB != nil {
+		return MockDB.Ping()
+	}
+
+	db, err := sql.Open(cfg.DBDriver, getMySQLConn(cfg))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return db.PingContext(ctx)
+}
+
 // ==================== //
 // == Network Policy == //
 // ==================== //
@@ -724,6 +749,73 @@ func CreatePolicyTableMySQL(cfg types.ConfigDB) error {
 			"	`policy_name` varchar(150) DEFAULT NULL," +
 			"	`policy_yaml` text DEFAULT NULL," +
 			"	`updated_time` bigint NOT NULL," +
+			"	`revision` bigint DEFAULT 0," +
+			"	`event` varchar(20) DEFAULT ''," +
+			"	`replaces` varchar(150) DEFAULT ''," +
+			"	`replaced_by` varchar(150) DEFAULT ''," +
+			"	PRIMARY KEY (`id`)" +
+			"  );"
+
+	if _, err := db.Exec(query); err != nil {
+		return err
+	}
+
+	// the tables created by a previous version are migrated
+	for _, column := range []struct{ name, definition string }{
+		{"revision", "bigint DEFAULT 0"},
+		{"event", "varchar(20) DEFAULT ''"},
+		{"replaces", "varchar(150) DEFAULT ''"},
+		{"replaced_by", "varchar(150) DEFAULT ''"},
+	} {
+		if err := addColumnIfNotExistsSQL(db, tableName, column.name, column.definition); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func CreateTableConfigurationMySQL(cfg types.ConfigDB) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	tableName := TableConfiguration_TableName
+
+	query :=
+		"CREATE TABLE IF NOT EXISTS `" + tableName + "` (" +
+			"	`id` INTEGER AUTO_INCREMENT," +
+			"	`config_name` varchar(50) NOT NULL," +
+			"	`status` int DEFAULT 0," +
+			"	`config` text DEFAULT NULL," +
+			"	`updated_time` bigint NOT NULL," +
+			"	PRIMARY KEY (`id`)," +
+			"	UNIQUE KEY (`config_name`)" +
+			"  );"
+
+	_, err := db.Exec(query)
+	return err
+}
+
+func CreateTableDiscoveryRunMySQL(cfg types.ConfigDB) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	tableName := TableDiscoveryRun_TableName
+
+	query :=
+		"CREATE TABLE IF NOT EXISTS `" + tableName + "` (" +
+			"	`id` INTEGER AUTO_INCREMENT," +
+			"	`type` varchar(50) NOT NULL," +
+			"	`run_trigger` varchar(50) NOT NULL," +
+			"	`status` varchar(50) NOT NULL," +
+			"	`start_time` bigint NOT NULL," +
+			"	`end_time` bigint DEFAULT 0," +
+			"	`logs_consumed` int DEFAULT 0," +
+			"	`clusters` text DEFAULT NULL," +
+			"	`namespaces` text DEFAULT NULL," +
+			"	`policies_created` int DEFAULT 0," +
+			"	`policies_updated` int DEFAULT 0," +
+			"	`errors` text DEFAULT NULL," +
 			"	PRIMARY KEY (`id`)" +
 			"  );"
 
@@ -1634,8 +1726,8 @@ func GetPolicyYamlsMySQL(cfg types.ConfigDB, policyType string) ([]types.PolicyY
 	var results *sql.Rows
 	var err error
 
-	query := "SELECT type,kind,cluster_name,namespace,labels,policy_name,policy_yaml FROM " + PolicyYaml_TableName
-	query = query + "WHERE type = ?"
+	query := "SELECT type,kind,cluster_name,namespace,labels,policy_name,policy_yaml,revision,event,replaces,replaced_by FROM " + PolicyYaml_TableName
+	query = query + " WHERE type = ?"
 
 	results, err = db.Query(query, policyType)
 	if err != nil {
@@ -1656,6 +1748,10 @@ func GetPolicyYamlsMySQL(cfg types.ConfigDB, policyType string) ([]types.PolicyY
 			&labels,
 			&policy.Name,
 			&policy.Yaml,
+			&policy.Revision,
+			&policy.Event,
+			&policy.Replaces,
+			&policy.ReplacedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -1671,20 +1767,34 @@ func UpdateOrInsertPolicyYamlsMySQL(cfg types.ConfigDB, policies []types.PolicyY
 	db := connectMySQL(cfg)
 	defer db.Close()
 
-	for _, pol := range policies {
-		if err := updateOrInsertPolicyYamlMySQL(pol, db); err != nil {
-			log.Error().Msg(err.Error())
+	if err := setPolicyYamlRevisionsSQL(db, PolicyYaml_TableName, policies); err != nil {
+		return err
+	}
+
+	writeErr := &PolicyYamlsWriteError{}
+	for i := range policies {
+		if err := updateOrInsertPolicyYamlMySQL(&policies[i], db); err != nil {
+			writeErr.add(i, err)
 		}
 	}
 
+	if len(writeErr.Indexes) > 0 {
+		return writeErr
+	}
 	return nil
 }
 
-func updateOrInsertPolicyYamlMySQL(policy types.PolicyYaml, db *sql.DB) error {
+func updateOrInsertPolicyYamlMySQL(policy *types.PolicyYaml, db *sql.DB) error {
 	var err error
-	queryString := ` policy_name = ? `
+	queryString := ` policy_name = ? AND kind = ? `
 
-	query := "UPDATE " + PolicyYaml_TableName + " SET policy_yaml=?, updated_time=? WHERE " + queryString + " "
+	query := "UPDATE " + PolicyYaml_TableName + " SET policy_yaml=?, updated_time=?, revision=?, event=?, replaces=?, replaced_by=? WHERE " + queryString + " "
+
+	// the event is added or updated depending on the existing policy, unless it is set by the caller
+	event := policy.Event
+	if event == "" {
+		event = types.PolicyEventUpdated
+	}
 
 	updateStmt, err := db.Prepare(query)
 	if err != nil {
@@ -1695,7 +1805,12 @@ func updateOrInsertPolicyYamlMySQL(policy types.PolicyYaml, db *sql.DB) error {
 	result, err := updateStmt.Exec(
 		policy.Yaml,
 		ConvertStrToUnixTime("now"),
+		policy.Revision,
+		event,
+		policy.Replaces,
+		policy.ReplacedBy,
 		policy.Name,
+		policy.Kind,
 	)
 	if err != nil {
 		log.Error().Msg(err.Error())
@@ -1705,9 +1820,12 @@ func updateOrInsertPolicyYamlMySQL(policy types.PolicyYaml, db *sql.DB) error {
 	rowsAffected, err := result.RowsAffected()
 
 	if err == nil && rowsAffected == 0 {
+		if policy.Event == "" {
+			event = types.PolicyEventAdded
+		}
 
 		insertStmt, err := db.Prepare("INSERT INTO " + PolicyYaml_TableName +
-			"(type,kind,cluster_name,namespace,labels,policy_name,policy_yaml,updated_time) values(?,?,?,?,?,?,?,?)")
+			"(type,kind,cluster_name,namespace,labels,policy_name,policy_yaml,updated_time,revision,event,replaces,replaced_by) values(?,?,?,?,?,?,?,?,?,?,?,?)")
 		if err != nil {
 			return err
 		}
@@ -1722,12 +1840,19 @@ func updateOrInsertPolicyYamlMySQL(policy types.PolicyYaml, db *sql.DB) error {
 			policy.Name,
 			policy.Yaml,
 			ConvertStrToUnixTime("now"),
+			policy.Revision,
+			event,
+			policy.Replaces,
+			policy.ReplacedBy,
 		)
 		if err != nil {
 			log.Error().Msg(err.Error())
+			return err
 		}
 	}
 
+	policy.Event = event
+
 	return err
 }
 
@@ -1747,3 +1872,67 @@ func UpsertSystemSummaryMySQL(cfg types.ConfigDB, sysSummary map[types.SystemSum
 
 	return nil
 }
+
+// =================== //
+// == Configuration == //
+// =================== //
+
+func AddConfigurationToMySQL(cfg types.ConfigDB, newConfig types.Configuration) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	return addConfigurationSQL(db, TableConfiguration_TableName, newConfig)
+}
+
+func GetConfigurationsFromMySQL(cfg types.ConfigDB, configName string) ([]types.Configuration, error) {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	return getConfigurationsSQL(db, TableConfiguration_TableName, configName)
+}
+
+func UpdateConfigurationToMySQL(cfg types.ConfigDB, configName string, updateConfig types.Configuration) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	return updateConfigurationSQL(db, TableConfiguration_TableName, configName, updateConfig)
+}
+
+func DeleteConfigurationFromMySQL(cfg types.ConfigDB, configName string) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	return deleteConfigurationSQL(db, TableConfiguration_TableName, configName)
+}
+
+func ApplyConfigurationToMySQL(cfg types.ConfigDB, configName string) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	return applyConfigurationSQL(db, TableConfiguration_TableName, configName)
+}
+
+// =================== //
+// == Discovery Run == //
+// =================== //
+
+func AddDiscoveryRunToMySQL(cfg types.ConfigDB, run *types.DiscoveryRun) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	return addDiscoveryRunSQL(db, TableDiscoveryRun_TableName, run)
+}
+
+func UpdateDiscoveryRunToMySQL(cfg types.ConfigDB, run *types.DiscoveryRun) error {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	return updateDiscoveryRunSQL(db, TableDiscoveryRun_TableName, run)
+}
+
+func GetDiscoveryRunsFromMySQL(cfg types.ConfigDB, filter types.DiscoveryRun, limit int) ([]types.DiscoveryRun, error) {
+	db := connectMySQL(cfg)
+	defer db.Close()
+
+	return getDiscoveryRunsSQL(db, TableDiscoveryRun_TableName, filter, limit)
+}
@@ -1,6 +1,7 @@
 package libs
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"strconv"
@@ -21,6 +22,8 @@ const TableSystemLogsSQLite_TableName = "system_logs"
 const TableNetworkLogsSQLite_TableName = "network_logs"
 const PolicyYamlSQLite_TableName = "policy_yaml"
 const TableSystemSummarySQLite = "system_summary"
+const TableConfigurationSQLite_TableName = "auto_policy_config"
+const TableDiscoveryRunSQLite_TableName = "discovery_run"
 
 // ================ //
 // == Connection == //
@@ -71,6 +74,24 @@ func connectSQLite(cfg types.ConfigDB, dbpath string) (db *sql.DB) {
 	return db
 }
 
+// PingSQLite checks the db once, unlike connectSQLite which waits until the db is reachable
+func PingSQLite(cfg types.ConfigDB, timeout time.Duration) error {
+	if MockDBSQLite != nil {
+		return MockDBSQLite.Ping()
+	}
+
+	db, err := sql.Open(cfg.DBDriver, cfg.SQLiteDBPath+"?_journal=OFF")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return db.PingContext(ctx)
+}
+
 // ==================== //
 // == Network Policy == //
 // ==================== //
@@ -695,6 +716,45 @@ func CreatePolicyTableSQLite(cfg types.ConfigDB) error {
 			"	`policy_name` varchar(150) DEFAULT NULL," +
 			"	`policy_yaml` text DEFAULT NULL," +
 			"	`updated_time` bigint NOT NULL," +
+			"	`revision` bigint DEFAULT 0," +
+			"	`event` varchar(20) DEFAULT ''," +
+			"	`replaces` varchar(150) DEFAULT ''," +
+			"	`replaced_by` varchar(150) DEFAULT ''," +
+			"	PRIMARY KEY (`id`)" +
+			"  );"
+
+	if _, err := db.Exec(query); err != nil {
+		return err
+	}
+
+	// the tables created by a previous version are migrated
+	for _, column := range []struct{ name, definition string }{
+		{"revision", "bigint DEFAULT 0"},
+		{"event", "varchar(20) DEFAULT ''"},
+		{"replaces", "varchar(150) DEFAULT ''"},
+		{"replaced_by", "varchar(150) DEFAULT ''"},
+	} {
+		if err := addColumnIfNotExistsSQL(db, tableName, column.name, column.definition); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func CreateTableConfigurationSQLite(cfg types.ConfigDB) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	tableName := TableConfigurationSQLite_TableName
+
+	query :=
+		"CREATE TABLE IF NOT EXISTS `" + tableName + "` (" +
+			"	`id` INTEGER AUTO_INCREMENT," +
+			"	`config_name` varchar(50) NOT NULL UNIQUE," +
+			"	`status` int DEFAULT 0," +
+			"	`config` text DEFAULT NULL," +
+			"	`updated_time` bigint NOT NULL," +
 			"	PRIMARY KEY (`id`)" +
 			"  );"
 
@@ -702,6 +762,33 @@ func CreatePolicyTableSQLite(cfg types.ConfigDB) error {
 	return err
 }
 
+func CreateTableDiscoveryRunSQLite(cfg types.ConfigDB) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	tableName := TableDiscoveryRunSQLite_TableName
+
+	// the id is an alias of the rowid, so that the last insert id is the run id
+	query :=
+		"CREATE TABLE IF NOT EXISTS `" + tableName + "` (" +
+			"	`id` INTEGER PRIMARY KEY AUTOINCREMENT," +
+			"	`type` varchar(50) NOT NULL," +
+			"	`run_trigger` varchar(50) NOT NULL," +
+			"	`status` varchar(50) NOT NULL," +
+			"	`start_time` bigint NOT NULL," +
+			"	`end_time` bigint DEFAULT 0," +
+			"	`logs_consumed` int DEFAULT 0," +
+			"	`clusters` text DEFAULT NULL," +
+			"	`namespaces` text DEFAULT NULL," +
+			"	`policies_created` int DEFAULT 0," +
+			"	`policies_updated` int DEFAULT 0," +
+			"	`errors` text DEFAULT NULL" +
+			"  );"
+
+	_, err := db.Exec(query)
+	return err
+}
+
 func CreateSystemSummaryTableSQLite(cfg types.ConfigDB) error {
 	db := connectSQLite(cfg, config.GetCfgObservabilityDBName())
 	defer db.Close()
@@ -1640,7 +1727,7 @@ func GetPolicyYamlsSQLite(cfg types.ConfigDB, policyType string) ([]types.Policy
 	var results *sql.Rows
 	var err error
 
-	query := "SELECT type,kind,cluster_name,namespace,labels,policy_name,policy_yaml FROM " + PolicyYaml_TableName
+	query := "SELECT type,kind,cluster_name,namespace,labels,policy_name,policy_yaml,revision,event,replaces,replaced_by FROM " + PolicyYaml_TableName
 	query = query + " WHERE type = ?"
 
 	results, err = db.Query(query, policyType)
@@ -1662,6 +1749,10 @@ func GetPolicyYamlsSQLite(cfg types.ConfigDB, policyType string) ([]types.Policy
 			&labels,
 			&policy.Name,
 			&policy.Yaml,
+			&policy.Revision,
+			&policy.Event,
+			&policy.Replaces,
+			&policy.ReplacedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -1677,19 +1768,33 @@ func UpdateOrInsertPolicyYamlsSQLite(cfg types.ConfigDB, policies []types.Policy
 	db := connectSQLite(cfg, cfg.SQLiteDBPath)
 	defer db.Close()
 
-	for _, pol := range policies {
-		if err := updateOrInsertPolicyYamlSQLite(db, pol); err != nil {
-			log.Error().Msg(err.Error())
+	if err := setPolicyYamlRevisionsSQL(db, PolicyYamlSQLite_TableName, policies); err != nil {
+		return err
+	}
+
+	writeErr := &PolicyYamlsWriteError{}
+	for i := range policies {
+		if err := updateOrInsertPolicyYamlSQLite(db, &policies[i]); err != nil {
+			writeErr.add(i, err)
 		}
 	}
 
+	if len(writeErr.Indexes) > 0 {
+		return writeErr
+	}
 	return nil
 }
 
-func updateOrInsertPolicyYamlSQLite(db *sql.DB, policy types.PolicyYaml) error {
+func updateOrInsertPolicyYamlSQLite(db *sql.DB, policy *types.PolicyYaml) error {
 	var err error
+	query := "UPDATE " + PolicyYamlSQLite_TableName + " SET policy_yaml = ?, updated_time = ?, revision = ?, event = ?, replaces = ?, replaced_by = ? WHERE policy_name = ? AND kind = ?"
+
+	// the event is added or updated depending on the existing policy, unless it is set by the caller
+	event := policy.Event
+	if event == "" {
+		event = types.PolicyEventUpdated
+	}
 
-	query := "UPDATE " + PolicyYamlSQLite_TableName + " SET policy_yaml = ?, updated_time = ? WHERE policy_name = ?"
 	updateStmt, err := db.Prepare(query)
 	if err != nil {
 		return err
@@ -1699,7 +1804,12 @@ func updateOrInsertPolicyYamlSQLite(db *sql.DB, policy types.PolicyYaml) error {
 	result, err := updateStmt.Exec(
 		policy.Yaml,
 		ConvertStrToUnixTime("now"),
+		policy.Revision,
+		event,
+		policy.Replaces,
+		policy.ReplacedBy,
 		policy.Name,
+		policy.Kind,
 	)
 	if err != nil {
 		log.Error().Msg(err.Error())
@@ -1709,8 +1819,12 @@ func updateOrInsertPolicyYamlSQLite(db *sql.DB, policy types.PolicyYaml) error {
 	rowsAffected, err := result.RowsAffected()
 
 	if err == nil && rowsAffected == 0 {
+		if policy.Event == "" {
+			event = types.PolicyEventAdded
+		}
+
 		insertStmt, err := db.Prepare("INSERT INTO " + PolicyYamlSQLite_TableName +
-			" (type,kind,cluster_name,namespace,labels,policy_name,policy_yaml,updated_time) values(?,?,?,?,?,?,?,?)")
+			" (type,kind,cluster_name,namespace,labels,policy_name,policy_yaml,updated_time,revision,event,replaces,replaced_by) values(?,?,?,?,?,?,?,?,?,?,?,?)")
 		if err != nil {
 			return err
 		}
@@ -1725,12 +1839,19 @@ func updateOrInsertPolicyYamlSQLite(db *sql.DB, policy types.PolicyYaml) error {
 			policy.Name,
 			policy.Yaml,
 			ConvertStrToUnixTime("now"),
+			policy.Revision,
+			event,
+			policy.Replaces,
+			policy.ReplacedBy,
 		)
 		if err != nil {
 			log.Error().Msg(err.Error())
+			return err
 		}
 	}
 
+	policy.Event = event
+
 	return err
 }
 
@@ -1750,3 +1871,67 @@ func UpsertSystemSummarySQLite(cfg types.ConfigDB, sysSummary map[types.SystemSu
 
 	return nil
 }
+
+// =================== //
+// == Configuration == //
+// =================== //
+
+func AddConfigurationToSQLite(cfg types.ConfigDB, newConfig types.Configuration) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	return addConfigurationSQL(db, TableConfigurationSQLite_TableName, newConfig)
+}
+
+func GetConfigurationsFromSQLite(cfg types.ConfigDB, configName string) ([]types.Configuration, error) {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	return getConfigurationsSQL(db, TableConfigurationSQLite_TableName, configName)
+}
+
+func UpdateConfigurationToSQLite(cfg types.ConfigDB, configName string, updateConfig types.Configuration) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	return updateConfigurationSQL(db, TableConfigurationSQLite_TableName, configName, updateConfig)
+}
+
+func DeleteConfigurationFromSQLite(cfg types.ConfigDB, configName string) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	return deleteConfigurationSQL(db, TableConfigurationSQLite_TableName, configName)
+}
+
+func ApplyConfigurationToSQLite(cfg types.ConfigDB, configName string) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	return applyConfigurationSQL(db, TableConfigurationSQLite_TableName, configName)
+}
+
+// =================== //
+// == Discovery Run == //
+// =================== //
+
+func AddDiscoveryRunToSQLite(cfg types.ConfigDB, run *types.DiscoveryRun) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	return addDiscoveryRunSQL(db, TableDiscoveryRunSQLite_TableName, run)
+}
+
+func UpdateDiscoveryRunToSQLite(cfg types.ConfigDB, run *types.DiscoveryRun) error {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	return updateDiscoveryRunSQL(db, TableDiscoveryRunSQLite_TableName, run)
+}
+
+func GetDiscoveryRunsFromSQLite(cfg types.ConfigDB, filter types.DiscoveryRun, limit int) ([]types.DiscoveryRun, error) {
+	db := connectSQLite(cfg, cfg.SQLiteDBPath)
+	defer db.Close()
+
+	return getDiscoveryRunsSQL(db, TableDiscoveryRunSQLite_TableName, filter, limit)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"math/rand"
 	"net"
+	"net/http"
 	"os"
 	"time"
 
@@ -38,7 +39,17 @@ func init() {
 	// 3. setup the tables in db
 	libs.CreateTablesIfNotExist(config.GetCfgDB())
 
-	// 4. Seed random number generator
+	// 4. use the configuration applied through the config store, if any
+	appliedCfg, err := libs.GetAppliedConfiguration(config.GetCfgDB())
+	if err != nil {
+		log.Error().Msgf("Failed to get the applied configuration: %v", err)
+	} else if appliedCfg != nil {
+		config.SetConfigurationDefaults(appliedCfg)
+		config.SetCurrentCfg(*appliedCfg)
+		log.Info().Msgf("Configuration [%s] applied from db", appliedCfg.ConfigName)
+	}
+
+	// 5. Seed random number generator
 	rand.Seed(time.Now().UnixNano())
 }
 
@@ -55,9 +66,42 @@ func main() {
 	}
 	server := grpcserver.GetNewServer()
 
+	// start prometheus metrics endpoint
+	metricsServer := grpcserver.GetNewMetricsServer()
+	if metricsServer != nil {
+		go func() {
+			log.Info().Msgf("Metrics server on %s started", metricsServer.Addr)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Error().Msgf("Metrics server failed to serve: %v", err)
+			}
+		}()
+	}
+
+	// start rest gateway
+	gateway := grpcserver.GetNewHTTPGateway()
+	if gateway != nil {
+		go func() {
+			log.Info().Msgf("REST gateway on %s started", gateway.Addr)
+			if err := grpcserver.ServeHTTPGateway(gateway); err != nil && err != http.ErrServerClosed {
+				log.Error().Msgf("REST gateway failed to serve: %v", err)
+			}
+		}()
+	}
+
 	// start autopolicy service
-	log.Info().Msgf("gRPC server on %s port started", grpcserver.PortNumber)
-	if err := server.Serve(lis); err != nil {
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Info().Msgf("gRPC server on %s port started", grpcserver.PortNumber)
+		serveErr <- server.Serve(lis)
+	}()
+
+	// wait for a termination signal, then shut down gracefully
+	select {
+	case sig := <-libs.GetOSSigChannel():
+		log.Info().Msgf("Got signal [%v]", sig)
+		grpcserver.Shutdown(server, gateway, metricsServer)
+	case err := <-serveErr:
 		log.Error().Msgf("Failed to serve: %v", err)
+		os.Exit(1)
 	}
 }
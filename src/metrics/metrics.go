@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "knoxautopolicy"
+
+// buffer label values
+const (
+	BufferCiliumFlows     = "cilium_flows"
+	BufferCiliumFlowsFC   = "cilium_flows_feed_consumer"
+	BufferKubeArmorLogs   = "kubearmor_relay_logs"
+	BufferKubeArmorLogsFC = "kubearmor_logs_feed_consumer"
+	BufferFeedConsumerNet = "feed_consumer_network_events"
+	BufferFeedConsumerSys = "feed_consumer_system_events"
+)
+
+// policy type, result and stream service label values
+const (
+	PolicyTypeNetwork = "network"
+	PolicyTypeSystem  = "system"
+
+	PolicyResultInserted = "inserted"
+	PolicyResultUpdated  = "updated"
+
+	StreamDiscovery = "discovery"
+	StreamPublisher = "publisher"
+)
+
+var (
+	// BufferedLogs is the number of flows/logs waiting in each buffer for the next discovery run
+	BufferedLogs = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "buffered_logs",
+		Help:      "Number of flows/logs buffered for the next discovery run.",
+	}, []string{"buffer"})
+
+	// DiscoveryDuration is the time taken to discover the policies of a namespace
+	DiscoveryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "discovery_duration_seconds",
+		Help:      "Duration of the policy discovery per cluster and namespace.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 4, 8),
+	}, []string{"type", "cluster", "namespace"})
+
+	// DiscoveredPolicies counts the policies newly inserted or updated by the deduplication
+	DiscoveredPolicies = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "discovered_policies_total",
+		Help:      "Number of discovered policies, newly inserted or updated from existing ones.",
+	}, []string{"type", "cluster", "result"})
+
+	// DBQueryDuration is the latency of each db handler
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Latency of the db handlers.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"driver", "handler"})
+
+	// StreamConsumers is the number of connected streaming consumers
+	StreamConsumers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "stream_consumers",
+		Help:      "Number of connected Discovery/Publisher stream consumers.",
+	}, []string{"service"})
+
+	// PolicyStreamLagging is the number of Discovery followers whose queue overflowed
+	PolicyStreamLagging = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "policy_stream_lagging_consumers",
+		Help:      "Number of Discovery stream followers lagging behind the published policies.",
+	})
+
+	// PolicyStreamDropped counts the policies dropped from the queue of the lagging followers
+	PolicyStreamDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "policy_stream_dropped_total",
+		Help:      "Number of policies dropped from the queue of lagging Discovery stream followers.",
+	})
+
+	// PolicyStreamDisconnected counts the followers disconnected because their queue overflowed
+	PolicyStreamDisconnected = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "policy_stream_disconnected_total",
+		Help:      "Number of Discovery stream followers disconnected because their queue overflowed.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		BufferedLogs,
+		DiscoveryDuration,
+		DiscoveredPolicies,
+		DBQueryDuration,
+		StreamConsumers,
+		PolicyStreamLagging,
+		PolicyStreamDropped,
+		PolicyStreamDisconnected,
+	)
+}
+
+// ================= //
+// == Observation == //
+// ================= //
+
+// SetBufferedLogs sets the current size of the buffer
+func SetBufferedLogs(buffer string, size int) {
+	BufferedLogs.WithLabelValues(buffer).Set(float64(size))
+}
+
+// AddBufferedLogs adds the delta to the size of the buffer, for the buffers shared by several goroutines
+func AddBufferedLogs(buffer string, delta int) {
+	BufferedLogs.WithLabelValues(buffer).Add(float64(delta))
+}
+
+// ObserveDiscovery records the discovery duration of a namespace since the start time
+func ObserveDiscovery(policyType, cluster, namespace string, start time.Time) {
+	DiscoveryDuration.WithLabelValues(policyType, cluster, namespace).Observe(time.Since(start).Seconds())
+}
+
+// AddDiscoveredPolicies counts the inserted and updated policies
+func AddDiscoveredPolicies(policyType, cluster string, inserted, updated int) {
+	DiscoveredPolicies.WithLabelValues(policyType, cluster, PolicyResultInserted).Add(float64(inserted))
+	DiscoveredPolicies.WithLabelValues(policyType, cluster, PolicyResultUpdated).Add(float64(updated))
+}
+
+// ObserveDBQuery records the latency of a db handler since the start time, to be deferred
+func ObserveDBQuery(driver, handler string, start time.Time) {
+	DBQueryDuration.WithLabelValues(driver, handler).Observe(time.Since(start).Seconds())
+}
+
+// ============= //
+// == Handler == //
+// ============= //
+
+// Handler returns the http handler of the /metrics endpoint
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
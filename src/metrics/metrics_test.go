@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferedLogs(t *testing.T) {
+	SetBufferedLogs(BufferCiliumFlows, 10)
+	assert.Equal(t, float64(10), testutil.ToFloat64(BufferedLogs.WithLabelValues(BufferCiliumFlows)))
+
+	AddBufferedLogs(BufferFeedConsumerNet, 5)
+	AddBufferedLogs(BufferFeedConsumerNet, -3)
+	assert.Equal(t, float64(2), testutil.ToFloat64(BufferedLogs.WithLabelValues(BufferFeedConsumerNet)))
+}
+
+func TestDiscoveredPolicies(t *testing.T) {
+	AddDiscoveredPolicies(PolicyTypeNetwork, "test", 3, 1)
+	AddDiscoveredPolicies(PolicyTypeNetwork, "test", 2, 0)
+
+	assert.Equal(t, float64(5), testutil.ToFloat64(DiscoveredPolicies.WithLabelValues(PolicyTypeNetwork, "test", PolicyResultInserted)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(DiscoveredPolicies.WithLabelValues(PolicyTypeNetwork, "test", PolicyResultUpdated)))
+}
+
+func TestHandler(t *testing.T) {
+	ObserveDBQuery("sqlite3", "GetNetworkPolicies", time.Now())
+	ObserveDiscovery(PolicyTypeSystem, "test", "default", time.Now())
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.True(t, strings.Contains(body, `knoxautopolicy_db_query_duration_seconds_count{driver="sqlite3",handler="GetNetworkPolicies"} 1`))
+	assert.True(t, strings.Contains(body, `knoxautopolicy_discovery_duration_seconds_count{cluster="test",namespace="default",type="system"} 1`))
+}
@@ -0,0 +1,256 @@
+package networkpolicy
+
+import (
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"sigs.k8s.io/yaml"
+)
+
+// CIDRAggregationMinIPs is the number of external addresses a supernet collapses, less than 2 disables it
+var CIDRAggregationMinIPs int
+
+// CIDRAggregationMaxPrefix and CIDRAggregationMaxPrefixV6 are the widest supernet prefixes
+var CIDRAggregationMaxPrefix int
+var CIDRAggregationMaxPrefixV6 int
+
+// CIDRProviderRanges are the named ranges the external addresses are widened to
+var CIDRProviderRanges []CIDRProviderRange
+
+// CIDRProviderRange is a named set of networks, e.g., the published ranges of a SaaS provider
+type CIDRProviderRange struct {
+	Name  string   `json:"name"`
+	CIDRs []string `json:"cidrs"`
+}
+
+// LoadCIDRProviderRanges reads the yaml list of the provider ranges
+func LoadCIDRProviderRanges(path string) ([]CIDRProviderRange, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := []CIDRProviderRange{}
+	if err := yaml.Unmarshal(data, &ranges); err != nil {
+		return nil, err
+	}
+
+	for _, providerRange := range ranges {
+		for _, cidr := range providerRange.CIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return ranges, nil
+}
+
+// ====================== //
+// == CIDR aggregation == //
+// ====================== //
+
+// aggregatedNet is a network of the aggregation, with the number of the addresses observed in it
+type aggregatedNet struct {
+	network *net.IPNet
+	weight  int
+
+	// final networks are aggregated or provider networks, they are not widened further
+	final bool
+}
+
+func (a aggregatedNet) prefixLen() int {
+	ones, _ := a.network.Mask.Size()
+	return ones
+}
+
+func (a aggregatedNet) contains(b aggregatedNet) bool {
+	return a.prefixLen() <= b.prefixLen() && a.network.Contains(b.network.IP)
+}
+
+func isCIDRAggregationEnabled() bool {
+	return CIDRAggregationMinIPs >= 2 || len(CIDRProviderRanges) > 0
+}
+
+// providerNetwork returns the provider range containing the network
+func providerNetwork(network *net.IPNet) (*net.IPNet, bool) {
+	for _, providerRange := range CIDRProviderRanges {
+		for _, cidr := range providerRange.CIDRs {
+			_, providerNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				continue
+			}
+
+			ones, _ := network.Mask.Size()
+			providerOnes, _ := providerNet.Mask.Size()
+			if providerOnes <= ones && providerNet.Contains(network.IP) {
+				log.Debug().Msgf("%s widened to the range %s of %s", network.String(), cidr, providerRange.Name)
+				return providerNet, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// removeContainedNets removes the networks contained in another one, their weights are added to it
+func removeContainedNets(nets []aggregatedNet) []aggregatedNet {
+	sort.Slice(nets, func(i, j int) bool {
+		return nets[i].prefixLen() < nets[j].prefixLen()
+	})
+
+	res := []aggregatedNet{}
+	for _, n := range nets {
+		contained := false
+		for i := range res {
+			if res[i].contains(n) {
+				res[i].weight += n.weight
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// aggregateNets collapses the host networks of a family into the narrowest supernets covering minIPs of them,
+// no wider than maxPrefix
+func aggregateNets(nets []aggregatedNet, hostBits, maxPrefix, minIPs int) []aggregatedNet {
+	nets = removeContainedNets(nets)
+	if minIPs < 2 {
+		return nets
+	}
+
+	for bits := hostBits - 1; bits >= maxPrefix; bits-- {
+		mask := net.CIDRMask(bits, hostBits)
+
+		weights := map[string]int{}
+		for _, n := range nets {
+			if !n.final && n.prefixLen() > bits {
+				weights[n.network.IP.Mask(mask).String()] += n.weight
+			}
+		}
+
+		for ip, weight := range weights {
+			if weight < minIPs {
+				continue
+			}
+			supernet := aggregatedNet{network: &net.IPNet{IP: net.ParseIP(ip).Mask(mask), Mask: mask}, final: true}
+			nets = removeContainedNets(append(nets, supernet))
+		}
+	}
+
+	return nets
+}
+
+// aggregateCIDRs collapses the cidrs into the provider ranges and supernets, the invalid cidrs are kept as they are
+func aggregateCIDRs(cidrs []string) []string {
+	res := []string{}
+	v4Nets := []aggregatedNet{}
+	v6Nets := []aggregatedNet{}
+
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			if !libs.ContainsElement(res, cidr) {
+				res = append(res, cidr)
+			}
+			continue
+		}
+
+		hostBits := CIDRBits
+		if network.IP.To4() == nil {
+			hostBits = CIDRv6Bits
+		}
+
+		n := aggregatedNet{network: network, weight: 1}
+		if providerNet, ok := providerNetwork(network); ok {
+			n.network = providerNet
+			n.final = true
+		} else if n.prefixLen() != hostBits {
+			// the networks stored by a previous aggregation
+			n.final = true
+		}
+
+		if network.IP.To4() != nil {
+			v4Nets = append(v4Nets, n)
+		} else {
+			v6Nets = append(v6Nets, n)
+		}
+	}
+
+	nets := aggregateNets(v4Nets, CIDRBits, CIDRAggregationMaxPrefix, CIDRAggregationMinIPs)
+	nets = append(nets, aggregateNets(v6Nets, CIDRv6Bits, CIDRAggregationMaxPrefixV6, CIDRAggregationMinIPs)...)
+
+	sort.Slice(nets, func(i, j int) bool {
+		if len(nets[i].network.IP) != len(nets[j].network.IP) {
+			return len(nets[i].network.IP) < len(nets[j].network.IP)
+		}
+		if c := strings.Compare(string(nets[i].network.IP), string(nets[j].network.IP)); c != 0 {
+			return c < 0
+		}
+		return nets[i].prefixLen() < nets[j].prefixLen()
+	})
+
+	for _, n := range nets {
+		res = append(res, n.network.String())
+	}
+	return res
+}
+
+// cidrsContained checks if each cidr is contained in one of the networks
+func cidrsContained(cidrs, networks []string) bool {
+	for _, cidr := range cidrs {
+		if libs.ContainsElement(networks, cidr) {
+			continue
+		}
+
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return false
+		}
+
+		contained := false
+		for _, network := range networks {
+			_, supernet, err := net.ParseCIDR(network)
+			if err != nil {
+				continue
+			}
+			if (aggregatedNet{network: supernet}).contains(aggregatedNet{network: n}) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			return false
+		}
+	}
+	return true
+}
+
+// aggregateEgressCIDRs merges the toCIDRs rules of the same ports into a single rule of the aggregated cidrs,
+// the rules with l7 rules or excepted cidrs are kept as they are
+func aggregateEgressCIDRs(policy *types.KnoxNetworkPolicy) {
+	if !isCIDRAggregationEnabled() {
+		return
+	}
+
+	isCIDRRule := func(egress types.Egress) bool {
+		return len(egress.ToCIDRs) == 1 && len(egress.ToCIDRs[0].Except) == 0 &&
+			len(egress.ToHTTPs) == 0 && len(egress.ToKafkas) == 0 && len(egress.ToFQDNs) == 0
+	}
+
+	aggregateEgressPeers(policy, isCIDRRule, func(merged *types.Egress, rules []types.Egress) {
+		cidrs := []string{}
+		for _, rule := range rules {
+			cidrs = append(cidrs, rule.ToCIDRs[0].CIDRs...)
+		}
+		merged.ToCIDRs = []types.SpecCIDR{{CIDRs: aggregateCIDRs(cidrs)}}
+	})
+}
@@ -0,0 +1,117 @@
+package networkpolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cfg "github.com/accuknox/auto-policy-discovery/src/config"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func setCIDRAggregation(t *testing.T, minIPs, maxPrefix, maxPrefixV6 int, ranges []CIDRProviderRange) {
+	CIDRBits, CIDRv6Bits = 32, 128
+	CIDRAggregationMinIPs, CIDRAggregationMaxPrefix, CIDRAggregationMaxPrefixV6 = minIPs, maxPrefix, maxPrefixV6
+	CIDRProviderRanges = ranges
+
+	t.Cleanup(func() {
+		CIDRAggregationMinIPs, CIDRAggregationMaxPrefix, CIDRAggregationMaxPrefixV6 = 0, 0, 0
+		CIDRProviderRanges = nil
+	})
+}
+
+// ====================== //
+// == CIDR aggregation == //
+// ====================== //
+
+func TestAggregateCIDRs(t *testing.T) {
+	setCIDRAggregation(t, 3, 16, 64, nil)
+
+	// the narrowest supernet of 3 addresses, the stray address is not absorbed
+	actual := aggregateCIDRs([]string{"1.2.3.4/32", "1.2.3.9/32", "1.2.3.200/32", "1.2.200.1/32", "8.8.8.8/32"})
+	assert.Equal(t, []string{"1.2.3.0/24", "1.2.200.1/32", "8.8.8.8/32"}, actual)
+
+	// the supernet is no wider than the max prefix
+	actual = aggregateCIDRs([]string{"1.2.3.4/32", "1.3.3.4/32", "1.4.3.4/32"})
+	assert.Equal(t, []string{"1.2.3.4/32", "1.3.3.4/32", "1.4.3.4/32"}, actual)
+
+	// the addresses of a previous supernet are merged into it
+	actual = aggregateCIDRs([]string{"1.2.3.0/24", "1.2.3.77/32", "2001:db8::1/128", "2001:db8::2/128", "2001:db8::3/128"})
+	assert.Equal(t, []string{"1.2.3.0/24", "2001:db8::/126"}, actual)
+}
+
+func TestAggregateCIDRsProviderRanges(t *testing.T) {
+	setCIDRAggregation(t, 0, 24, 64, []CIDRProviderRange{{Name: "github", CIDRs: []string{"140.82.112.0/20"}}})
+
+	actual := aggregateCIDRs([]string{"140.82.113.3/32", "140.82.121.4/32", "10.0.0.1/32", "invalid"})
+	assert.Equal(t, []string{"invalid", "10.0.0.1/32", "140.82.112.0/20"}, actual)
+}
+
+func TestLoadCIDRProviderRanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ranges.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("- name: github\n  cidrs:\n    - 140.82.112.0/20\n"), 0600))
+
+	ranges, err := LoadCIDRProviderRanges(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []CIDRProviderRange{{Name: "github", CIDRs: []string{"140.82.112.0/20"}}}, ranges)
+
+	assert.NoError(t, os.WriteFile(path, []byte("- name: bad\n  cidrs:\n    - 140.82.112.0\n"), 0600))
+	_, err = LoadCIDRProviderRanges(path)
+	assert.Error(t, err)
+}
+
+func TestAggregateEgressCIDRs(t *testing.T) {
+	setCIDRAggregation(t, 2, 24, 64, nil)
+
+	https := []types.SpecPort{{Port: "443", Protocol: "tcp"}}
+	policy := types.KnoxNetworkPolicy{}
+	policy.Spec.Egress = []types.Egress{
+		{ToCIDRs: []types.SpecCIDR{{CIDRs: []string{"1.2.3.4/32"}}}, ToPorts: https},
+		{ToEntities: []string{"kube-apiserver"}},
+		{ToCIDRs: []types.SpecCIDR{{CIDRs: []string{"1.2.3.5/32"}}}, ToPorts: https},
+		{ToCIDRs: []types.SpecCIDR{{CIDRs: []string{"1.2.3.6/32"}}}, ToPorts: []types.SpecPort{{Port: "80", Protocol: "tcp"}}},
+	}
+
+	aggregateEgressCIDRs(&policy)
+
+	assert.Len(t, policy.Spec.Egress, 3)
+	assert.Equal(t, []string{"1.2.3.4/31"}, policy.Spec.Egress[0].ToCIDRs[0].CIDRs)
+	assert.Equal(t, []string{"1.2.3.6/32"}, policy.Spec.Egress[2].ToCIDRs[0].CIDRs)
+
+	// a new address of the supernet does not update the policy
+	newPolicy := types.KnoxNetworkPolicy{}
+	newPolicy.Spec.Egress = []types.Egress{{ToCIDRs: []types.SpecCIDR{{CIDRs: []string{"1.2.3.5/32"}}}, ToPorts: https}}
+
+	_, updated := mergeEgressPolicies(policy, []types.KnoxNetworkPolicy{newPolicy})
+	assert.False(t, updated)
+}
+
+func TestGetWorldCIDR(t *testing.T) {
+	ruleTypes := cfg.CurrentCfg.ConfigNetPolicy.NetPolicyRuleTypes
+	cfg.CurrentCfg.ConfigNetPolicy.NetPolicyRuleTypes = TO_CIDRS
+	t.Cleanup(func() {
+		cfg.CurrentCfg.ConfigNetPolicy.NetPolicyRuleTypes = ruleTypes
+		WorldCIDRs = false
+	})
+
+	// the world entity is kept by default
+	setCIDRAggregation(t, 0, 24, 64, nil)
+	_, ok := getWorldCIDR("world", "1.2.3.4")
+	assert.False(t, ok)
+
+	WorldCIDRs = true
+	cidr, ok := getWorldCIDR("world", "1.2.3.4")
+	assert.True(t, ok)
+	assert.Equal(t, "1.2.3.4/32", cidr)
+
+	_, ok = getWorldCIDR("host", "1.2.3.4")
+	assert.False(t, ok)
+
+	// the aggregation needs the world addresses
+	WorldCIDRs = false
+	setCIDRAggregation(t, 2, 24, 64, nil)
+	cidr, ok = getWorldCIDR("world", "2001:db8::1")
+	assert.True(t, ok)
+	assert.Equal(t, "2001:db8::1/128", cidr)
+}
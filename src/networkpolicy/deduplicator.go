@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/metrics"
 	types "github.com/accuknox/auto-policy-discovery/src/types"
 
 	"github.com/google/go-cmp/cmp"
@@ -80,17 +81,12 @@ func GetLatestFQDNPolicy(existingPolicies []types.KnoxNetworkPolicy, policy type
 				continue
 			}
 
-			// check FQDN list
-			matchFQDN := true
-			for _, dns := range policy.Spec.Egress[0].ToFQDNs[0].MatchNames {
-				for _, existDNS := range exist.Spec.Egress[0].ToFQDNs[0].MatchNames {
-					if dns != existDNS {
-						matchFQDN = false
-					}
-				}
+			// check FQDN list, the names may be matched by the patterns
+			if len(policy.Spec.Egress) == 0 || len(exist.Spec.Egress) == 0 {
+				continue
 			}
 
-			if matchFQDN {
+			if fqdnsContained(policy.Spec.Egress[0].ToFQDNs, exist.Spec.Egress[0].ToFQDNs) {
 				latestPolicies = append(latestPolicies, exist)
 			}
 		}
@@ -122,13 +118,13 @@ func GetLatestHTTPPolicy(existingPolicies []types.KnoxNetworkPolicy, policy type
 			// check matchLabels & toPorts
 			newMatchLabels := map[string]string{}
 			newCIDRs := []string{}
-			newFQDNs := []string{}
+			newFQDNs := []types.SpecFQDN{}
 			newEntities := []string{}
 			newToPorts := []types.SpecPort{}
 
 			existMatchLabels := map[string]string{}
 			existCIDRs := []string{}
-			existFQDNs := []string{}
+			existFQDNs := []types.SpecFQDN{}
 			existEntities := []string{}
 			existToPorts := []types.SpecPort{}
 
@@ -138,7 +134,7 @@ func GetLatestHTTPPolicy(existingPolicies []types.KnoxNetworkPolicy, policy type
 					newCIDRs = policy.Spec.Egress[0].ToCIDRs[0].CIDRs
 				}
 				if strings.Contains(rule, "toFQDNs") {
-					newFQDNs = policy.Spec.Egress[0].ToFQDNs[0].MatchNames
+					newFQDNs = policy.Spec.Egress[0].ToFQDNs
 				}
 				newEntities = policy.Spec.Egress[0].ToEntities
 				newToPorts = policy.Spec.Egress[0].ToPorts
@@ -148,7 +144,7 @@ func GetLatestHTTPPolicy(existingPolicies []types.KnoxNetworkPolicy, policy type
 					existCIDRs = exist.Spec.Egress[0].ToCIDRs[0].CIDRs
 				}
 				if strings.Contains(rule, "toFQDNs") {
-					existFQDNs = exist.Spec.Egress[0].ToFQDNs[0].MatchNames
+					existFQDNs = exist.Spec.Egress[0].ToFQDNs
 				}
 				existEntities = exist.Spec.Egress[0].ToEntities
 				existToPorts = exist.Spec.Egress[0].ToPorts
@@ -188,14 +184,7 @@ func GetLatestHTTPPolicy(existingPolicies []types.KnoxNetworkPolicy, policy type
 				}
 			} else if strings.Contains(rule, "toFQDNs") && policyType == PolicyTypeEgress {
 				// check FQDNs
-				matchFQDNs := true
-				for _, fqdn := range newFQDNs {
-					if !libs.ContainsElement(existFQDNs, fqdn) {
-						matchFQDNs = false
-						break
-					}
-				}
-				if !matchFQDNs {
+				if !fqdnsContained(newFQDNs, existFQDNs) {
 					continue
 				}
 			} else if strings.Contains(rule, "toEntities") || strings.Contains(rule, "fromEntities") {
@@ -316,13 +305,15 @@ func updateOutdatedPolicy(outdatedPolicy types.KnoxNetworkPolicy, newPolicy *typ
 	}
 
 	libs.UpdateOutdatedNetworkPolicy(CfgDB, outdatedPolicy.Metadata["name"], newPolicy.Metadata["name"])
+	setReplacedPolicy(outdatedPolicy.Metadata["name"], newPolicy.Metadata["name"])
 }
 
 func includedHTTPPath(httpRules []types.SpecHTTP, targetRule types.SpecHTTP) bool {
 	included := false
 
 	for _, httpRule := range httpRules {
-		if httpRule.Method != targetRule.Method {
+		if httpRule.Method != targetRule.Method || httpRule.Host != targetRule.Host ||
+			!cmp.Equal(httpRule.Headers, targetRule.Headers) {
 			continue
 		}
 
@@ -815,7 +806,7 @@ func GetFQDNFromDomainName(domainName string, fqdnPolicies []types.KnoxNetworkPo
 	for _, policy := range fqdnPolicies {
 		for _, egress := range policy.Spec.Egress {
 			for _, fqdn := range egress.ToFQDNs {
-				if libs.ContainsElement(fqdn.MatchNames, domainName) {
+				if libs.ContainsElement(fqdn.MatchNames, domainName) || fqdnMatchesPatterns(fqdn.MatchPatterns, domainName) {
 					return policy, true
 				}
 			}
@@ -892,6 +883,7 @@ func updateExistCIDRtoNewFQDN(existingPolicies []types.KnoxNetworkPolicy, newPol
 						}
 
 						libs.UpdateOutdatedNetworkPolicy(CfgDB, existCIDR.Metadata["name"], fqdnPolicy.Metadata["name"])
+						setReplacedPolicy(existCIDR.Metadata["name"], fqdnPolicy.Metadata["name"])
 					}
 				}
 			}
@@ -947,6 +939,8 @@ func UpdateDuplicatedPolicy(existingPolicies []types.KnoxNetworkPolicy, discover
 				// Ingress policy for this endpoint exists already
 				mergedPolicy, updated := mergeIngressPolicies(existPolicy, []types.KnoxNetworkPolicy{newPolicy})
 				if updated {
+					aggregateHTTPPaths(&mergedPolicy)
+					aggregatePortRanges(&mergedPolicy)
 					mergedPolicy.Metadata["status"] = "updated"
 					existIngressPolicies[selector] = mergedPolicy
 				}
@@ -961,6 +955,10 @@ func UpdateDuplicatedPolicy(existingPolicies []types.KnoxNetworkPolicy, discover
 				// Egress policy for this endpoint exists already
 				mergedPolicy, updated := mergeEgressPolicies(existPolicy, []types.KnoxNetworkPolicy{newPolicy})
 				if updated {
+					aggregateEgressCIDRs(&mergedPolicy)
+					aggregateEgressFQDNs(&mergedPolicy)
+					aggregateHTTPPaths(&mergedPolicy)
+					aggregatePortRanges(&mergedPolicy)
 					mergedPolicy.Metadata["status"] = "updated"
 					existEgressPolicies[selector] = mergedPolicy
 				}
@@ -985,5 +983,8 @@ func UpdateDuplicatedPolicy(existingPolicies []types.KnoxNetworkPolicy, discover
 		}
 	}
 
+	metrics.AddDiscoveredPolicies(metrics.PolicyTypeNetwork, clusterName, len(newPolicies), len(updatedPolicies))
+	currentRun.AddPolicies(len(newPolicies), len(updatedPolicies))
+
 	return newPolicies, updatedPolicies
 }
@@ -0,0 +1,137 @@
+package networkpolicy
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"golang.org/x/net/publicsuffix"
+)
+
+// FQDNPatternMinNames is the number of names of a domain a wildcard pattern replaces, less than 2 disables it
+var FQDNPatternMinNames int
+
+// ====================== //
+// == FQDN aggregation == //
+// ====================== //
+
+// fqdnPatternDomain returns the parent domain of the name, if it is the registrable domain or under it,
+// e.g., vendor.com of api.vendor.com, but not com of vendor.com
+func fqdnPatternDomain(name string) (string, bool) {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(name)
+	if err != nil || name == registrable {
+		return "", false
+	}
+
+	return name[strings.Index(name, ".")+1:], true
+}
+
+// fqdnMatchesPattern checks if the name matches the pattern, the wildcard matches a single label as cilium does
+func fqdnMatchesPattern(pattern, name string) bool {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	if !strings.HasPrefix(pattern, "*.") {
+		return pattern == name
+	}
+
+	label := strings.TrimSuffix(name, pattern[1:])
+	return label != name && label != "" && !strings.Contains(label, ".")
+}
+
+func fqdnMatchesPatterns(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if fqdnMatchesPattern(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// aggregateFQDNs merges the fqdns, the names of a domain are replaced by its wildcard pattern
+// if there are minNames of them at least, the names the patterns match are removed
+func aggregateFQDNs(fqdns []types.SpecFQDN, minNames int) types.SpecFQDN {
+	res := types.SpecFQDN{}
+	names := []string{}
+
+	for _, fqdn := range fqdns {
+		for _, pattern := range fqdn.MatchPatterns {
+			if !libs.ContainsElement(res.MatchPatterns, pattern) {
+				res.MatchPatterns = append(res.MatchPatterns, pattern)
+			}
+		}
+		for _, name := range fqdn.MatchNames {
+			if !libs.ContainsElement(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	if minNames >= 2 {
+		namesPerDomain := map[string]int{}
+		for _, name := range names {
+			if domain, ok := fqdnPatternDomain(name); ok {
+				namesPerDomain[domain]++
+			}
+		}
+
+		for domain, count := range namesPerDomain {
+			pattern := "*." + domain
+			if count >= minNames && !libs.ContainsElement(res.MatchPatterns, pattern) {
+				res.MatchPatterns = append(res.MatchPatterns, pattern)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if !fqdnMatchesPatterns(res.MatchPatterns, name) {
+			res.MatchNames = append(res.MatchNames, name)
+		}
+	}
+
+	sort.Strings(res.MatchNames)
+	sort.Strings(res.MatchPatterns)
+
+	return res
+}
+
+// fqdnsContained checks if each name of the fqdns is in the existing fqdns or matches their patterns,
+// and each pattern is in the existing fqdns
+func fqdnsContained(fqdns, existFQDNs []types.SpecFQDN) bool {
+	exist := aggregateFQDNs(existFQDNs, 0)
+
+	for _, fqdn := range fqdns {
+		for _, name := range fqdn.MatchNames {
+			if !libs.ContainsElement(exist.MatchNames, name) && !fqdnMatchesPatterns(exist.MatchPatterns, name) {
+				return false
+			}
+		}
+		for _, pattern := range fqdn.MatchPatterns {
+			if !libs.ContainsElement(exist.MatchPatterns, pattern) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// aggregateEgressFQDNs merges the toFQDNs rules of the same ports into a single rule of the names and patterns,
+// the rules with l7 rules are kept as they are
+func aggregateEgressFQDNs(policy *types.KnoxNetworkPolicy) {
+	if FQDNPatternMinNames < 2 {
+		return
+	}
+
+	isFQDNRule := func(egress types.Egress) bool {
+		return len(egress.ToFQDNs) > 0 && len(egress.ToHTTPs) == 0 && len(egress.ToKafkas) == 0
+	}
+
+	aggregateEgressPeers(policy, isFQDNRule, func(merged *types.Egress, rules []types.Egress) {
+		fqdns := []types.SpecFQDN{}
+		for _, rule := range rules {
+			fqdns = append(fqdns, rule.ToFQDNs...)
+		}
+		merged.ToFQDNs = []types.SpecFQDN{aggregateFQDNs(fqdns, FQDNPatternMinNames)}
+	})
+}
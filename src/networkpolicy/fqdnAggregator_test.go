@@ -0,0 +1,81 @@
+package networkpolicy
+
+import (
+	"testing"
+
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func setFQDNPatternMinNames(t *testing.T, minNames int) {
+	FQDNPatternMinNames = minNames
+	t.Cleanup(func() {
+		FQDNPatternMinNames = 0
+	})
+}
+
+// ====================== //
+// == FQDN aggregation == //
+// ====================== //
+
+func TestFQDNMatchesPattern(t *testing.T) {
+	assert.True(t, fqdnMatchesPattern("*.vendor.com", "api.vendor.com"))
+	assert.True(t, fqdnMatchesPattern("*.vendor.com", "API.vendor.com."))
+	assert.False(t, fqdnMatchesPattern("*.vendor.com", "vendor.com"))
+	assert.False(t, fqdnMatchesPattern("*.vendor.com", "a.b.vendor.com"))
+	assert.False(t, fqdnMatchesPattern("*.vendor.com", "apivendor.com"))
+	assert.True(t, fqdnMatchesPattern("vendor.com", "vendor.com"))
+}
+
+func TestAggregateFQDNs(t *testing.T) {
+	fqdns := []types.SpecFQDN{
+		{MatchNames: []string{"api.vendor.com", "cdn.vendor.com"}},
+		{MatchNames: []string{"vendor.com", "a.com", "b.com"}},
+		{MatchNames: []string{"x.bucket.s3.amazonaws.com", "eu.api.other.co.uk", "us.api.other.co.uk"}},
+	}
+
+	actual := aggregateFQDNs(fqdns, 2)
+	assert.Equal(t, []string{"a.com", "b.com", "vendor.com", "x.bucket.s3.amazonaws.com"}, actual.MatchNames)
+	// no pattern of a public suffix, e.g., *.com
+	assert.Equal(t, []string{"*.api.other.co.uk", "*.vendor.com"}, actual.MatchPatterns)
+
+	// below the threshold, the names are kept
+	actual = aggregateFQDNs(fqdns, 3)
+	assert.Len(t, actual.MatchPatterns, 0)
+	assert.Len(t, actual.MatchNames, 8)
+
+	// the names of an existing pattern are removed, even if the patterns are disabled
+	actual = aggregateFQDNs([]types.SpecFQDN{{MatchPatterns: []string{"*.vendor.com"}}, {MatchNames: []string{"new.vendor.com"}}}, 0)
+	assert.Equal(t, types.SpecFQDN{MatchPatterns: []string{"*.vendor.com"}}, actual)
+}
+
+func TestAggregateEgressFQDNs(t *testing.T) {
+	setFQDNPatternMinNames(t, 2)
+
+	https := []types.SpecPort{{Port: "443", Protocol: "tcp"}}
+	policy := types.KnoxNetworkPolicy{}
+	policy.Spec.Egress = []types.Egress{
+		{ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"api.vendor.com"}}}, ToPorts: https},
+		{ToEntities: []string{"kube-apiserver"}},
+		{ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"cdn.vendor.com"}}}, ToPorts: https},
+		{ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"git.vendor.com"}}}, ToPorts: []types.SpecPort{{Port: "22", Protocol: "tcp"}}},
+	}
+
+	aggregateEgressFQDNs(&policy)
+
+	assert.Len(t, policy.Spec.Egress, 3)
+	assert.Equal(t, []types.SpecFQDN{{MatchPatterns: []string{"*.vendor.com"}}}, policy.Spec.Egress[0].ToFQDNs)
+	assert.Equal(t, []types.SpecFQDN{{MatchNames: []string{"git.vendor.com"}}}, policy.Spec.Egress[2].ToFQDNs)
+
+	// a new name of the pattern does not update the policy
+	newPolicy := types.KnoxNetworkPolicy{}
+	newPolicy.Spec.Egress = []types.Egress{{ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"www.vendor.com"}}}, ToPorts: https}}
+
+	_, updated := mergeEgressPolicies(policy, []types.KnoxNetworkPolicy{newPolicy})
+	assert.False(t, updated)
+
+	// the latest policy of a name is the policy of its pattern
+	policy.Metadata = map[string]string{"namespace": "default", "type": "egress", "rule": "toFQDNs+toPorts", "status": "latest"}
+	newPolicy.Metadata = map[string]string{"namespace": "default", "type": "egress", "rule": "toFQDNs+toPorts"}
+	assert.Len(t, GetLatestFQDNPolicy([]types.KnoxNetworkPolicy{policy}, newPolicy), 1)
+}
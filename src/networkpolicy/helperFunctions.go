@@ -13,6 +13,7 @@ import (
 	"github.com/clarketm/json"
 
 	"github.com/accuknox/auto-policy-discovery/src/cluster"
+	cfg "github.com/accuknox/auto-policy-discovery/src/config"
 	"github.com/accuknox/auto-policy-discovery/src/libs"
 	"github.com/accuknox/auto-policy-discovery/src/plugin"
 	wpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/worker"
@@ -107,9 +108,47 @@ func getHaveToCheckItems(igFlows types.NetworkLogFilter) int {
 	return check
 }
 
+// httpRequestKey identifies the requests of a client to a server, the responses are reversed
+func httpRequestKey(log types.KnoxNetworkLog) string {
+	if log.IsReply {
+		return strings.Join([]string{log.DstIP, log.SrcIP, strconv.Itoa(log.SrcPort), log.HTTPMethod, log.HTTPPath}, "|")
+	}
+	return strings.Join([]string{log.SrcIP, log.DstIP, strconv.Itoa(log.DstPort), log.HTTPMethod, log.HTTPPath}, "|")
+}
+
+// removeHTTPErrorRequests removes a request per 4xx/5xx response of the same client, server, method and path,
+// the responses of the requests in the previous logs are ignored
+func removeHTTPErrorRequests(logs []types.KnoxNetworkLog) []types.KnoxNetworkLog {
+	failed := map[string]int{}
+	for _, log := range logs {
+		if log.L7Protocol == libs.L7ProtocolHTTP && log.IsReply && log.HTTPCode >= 400 {
+			failed[httpRequestKey(log)]++
+		}
+	}
+	if len(failed) == 0 {
+		return logs
+	}
+
+	res := []types.KnoxNetworkLog{}
+	for _, log := range logs {
+		if log.L7Protocol == libs.L7ProtocolHTTP && !log.IsReply {
+			if key := httpRequestKey(log); failed[key] > 0 {
+				failed[key]--
+				continue
+			}
+		}
+		res = append(res, log)
+	}
+	return res
+}
+
 func FilterNetworkLogsByConfig(logs []types.KnoxNetworkLog, pods []types.Pod) []types.KnoxNetworkLog {
 	filteredLogs := []types.KnoxNetworkLog{}
 
+	if cfg.GetCfgHTTPSkipErrors() {
+		logs = removeHTTPErrorRequests(logs)
+	}
+
 	for _, log := range logs {
 		filtered := false
 
@@ -454,11 +493,8 @@ func getMergedSortedLabels(namespace, podName string, pods []types.Pod) string {
 	for _, pod := range pods {
 		// find the src pod
 		if namespace == pod.Namespace && podName == pod.PodName {
-			// remove common name identities
-			labels := []string{}
-
-			/* TODO: do we need to skip the hash labels? */
-			labels = append(labels, pod.Labels...)
+			// skip the hash labels, and the labels out of the label policy
+			labels := filterSelectorLabels(pod.Labels)
 
 			// sorting labels alphabetically
 			sort.Slice(labels, func(i, j int) bool {
@@ -643,6 +679,67 @@ func removeDstFromMergedDstSlice(dsts []MergedPortDst, remove MergedPortDst) []M
 	return cp
 }
 
+// =========================== //
+// == CIDR of the Addresses == //
+// =========================== //
+
+// getCIDR returns the network of the ip address, with the prefix length of its family
+func getCIDR(ip string, cidrBits, cidrv6Bits int) (string, bool) {
+	bits := cidrBits
+	if libs.GetEtherType(ip) == libs.EtherTypeIPv6 {
+		bits = cidrv6Bits
+	} else if libs.GetEtherType(ip) != libs.EtherTypeIPv4 {
+		return "", false
+	}
+
+	_, network, err := net.ParseCIDR(ip + "/" + strconv.Itoa(bits))
+	if err != nil {
+		return "", false
+	}
+	return network.String(), true
+}
+
+// ============================= //
+// == Egress Rule Aggregation == //
+// ============================= //
+
+// egressL4Key identifies the ports and icmp rules of an egress rule
+func egressL4Key(egress types.Egress) (string, bool) {
+	key, err := json.Marshal(struct {
+		ToPorts []types.SpecPort
+		ICMPs   []types.SpecICMP
+	}{egress.ToPorts, egress.ICMPs})
+	return string(key), err == nil
+}
+
+// aggregateEgressPeers merges the egress rules the filter selects into a rule per l4 rules,
+// aggregate sets the peers of the merged rule from the rules, the other egress rules are kept as they are
+func aggregateEgressPeers(policy *types.KnoxNetworkPolicy, filter func(types.Egress) bool, aggregate func(*types.Egress, []types.Egress)) {
+	egresses := []types.Egress{}
+	rulesPerKey := map[string][]types.Egress{}
+	rulePosition := map[string]int{}
+
+	for _, egress := range policy.Spec.Egress {
+		key, ok := egressL4Key(egress)
+		if !ok || !filter(egress) {
+			egresses = append(egresses, egress)
+			continue
+		}
+
+		if _, ok := rulePosition[key]; !ok {
+			rulePosition[key] = len(egresses)
+			egresses = append(egresses, egress)
+		}
+		rulesPerKey[key] = append(rulesPerKey[key], egress)
+	}
+
+	for key, rules := range rulesPerKey {
+		aggregate(&egresses[rulePosition[key]], rules)
+	}
+
+	policy.Spec.Egress = egresses
+}
+
 // =================================== //
 // == Kubernetes Services/Endpoints == //
 // =================================== //
@@ -774,6 +871,20 @@ func WriteNetworkPoliciesToFile(cluster, namespace string) {
 	libs.WriteCiliumPolicyToYamlFile(namespace, ciliumPolicies)
 }
 
+func toUnsupportedRules(unsupported []types.UnsupportedPolicyRule) []*wpb.UnsupportedRule {
+	rules := []*wpb.UnsupportedRule{}
+	for _, rule := range unsupported {
+		rules = append(rules, &wpb.UnsupportedRule{
+			PolicyName: rule.PolicyName,
+			Namespace:  rule.Namespace,
+			Direction:  rule.Direction,
+			Rule:       rule.Rule,
+			Reason:     rule.Reason,
+		})
+	}
+	return rules
+}
+
 func GetNetPolicy(cluster, namespace, policyType string) *wpb.WorkerResponse {
 
 	var response wpb.WorkerResponse
@@ -797,7 +908,7 @@ func GetNetPolicy(cluster, namespace, policyType string) *wpb.WorkerResponse {
 		}
 		response.K8SNetworkpolicy = nil
 	} else if strings.Contains(policyType, "generic") {
-		policies := plugin.ConvertKnoxNetPolicyToK8sNetworkPolicy(cluster, namespace)
+		policies, unsupported := plugin.ConvertKnoxNetPolicyToK8sNetworkPolicy(cluster, namespace)
 
 		for i := range policies {
 			genericNetPol := wpb.Policy{}
@@ -811,7 +922,69 @@ func GetNetPolicy(cluster, namespace, policyType string) *wpb.WorkerResponse {
 
 			response.K8SNetworkpolicy = append(response.K8SNetworkpolicy, &genericNetPol)
 		}
+
+		response.UnsupportedRules = toUnsupportedRules(unsupported)
+		response.Ciliumpolicy = nil
+	} else if strings.Contains(policyType, "calico") {
+		latestPolicies := libs.GetNetworkPolicies(CfgDB, cluster, namespace, "latest", "", "")
+		calicoPolicies, unsupported := plugin.ConvertKnoxPoliciesToCalicoPolicies(latestPolicies)
+
+		for i := range calicoPolicies {
+			calicoPolicy := wpb.Policy{}
+
+			val, err := json.Marshal(&calicoPolicies[i])
+			if err != nil {
+				log.Error().Msg(err.Error())
+				continue
+			}
+			calicoPolicy.Data = val
+
+			response.Calicopolicy = append(response.Calicopolicy, &calicoPolicy)
+		}
+
+		response.UnsupportedRules = toUnsupportedRules(unsupported)
+		response.Ciliumpolicy = nil
+		response.K8SNetworkpolicy = nil
+	} else if strings.Contains(policyType, "istio") {
+		latestPolicies := libs.GetNetworkPolicies(CfgDB, cluster, namespace, "latest", "", "")
+		istioPolicies, unsupported := plugin.ConvertKnoxPoliciesToIstioPolicies(latestPolicies)
+
+		for i := range istioPolicies {
+			istioPolicy := wpb.Policy{}
+
+			val, err := json.Marshal(&istioPolicies[i])
+			if err != nil {
+				log.Error().Msg(err.Error())
+				continue
+			}
+			istioPolicy.Data = val
+
+			response.Istiopolicy = append(response.Istiopolicy, &istioPolicy)
+		}
+
+		response.UnsupportedRules = toUnsupportedRules(unsupported)
+		response.Ciliumpolicy = nil
+		response.K8SNetworkpolicy = nil
+	} else if strings.Contains(policyType, "admin") {
+		// the admin policies are cluster wide, they aggregate the policies of all the namespaces
+		latestPolicies := libs.GetNetworkPolicies(CfgDB, cluster, "", "latest", "", "")
+		adminPolicies := plugin.ProposeAdminNetworkPolicies(latestPolicies, cfg.GetCfgAdminPolicyMinNamespaces())
+
+		for i := range adminPolicies {
+			adminPolicy := wpb.Policy{}
+
+			val, err := json.Marshal(&adminPolicies[i])
+			if err != nil {
+				log.Error().Msg(err.Error())
+				continue
+			}
+			adminPolicy.Data = val
+
+			response.Adminnetworkpolicy = append(response.Adminnetworkpolicy, &adminPolicy)
+		}
+
 		response.Ciliumpolicy = nil
+		response.K8SNetworkpolicy = nil
 	}
 	response.Res = "OK"
 	response.Kubearmorpolicy = nil
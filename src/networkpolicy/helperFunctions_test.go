@@ -3,6 +3,7 @@ package networkpolicy
 import (
 	"testing"
 
+	"github.com/accuknox/auto-policy-discovery/src/libs"
 	"github.com/accuknox/auto-policy-discovery/src/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -133,3 +134,40 @@ func TestRemoveDstFromMergedDstSlice(t *testing.T) {
 
 	assert.Equal(t, expected, results, ShouldBeEqual)
 }
+
+// =========================== //
+// == CIDR of the Addresses == //
+// =========================== //
+
+func TestGetCIDR(t *testing.T) {
+	cidr, ok := getCIDR("8.8.8.8", 32, 128)
+	assert.True(t, ok)
+	assert.Equal(t, "8.8.8.8/32", cidr, ShouldBeEqual)
+
+	cidr, ok = getCIDR("2001:4860:4860::8888", 32, 128)
+	assert.True(t, ok)
+	assert.Equal(t, "2001:4860:4860::8888/128", cidr, ShouldBeEqual)
+
+	cidr, ok = getCIDR("2001:4860:4860::8888", 24, 64)
+	assert.True(t, ok)
+	assert.Equal(t, "2001:4860:4860::/64", cidr, ShouldBeEqual)
+
+	_, ok = getCIDR("invalid", 32, 128)
+	assert.False(t, ok)
+}
+
+// ========== //
+// == HTTP == //
+// ========== //
+
+func TestRemoveHTTPErrorRequests(t *testing.T) {
+	request := types.KnoxNetworkLog{L7Protocol: libs.L7ProtocolHTTP, SrcIP: "10.0.0.1", DstIP: "10.0.0.2", DstPort: 8080, HTTPMethod: "GET", HTTPPath: "/users"}
+	failed := types.KnoxNetworkLog{L7Protocol: libs.L7ProtocolHTTP, SrcIP: "10.0.0.2", SrcPort: 8080, DstIP: "10.0.0.1",
+		HTTPMethod: "GET", HTTPPath: "/users", HTTPCode: 404, IsReply: true}
+	other := request
+	other.HTTPPath = "/orders"
+
+	// a request per error response is removed
+	actual := removeHTTPErrorRequests([]types.KnoxNetworkLog{request, other, failed, request})
+	assert.Equal(t, []types.KnoxNetworkLog{other, failed, request}, actual)
+}
@@ -2,6 +2,7 @@ package networkpolicy
 
 import (
 	"net/http"
+	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
@@ -456,13 +457,27 @@ func AggregateHTTPRule(aggregatedSrcPerAggregatedDst map[string][]MergedPortDst)
 				}
 			}
 
+			dstLabels := getLabelMapFromArray(strings.Split(dst.MatchLabels, ","))
+
 			for method, paths := range methodToPaths {
 				httpPathTree := map[string]*Node{}
 				if existed, ok := httpTree[method]; ok {
 					httpPathTree = existed
 				}
 
-				aggregatedPaths := AggregatePaths(httpPathTree, paths)
+				// the paths of the openapi templates are exact, the tree only aggregates the others
+				unmatchedPaths := []string{}
+				for _, path := range paths {
+					if regex, ok := matchOpenAPIPath(dst.Namespace, dstLabels, "", method, path); ok {
+						if !libs.ContainsElement(updatedAdditionals, method+"|"+regex) {
+							updatedAdditionals = append(updatedAdditionals, method+"|"+regex)
+						}
+					} else {
+						unmatchedPaths = append(unmatchedPaths, path)
+					}
+				}
+
+				aggregatedPaths := AggregatePaths(httpPathTree, unmatchedPaths)
 				for _, aggPath := range aggregatedPaths {
 					updatedAdditionals = append(updatedAdditionals, method+"|"+aggPath)
 				}
@@ -478,3 +493,91 @@ func AggregateHTTPRule(aggregatedSrcPerAggregatedDst map[string][]MergedPortDst)
 		aggregatedSrcPerAggregatedDst[aggregatedSrc] = dsts
 	}
 }
+
+// ============================= //
+// == HTTP Path Tree Fallback == //
+// ============================= //
+
+// getHTTPRule returns the http rule of the network log, the path is the openapi template of the destination if any
+func getHTTPRule(log *types.KnoxNetworkLog, namespace string, podLabels map[string]string) types.SpecHTTP {
+	path := getOpenAPIPath(namespace, podLabels, log.HTTPHost, log.HTTPMethod, log.HTTPPath)
+	return types.SpecHTTP{Method: log.HTTPMethod, Path: path, Host: log.HTTPHost, Headers: log.HTTPHeaders}
+}
+
+// httpRuleContained checks if the path of the rule matches the path regex of an existing rule of the same method, host and headers
+func httpRuleContained(existRules []types.SpecHTTP, rule types.SpecHTTP) bool {
+	for _, exist := range existRules {
+		if exist.Method != rule.Method || exist.Host != rule.Host || !reflect.DeepEqual(exist.Headers, rule.Headers) {
+			continue
+		}
+		if exist.Path == rule.Path {
+			return true
+		}
+		if r, err := regexp.Compile("^(?:" + exist.Path + ")$"); err == nil && r.MatchString(rule.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// aggregateHTTPRules aggregates the observed paths of the rules of the same method, host and headers by the path tree,
+// the openapi template paths are exact and kept as they are
+func aggregateHTTPRules(https []types.SpecHTTP) []types.SpecHTTP {
+	type httpKey struct {
+		method, host, headers string
+	}
+
+	res := []types.SpecHTTP{}
+	keys := []httpKey{}
+	rules := map[httpKey]types.SpecHTTP{}
+	paths := map[httpKey][]string{}
+
+	for _, http := range https {
+		if !strings.HasPrefix(http.Path, "/") || isOpenAPIPath(http.Path) {
+			res = append(res, http)
+			continue
+		}
+
+		key := httpKey{http.Method, http.Host, strings.Join(http.Headers, ",")}
+		if _, ok := rules[key]; !ok {
+			keys = append(keys, key)
+			rules[key] = http
+		}
+		if !libs.ContainsElement(paths[key], http.Path) {
+			paths[key] = append(paths[key], http.Path)
+		}
+	}
+
+	for _, key := range keys {
+		aggregatedPaths := AggregatePaths(map[string]*Node{}, paths[key])
+		sort.Strings(aggregatedPaths)
+
+		for _, path := range aggregatedPaths {
+			rule := rules[key]
+			rule.Path = path
+			if !libs.ContainsElement(res, rule) {
+				res = append(res, rule)
+			}
+		}
+	}
+
+	return res
+}
+
+// aggregateHTTPPaths aggregates the http paths of the rules of the policy, unless the l7 discovery level is 1
+func aggregateHTTPPaths(policy *types.KnoxNetworkPolicy) {
+	if L7DiscoveryLevel == 1 {
+		return
+	}
+
+	for i := range policy.Spec.Ingress {
+		if len(policy.Spec.Ingress[i].ToHTTPs) > 0 {
+			policy.Spec.Ingress[i].ToHTTPs = aggregateHTTPRules(policy.Spec.Ingress[i].ToHTTPs)
+		}
+	}
+	for i := range policy.Spec.Egress {
+		if len(policy.Spec.Egress[i].ToHTTPs) > 0 {
+			policy.Spec.Egress[i].ToHTTPs = aggregateHTTPRules(policy.Spec.Egress[i].ToHTTPs)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package networkpolicy
+
+import (
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"sigs.k8s.io/yaml"
+)
+
+// OpenAPIPathParam is the path regex of a path template parameter, e.g., {id} in /users/{id}
+var OpenAPIPathParam string = "[^/]+"
+
+var openAPIParamPattern = regexp.MustCompile(`\{[^/{}]+\}`)
+
+// OpenAPIServices are the services of the configured openapi documents
+var OpenAPIServices []*openAPIService
+
+// ======================= //
+// == OpenAPI Documents == //
+// ======================= //
+
+// openAPIDocument has the fields of the openapi 3 and swagger 2 documents to match the paths
+type openAPIDocument struct {
+	Host     string                            `json:"host"`
+	BasePath string                            `json:"basePath"`
+	Servers  []struct{ URL string }            `json:"servers"`
+	Paths    map[string]map[string]interface{} `json:"paths"`
+}
+
+// openAPITemplate is a path template of an openapi document
+type openAPITemplate struct {
+	template string
+	methods  []string // no method matches all the methods
+	params   int
+
+	matcher *regexp.Regexp
+	regex   string
+}
+
+type openAPIService struct {
+	spec      types.HTTPOpenAPISpec
+	selector  map[string]string
+	hosts     []string // the hosts of the document servers, the requests to the external apis
+	templates []openAPITemplate
+}
+
+// newOpenAPITemplate builds the regex matching the observed paths and the path regex of the policies
+func newOpenAPITemplate(template string) openAPITemplate {
+	literals := openAPIParamPattern.Split(template, -1)
+
+	quoted := []string{}
+	for _, literal := range literals {
+		quoted = append(quoted, regexp.QuoteMeta(literal))
+	}
+
+	return openAPITemplate{
+		template: template,
+		params:   len(literals) - 1,
+		matcher:  regexp.MustCompile("^" + strings.Join(quoted, OpenAPIPathParam) + "$"),
+		regex:    strings.Join(quoted, OpenAPIPathParam),
+	}
+}
+
+// loadOpenAPITemplates reads the path templates and the server hosts of an openapi 3 or swagger 2 document, in yaml or json
+func loadOpenAPITemplates(path string) ([]openAPITemplate, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc := openAPIDocument{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	// the observed paths include the base path of the api
+	basePath := doc.BasePath
+	hosts := []string{}
+	if doc.Host != "" {
+		hosts = append(hosts, strings.ToLower(doc.Host))
+	}
+	for i, server := range doc.Servers {
+		serverURL, err := url.Parse(server.URL)
+		if err != nil {
+			continue
+		}
+		if i == 0 {
+			basePath = serverURL.Path
+		}
+		if host := strings.ToLower(serverURL.Host); host != "" && !libs.ContainsElement(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	basePath = strings.TrimSuffix(basePath, "/")
+
+	templates := []openAPITemplate{}
+	for templatePath, item := range doc.Paths {
+		template := newOpenAPITemplate(basePath + templatePath)
+		for key := range item {
+			// the other keys are the parameters, summary, etc.
+			if libs.ContainsElement(httpMethods, strings.ToUpper(key)) {
+				template.methods = append(template.methods, strings.ToUpper(key))
+			}
+		}
+		sort.Strings(template.methods)
+		templates = append(templates, template)
+	}
+
+	// the concrete paths precede the templates, e.g., /users/me precedes /users/{id}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].params != templates[j].params {
+			return templates[i].params < templates[j].params
+		}
+		if len(templates[i].template) != len(templates[j].template) {
+			return len(templates[i].template) > len(templates[j].template)
+		}
+		return templates[i].template < templates[j].template
+	})
+
+	return templates, hosts, nil
+}
+
+// InitOpenAPIServices loads the openapi documents of the services
+func InitOpenAPIServices(specs []types.HTTPOpenAPISpec) {
+	OpenAPIServices = []*openAPIService{}
+
+	for _, spec := range specs {
+		templates, hosts, err := loadOpenAPITemplates(spec.File)
+		if err != nil {
+			log.Error().Msgf("failed to load the openapi document [%s]: %v", spec.File, err)
+			continue
+		}
+
+		OpenAPIServices = append(OpenAPIServices, &openAPIService{
+			spec:      spec,
+			selector:  getLabelMapFromArray(spec.Labels),
+			hosts:     hosts,
+			templates: templates,
+		})
+	}
+}
+
+// resolveOpenAPIServices selects the pods of the services by the selectors of the cluster services
+func resolveOpenAPIServices(services []types.Service) {
+	for _, openAPISvc := range OpenAPIServices {
+		if openAPISvc.spec.Service == "" {
+			continue
+		}
+
+		openAPISvc.selector = nil
+		for _, svc := range services {
+			if svc.ServiceName == openAPISvc.spec.Service && svc.Namespace == openAPISvc.spec.Namespace && len(svc.Selector) > 0 {
+				openAPISvc.selector = svc.Selector
+				break
+			}
+		}
+	}
+}
+
+// =========================== //
+// == OpenAPI Path Matching == //
+// =========================== //
+
+// selects checks if the requests to the pod, or to the host out of the cluster, are the requests of the service
+func (s *openAPIService) selects(namespace string, podLabels map[string]string, host string) bool {
+	if len(podLabels) == 0 {
+		return host != "" && libs.ContainsElement(s.hosts, strings.ToLower(host))
+	}
+
+	if len(s.selector) == 0 || (s.spec.Namespace != "" && s.spec.Namespace != namespace) {
+		return false
+	}
+
+	for k, v := range s.selector {
+		if podLabels[k] != v && podLabels["k8s:"+k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// matchOpenAPIPath returns the path regex of the template the path of the pod, or of the external host, matches
+func matchOpenAPIPath(namespace string, podLabels map[string]string, host, method, path string) (string, bool) {
+	// the query string is not a part of the template
+	path = strings.SplitN(path, "?", 2)[0]
+
+	for _, openAPISvc := range OpenAPIServices {
+		if !openAPISvc.selects(namespace, podLabels, host) {
+			continue
+		}
+
+		for _, template := range openAPISvc.templates {
+			if len(template.methods) > 0 && !libs.ContainsElement(template.methods, strings.ToUpper(method)) {
+				continue
+			}
+			if template.matcher.MatchString(path) {
+				return template.regex, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// getOpenAPIPath returns the path regex of the matched template, or the observed path
+func getOpenAPIPath(namespace string, podLabels map[string]string, host, method, path string) string {
+	if regex, ok := matchOpenAPIPath(namespace, podLabels, host, method, path); ok {
+		return regex
+	}
+	return path
+}
+
+// isOpenAPIPath checks if the path is the path regex of an openapi template
+func isOpenAPIPath(path string) bool {
+	for _, openAPISvc := range OpenAPIServices {
+		for _, template := range openAPISvc.templates {
+			if template.regex == path {
+				return true
+			}
+		}
+	}
+	return false
+}
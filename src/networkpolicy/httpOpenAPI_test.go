@@ -0,0 +1,149 @@
+package networkpolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const petstoreDocument = `
+openapi: 3.0.0
+servers:
+  - url: https://petstore.example.com/v1
+paths:
+  /pets:
+    get: {}
+    post: {}
+  /pets/{petId}:
+    parameters: []
+    get: {}
+  /pets/mine:
+    get: {}
+  /pets/{petId}/photos/{photoId}.jpg:
+    get: {}
+`
+
+func initPetstore(t *testing.T, spec types.HTTPOpenAPISpec) {
+	spec.File = filepath.Join(t.TempDir(), "petstore.yaml")
+	assert.NoError(t, os.WriteFile(spec.File, []byte(petstoreDocument), 0600))
+
+	InitOpenAPIServices([]types.HTTPOpenAPISpec{spec})
+	t.Cleanup(func() {
+		OpenAPIServices = nil
+	})
+}
+
+// ============================ //
+// == OpenAPI Path Templates == //
+// ============================ //
+
+func TestMatchOpenAPIPath(t *testing.T) {
+	initPetstore(t, types.HTTPOpenAPISpec{Namespace: "default", Labels: []string{"app=petstore"}})
+	assert.Len(t, OpenAPIServices[0].templates, 4)
+
+	podLabels := map[string]string{"app": "petstore", "version": "v2"}
+
+	for path, expected := range map[string]string{
+		"/v1/pets":                 "/v1/pets",
+		"/v1/pets/42?verbose=true": "/v1/pets/[^/]+",
+		"/v1/pets/mine":            "/v1/pets/mine",
+		"/v1/pets/42/photos/1.jpg": `/v1/pets/[^/]+/photos/[^/]+\.jpg`,
+	} {
+		actual, ok := matchOpenAPIPath("default", podLabels, "", "GET", path)
+		assert.True(t, ok, path)
+		assert.Equal(t, expected, actual)
+	}
+
+	// the method, the namespace and the labels are matched too
+	_, ok := matchOpenAPIPath("default", podLabels, "", "DELETE", "/v1/pets/42")
+	assert.False(t, ok)
+	_, ok = matchOpenAPIPath("other", podLabels, "", "GET", "/v1/pets/42")
+	assert.False(t, ok)
+
+	// the unmatched paths are kept
+	assert.Equal(t, "/healthz", getOpenAPIPath("default", podLabels, "", "GET", "/healthz"))
+}
+
+func TestResolveOpenAPIServices(t *testing.T) {
+	initPetstore(t, types.HTTPOpenAPISpec{Namespace: "default", Service: "petstore"})
+
+	podLabels := map[string]string{"k8s:app": "petstore"}
+	_, ok := matchOpenAPIPath("default", podLabels, "", "GET", "/v1/pets/42")
+	assert.False(t, ok)
+
+	resolveOpenAPIServices([]types.Service{
+		{Namespace: "other", ServiceName: "petstore", Selector: map[string]string{"app": "other"}},
+		{Namespace: "default", ServiceName: "petstore", Selector: map[string]string{"app": "petstore"}},
+	})
+
+	actual, ok := matchOpenAPIPath("default", podLabels, "", "GET", "/v1/pets/42")
+	assert.True(t, ok)
+	assert.Equal(t, "/v1/pets/[^/]+", actual)
+}
+
+func TestMatchOpenAPIPathExternalHost(t *testing.T) {
+	initPetstore(t, types.HTTPOpenAPISpec{Namespace: "default", Labels: []string{"app=petstore"}})
+
+	// the requests to the world are matched by the hosts of the document servers
+	actual, ok := matchOpenAPIPath("", nil, "PetStore.example.com", "GET", "/v1/pets/42")
+	assert.True(t, ok)
+	assert.Equal(t, "/v1/pets/[^/]+", actual)
+
+	_, ok = matchOpenAPIPath("", nil, "other.example.com", "GET", "/v1/pets/42")
+	assert.False(t, ok)
+}
+
+func TestConvertKnoxNetworkLogOpenAPIEgress(t *testing.T) {
+	initPetstore(t, types.HTTPOpenAPISpec{Namespace: "default", Labels: []string{"app=petstore"}})
+
+	pods := []types.Pod{{Namespace: "default", PodName: "client", Labels: []string{"app=client"}}}
+	log := types.KnoxNetworkLog{
+		SrcNamespace: "default", SrcPodName: "client", DstReservedLabels: []string{"reserved:world"},
+		Protocol: libs.IPProtocolTCP, DstPort: 443, L7Protocol: libs.L7ProtocolHTTP,
+		HTTPMethod: "GET", HTTPPath: "/v1/pets/42", HTTPHost: "petstore.example.com",
+	}
+
+	_, egress := convertKnoxNetworkLogToKnoxNetworkPolicy(&log, pods)
+	assert.NotNil(t, egress)
+	assert.Equal(t, "/v1/pets/[^/]+", egress.Spec.Egress[0].ToHTTPs[0].Path)
+}
+
+// ============================= //
+// == HTTP Path Tree Fallback == //
+// ============================= //
+
+func TestAggregateHTTPPaths(t *testing.T) {
+	initPetstore(t, types.HTTPOpenAPISpec{Namespace: "default", Labels: []string{"app=petstore"}})
+
+	L7DiscoveryLevel = 2
+	t.Cleanup(func() {
+		L7DiscoveryLevel = 0
+	})
+
+	policy := types.KnoxNetworkPolicy{}
+	policy.Spec.Ingress = []types.Ingress{{
+		ToHTTPs: []types.SpecHTTP{
+			{Method: "GET", Path: "/v1/pets/[^/]+"},
+			{Method: "GET", Path: "/orders/1"},
+			{Method: "GET", Path: "/orders/2"},
+			{Method: "GET", Path: "/orders/3"},
+			{Method: "GET", Path: "/orders/4"},
+		},
+	}}
+
+	aggregateHTTPPaths(&policy)
+
+	// the openapi template is kept, the other paths fall back to the path tree
+	assert.Equal(t, []types.SpecHTTP{
+		{Method: "GET", Path: "/v1/pets/[^/]+"},
+		{Method: "GET", Path: "/orders/[0-9^/]+"},
+	}, policy.Spec.Ingress[0].ToHTTPs)
+
+	// a new path of an aggregated path does not update the rule
+	assert.True(t, httpRuleContained(policy.Spec.Ingress[0].ToHTTPs, types.SpecHTTP{Method: "GET", Path: "/orders/5"}))
+	assert.False(t, httpRuleContained(policy.Spec.Ingress[0].ToHTTPs, types.SpecHTTP{Method: "POST", Path: "/orders/5"}))
+}
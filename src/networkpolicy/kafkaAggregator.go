@@ -0,0 +1,112 @@
+package networkpolicy
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+const (
+	KafkaRoleProduce = "produce"
+	KafkaRoleConsume = "consume"
+)
+
+// kafkaRoleAPIKeys are the api keys cilium allows for a role, the role is discovered from its first key
+var kafkaRoleAPIKeys = map[string][]string{
+	KafkaRoleProduce: {"produce", "metadata", "apiversions"},
+	KafkaRoleConsume: {"fetch", "offsets", "metadata", "offsetcommit", "offsetfetch", "findcoordinator",
+		"joingroup", "heartbeat", "leavegroup", "syncgroup", "apiversions"},
+}
+
+// ======================= //
+// == Kafka aggregation == //
+// ======================= //
+
+// aggregateKafkaRules replaces the api keys of a topic by the produce or consume role,
+// if the topic is produced or fetched, the rules without topic are kept as they are
+func aggregateKafkaRules(rules []types.SpecKafka) []types.SpecKafka {
+	topics := []string{}
+	apiKeysPerTopic := map[string][]string{}
+	rolesPerTopic := map[string][]string{}
+
+	res := []types.SpecKafka{}
+
+	for _, rule := range rules {
+		if rule.Topic == "" || rule.ClientID != "" {
+			if !libs.ContainsElement(res, rule) {
+				res = append(res, rule)
+			}
+			continue
+		}
+
+		if !libs.ContainsElement(topics, rule.Topic) {
+			topics = append(topics, rule.Topic)
+		}
+		if rule.Role != "" && !libs.ContainsElement(rolesPerTopic[rule.Topic], rule.Role) {
+			rolesPerTopic[rule.Topic] = append(rolesPerTopic[rule.Topic], rule.Role)
+		}
+		if rule.APIKey != "" && !libs.ContainsElement(apiKeysPerTopic[rule.Topic], rule.APIKey) {
+			apiKeysPerTopic[rule.Topic] = append(apiKeysPerTopic[rule.Topic], rule.APIKey)
+		}
+	}
+
+	for _, topic := range topics {
+		roles := rolesPerTopic[topic]
+		for _, role := range []string{KafkaRoleProduce, KafkaRoleConsume} {
+			if libs.ContainsElement(apiKeysPerTopic[topic], kafkaRoleAPIKeys[role][0]) && !libs.ContainsElement(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+
+		for _, role := range roles {
+			res = append(res, types.SpecKafka{Role: role, Topic: topic})
+		}
+
+		for _, apiKey := range apiKeysPerTopic[topic] {
+			covered := false
+			for _, role := range roles {
+				if libs.ContainsElement(kafkaRoleAPIKeys[role], apiKey) {
+					covered = true
+				}
+			}
+			if !covered {
+				res = append(res, types.SpecKafka{APIKey: apiKey, Topic: topic})
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Topic != res[j].Topic {
+			return res[i].Topic < res[j].Topic
+		}
+		if res[i].Role != res[j].Role {
+			return res[i].Role < res[j].Role
+		}
+		if res[i].APIKey != res[j].APIKey {
+			return res[i].APIKey < res[j].APIKey
+		}
+		return res[i].ClientID < res[j].ClientID
+	})
+
+	return res
+}
+
+// mergeKafkaRules aggregates the new kafka rules into the existing ones, updated is false if they are all covered
+func mergeKafkaRules(existRules, newRules []types.SpecKafka) ([]types.SpecKafka, bool) {
+	if len(newRules) == 0 {
+		return existRules, false
+	}
+
+	merged := aggregateKafkaRules(append(append([]types.SpecKafka{}, existRules...), newRules...))
+	return merged, !reflect.DeepEqual(merged, existRules)
+}
+
+// getKafkaRules returns the kafka rule of the network log, if the kafka rules are discovered
+func getKafkaRules(log *types.KnoxNetworkLog, ruleTypes int) []types.SpecKafka {
+	if log.L7Protocol != libs.L7ProtocolKafka || ruleTypes&TO_KAFKAS == 0 {
+		return nil
+	}
+	return aggregateKafkaRules([]types.SpecKafka{{APIKey: log.KafkaAPIKey, Topic: log.KafkaTopic}})
+}
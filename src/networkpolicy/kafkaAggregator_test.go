@@ -0,0 +1,64 @@
+package networkpolicy
+
+import (
+	"testing"
+
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// ======================= //
+// == Kafka aggregation == //
+// ======================= //
+
+func TestAggregateKafkaRules(t *testing.T) {
+	actual := aggregateKafkaRules([]types.SpecKafka{
+		{APIKey: "produce", Topic: "orders"},
+		{APIKey: "metadata", Topic: "orders"},
+		{APIKey: "fetch", Topic: "payments"},
+		{APIKey: "createtopics", Topic: "payments"},
+		{APIKey: "metadata", Topic: "audit"},
+		{APIKey: "apiversions"},
+		{APIKey: "apiversions"},
+	})
+
+	assert.Equal(t, []types.SpecKafka{
+		{APIKey: "apiversions"},
+		{APIKey: "metadata", Topic: "audit"},
+		{Role: KafkaRoleProduce, Topic: "orders"},
+		{APIKey: "createtopics", Topic: "payments"},
+		{Role: KafkaRoleConsume, Topic: "payments"},
+	}, actual)
+}
+
+func TestMergeKafkaRules(t *testing.T) {
+	exist := []types.SpecKafka{{Role: KafkaRoleProduce, Topic: "orders"}}
+
+	// the api keys of the role are covered
+	merged, updated := mergeKafkaRules(exist, []types.SpecKafka{{APIKey: "metadata", Topic: "orders"}})
+	assert.False(t, updated)
+	assert.Equal(t, exist, merged)
+
+	merged, updated = mergeKafkaRules(exist, []types.SpecKafka{{APIKey: "fetch", Topic: "orders"}})
+	assert.True(t, updated)
+	assert.Equal(t, []types.SpecKafka{{Role: KafkaRoleConsume, Topic: "orders"}, {Role: KafkaRoleProduce, Topic: "orders"}}, merged)
+}
+
+func TestMergeEgressPoliciesKafka(t *testing.T) {
+	ports := []types.SpecPort{{Port: "9092", Protocol: "tcp"}}
+	kafkaLabels := map[string]string{"app": "kafka"}
+
+	existPolicy := types.KnoxNetworkPolicy{}
+	existPolicy.Spec.Egress = []types.Egress{
+		{MatchLabels: kafkaLabels, ToPorts: ports, ToKafkas: []types.SpecKafka{{APIKey: "apiversions"}}},
+	}
+	newPolicy := types.KnoxNetworkPolicy{}
+	newPolicy.Spec.Egress = []types.Egress{
+		{MatchLabels: kafkaLabels, ToPorts: ports, ToKafkas: []types.SpecKafka{{Role: KafkaRoleProduce, Topic: "orders"}}},
+	}
+
+	merged, updated := mergeEgressPolicies(existPolicy, []types.KnoxNetworkPolicy{newPolicy})
+	assert.True(t, updated)
+	assert.Len(t, merged.Spec.Egress, 1)
+	assert.Equal(t, []types.SpecKafka{{APIKey: "apiversions"}, {Role: KafkaRoleProduce, Topic: "orders"}}, merged.Spec.Egress[0].ToKafkas)
+}
@@ -0,0 +1,101 @@
+package networkpolicy
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+)
+
+// VolatileLabelKeys are the label keys kubernetes controllers set per revision, job or pod,
+// a selector of them breaks at the next rollout
+var VolatileLabelKeys = []string{
+	"pod-template-hash",
+	"controller-revision-hash",
+	"pod-template-generation",
+	"statefulset.kubernetes.io/pod-name",
+	"apps.kubernetes.io/pod-index",
+	"controller-uid",
+	"job-name",
+	"batch.kubernetes.io/controller-uid",
+	"batch.kubernetes.io/job-name",
+	"batch.kubernetes.io/job-completion-index",
+}
+
+// LabelIncludeKeys are the regexes of the label keys the selectors are built from, empty for all the keys
+var LabelIncludeKeys []*regexp.Regexp
+
+// LabelExcludeKeys are the regexes of the label keys the selectors are not built from
+var LabelExcludeKeys []*regexp.Regexp
+
+// LabelPreferredKeys are the label keys a selector is built from only, if the pod has any of them
+var LabelPreferredKeys []string
+
+// ================== //
+// == Label Policy == //
+// ================== //
+
+// InitLabelPolicy compiles the regexes of the included and excluded label keys
+func InitLabelPolicy(include, exclude, preferred []string) {
+	compile := func(exprs []string) []*regexp.Regexp {
+		res := []*regexp.Regexp{}
+		for _, expr := range exprs {
+			r, err := regexp.Compile(expr)
+			if err != nil {
+				log.Error().Msgf("failed to compile the label key regex [%s]: %v", expr, err)
+				continue
+			}
+			res = append(res, r)
+		}
+		return res
+	}
+
+	LabelIncludeKeys = compile(include)
+	LabelExcludeKeys = compile(exclude)
+	LabelPreferredKeys = preferred
+}
+
+func labelKeyMatches(exprs []*regexp.Regexp, key string) bool {
+	for _, r := range exprs {
+		if r.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSelectorLabels returns the labels, key=value, a selector is built from:
+// the volatile and excluded keys are dropped, only the included keys are kept if any regex is set,
+// and only the preferred keys are kept if the pod has any of them.
+// if the include and exclude regexes drop all the labels, the labels but the volatile ones are kept,
+// so that the selector does not select the whole namespace
+func filterSelectorLabels(labels []string) []string {
+	stable, filtered, preferred := []string{}, []string{}, []string{}
+
+	for _, label := range labels {
+		key := strings.SplitN(label, "=", 2)[0]
+		if key != label && libs.ContainsElement(VolatileLabelKeys, key) {
+			continue
+		}
+		stable = append(stable, label)
+
+		if labelKeyMatches(LabelExcludeKeys, key) {
+			continue
+		}
+		if len(LabelIncludeKeys) > 0 && !labelKeyMatches(LabelIncludeKeys, key) {
+			continue
+		}
+		filtered = append(filtered, label)
+
+		if libs.ContainsElement(LabelPreferredKeys, key) {
+			preferred = append(preferred, label)
+		}
+	}
+
+	if len(preferred) > 0 {
+		return preferred
+	} else if len(filtered) > 0 {
+		return filtered
+	}
+	return stable
+}
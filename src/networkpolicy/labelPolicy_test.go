@@ -0,0 +1,54 @@
+package networkpolicy
+
+import (
+	"testing"
+
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func setLabelPolicy(t *testing.T, include, exclude, preferred []string) {
+	InitLabelPolicy(include, exclude, preferred)
+	t.Cleanup(func() {
+		InitLabelPolicy(nil, nil, nil)
+	})
+}
+
+// ================== //
+// == Label Policy == //
+// ================== //
+
+func TestFilterSelectorLabels(t *testing.T) {
+	labels := []string{"app=web", "tier=frontend", "pod-template-hash=7d9f8c6b5", "controller-revision-hash=web-5c8d", "reserved:host"}
+
+	// the volatile labels are skipped by default
+	assert.Equal(t, []string{"app=web", "tier=frontend", "reserved:host"}, filterSelectorLabels(labels))
+
+	setLabelPolicy(t, nil, []string{"^tier$"}, nil)
+	assert.Equal(t, []string{"app=web", "reserved:host"}, filterSelectorLabels(labels))
+
+	setLabelPolicy(t, []string{"^tier$"}, nil, nil)
+	assert.Equal(t, []string{"tier=frontend"}, filterSelectorLabels(labels))
+
+	// the labels but the volatile ones are kept, if the regexes drop all of them
+	setLabelPolicy(t, []string{"^team$"}, nil, nil)
+	assert.Equal(t, []string{"app=web", "tier=frontend", "reserved:host"}, filterSelectorLabels(labels))
+
+	// the preferred keys are used only, if present
+	setLabelPolicy(t, nil, nil, []string{"app.kubernetes.io/name"})
+	assert.Equal(t, []string{"app.kubernetes.io/name=web"}, filterSelectorLabels(append(labels, "app.kubernetes.io/name=web")))
+	assert.Equal(t, []string{"app=web", "tier=frontend", "reserved:host"}, filterSelectorLabels(labels))
+}
+
+func TestGetEndpointMatchLabelsLabelPolicy(t *testing.T) {
+	setLabelPolicy(t, nil, nil, []string{"app.kubernetes.io/name"})
+
+	pods := []types.Pod{
+		{Namespace: "default", PodName: "web-7d9f8c6b5-x2x4z", Labels: []string{"app.kubernetes.io/name=web", "app.kubernetes.io/version=1.2", "pod-template-hash=7d9f8c6b5"}},
+		{Namespace: "default", PodName: "db-0", Labels: []string{"app=db", "statefulset.kubernetes.io/pod-name=db-0", "controller-revision-hash=db-6b4f"}},
+	}
+
+	assert.Equal(t, map[string]string{"app.kubernetes.io/name": "web"}, getEndpointMatchLabels("web-7d9f8c6b5-x2x4z", pods))
+	assert.Equal(t, map[string]string{"app": "db"}, getEndpointMatchLabels("db-0", pods))
+	assert.Equal(t, "app=db", getMergedSortedLabels("default", "db-0", pods))
+}
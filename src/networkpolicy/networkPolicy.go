@@ -4,6 +4,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/accuknox/auto-policy-discovery/src/cluster"
@@ -12,6 +13,7 @@ import (
 	fc "github.com/accuknox/auto-policy-discovery/src/feedconsumer"
 	"github.com/accuknox/auto-policy-discovery/src/libs"
 	logger "github.com/accuknox/auto-policy-discovery/src/logging"
+	"github.com/accuknox/auto-policy-discovery/src/metrics"
 	"github.com/accuknox/auto-policy-discovery/src/plugin"
 	"github.com/accuknox/auto-policy-discovery/src/types"
 	cu "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/utils"
@@ -53,16 +55,17 @@ const (
 	EGRESS_INGRESS = 3
 
 	// discovery rule type
-	MATCH_LABELS  = 1 << 0 // 1
-	TO_ICMPS      = 1 << 1 // 2
-	TO_PORTS      = 1 << 2 // 4
-	TO_HTTPS      = 1 << 3 // 8
-	TO_CIDRS      = 1 << 4 // 16
-	TO_ENTITIES   = 1 << 5 // 32
-	TO_SERVICES   = 1 << 6 // 64
-	TO_FQDNS      = 1 << 7 // 126
-	FROM_CIDRS    = 1 << 8 // 256
-	FROM_ENTITIES = 1 << 9 // 512
+	MATCH_LABELS  = 1 << 0  // 1
+	TO_ICMPS      = 1 << 1  // 2
+	TO_PORTS      = 1 << 2  // 4
+	TO_HTTPS      = 1 << 3  // 8
+	TO_CIDRS      = 1 << 4  // 16
+	TO_ENTITIES   = 1 << 5  // 32
+	TO_SERVICES   = 1 << 6  // 64
+	TO_FQDNS      = 1 << 7  // 126
+	FROM_CIDRS    = 1 << 8  // 256
+	FROM_ENTITIES = 1 << 9  // 512
+	TO_KAFKAS     = 1 << 10 // 1024
 )
 
 const (
@@ -90,11 +93,24 @@ var NetworkStopChan chan struct{} // for hubble
 var OperationTrigger int
 var CfgDB types.ConfigDB
 
+// currentRun is the record of the discovery run in progress
+var currentRun *types.DiscoveryRun
+
+// replacedPolicies maps the name of each policy outdated by the deduplication to the name of the policy replacing it,
+// the analyzer may run the discovery along with the worker
+var replacedPolicies = map[string]string{}
+var replacedPoliciesMutex sync.Mutex
+
 var NetworkLogFrom string
 var NetworkLogFile string
 var NetworkPolicyTo string
 
 var CIDRBits int
+var CIDRv6Bits int
+
+// WorldCIDRs is true if the addresses out of the cluster are discovered as cidrs rather than the world entity
+var WorldCIDRs bool
+
 var HTTPThreshold int
 
 var L3DiscoveryLevel int
@@ -125,7 +141,22 @@ func InitNetPolicyDiscoveryConfiguration() {
 	L7DiscoveryLevel = cfg.GetCfgNetworkL7Level()
 
 	CIDRBits = cfg.GetCfgCIDRBits()
+	CIDRv6Bits = cfg.GetCfgCIDRv6Bits()
+	WorldCIDRs = cfg.GetCfgWorldCIDRs()
+	CIDRAggregationMinIPs, CIDRAggregationMaxPrefix, CIDRAggregationMaxPrefixV6 = cfg.GetCfgCIDRAggregation()
+	CIDRProviderRanges = nil
+	if path := cfg.GetCfgCIDRProviderRangesFile(); path != "" {
+		ranges, err := LoadCIDRProviderRanges(path)
+		if err != nil {
+			log.Error().Msgf("failed to load the cidr provider ranges [%s]: %v", path, err)
+		}
+		CIDRProviderRanges = ranges
+	}
+	FQDNPatternMinNames = cfg.GetCfgFQDNPatternMinNames()
+	PortRangeMinPorts, PortRangeMaxGap = cfg.GetCfgPortRange()
+	InitLabelPolicy(cfg.GetCfgLabelPolicy())
 	HTTPThreshold = cfg.GetCfgNetworkHTTPThreshold()
+	InitOpenAPIServices(cfg.GetCfgHTTPOpenAPISpecs())
 
 	NetworkLogFilters = cfg.GetCfgNetworkLogFilters()
 	NamespaceFilters = cfg.GetCfgNetworkSkipNamespaces()
@@ -799,10 +830,7 @@ func mergeProtocolPorts(src string, dsts []Dst) []MergedPortDst {
 			l4DstExists = true
 
 			if libs.IsICMP(dst.Protocol) {
-				family := "IPv4"
-				if dst.Protocol == libs.IPProtocolICMPv6 {
-					family = "IPv6"
-				}
+				family := libs.GetICMPFamily(dst.Protocol)
 				l4MergedDst.ICMPs = []types.SpecICMP{{
 					Family: family,
 					Type:   uint8(dst.ICMPType),
@@ -1408,10 +1436,7 @@ func buildNetworkPolicy(namespace string, services []types.Service, aggregatedSr
 				if discoverPolicyTypes&EGRESS > 0 && discoverRuleTypes&TO_FQDNS > 0 {
 					egressPolicy.Metadata["rule"] = egressPolicy.Metadata["rule"] + "+toFQDNs"
 
-					sort.Strings(dst.Additionals)
-					fqdn := types.SpecFQDN{
-						MatchNames: dst.Additionals,
-					}
+					fqdn := aggregateFQDNs([]types.SpecFQDN{{MatchNames: dst.Additionals}}, FQDNPatternMinNames)
 
 					egressRule.ToFQDNs = []types.SpecFQDN{fqdn}
 					egressPolicy.Spec.Egress = append(egressPolicy.Spec.Egress, egressRule)
@@ -1529,6 +1554,8 @@ func DiscoverNetworkPolicy(namespace string,
 			mergedPolicy, _ := mergeNetworkPolicies(policies[0], policies[1:])
 			ingressPolicies[selector] = []types.KnoxNetworkPolicy{mergedPolicy}
 		}
+		aggregateHTTPPaths(&ingressPolicies[selector][0])
+		aggregatePortRanges(&ingressPolicies[selector][0])
 	}
 
 	for selector, policies := range egressPolicies {
@@ -1536,6 +1563,10 @@ func DiscoverNetworkPolicy(namespace string,
 			mergedPolicy, _ := mergeNetworkPolicies(policies[0], policies[1:])
 			egressPolicies[selector] = []types.KnoxNetworkPolicy{mergedPolicy}
 		}
+		aggregateEgressCIDRs(&egressPolicies[selector][0])
+		aggregateEgressFQDNs(&egressPolicies[selector][0])
+		aggregateHTTPPaths(&egressPolicies[selector][0])
+		aggregatePortRanges(&egressPolicies[selector][0])
 	}
 
 	for _, p := range ingressPolicies {
@@ -1578,6 +1609,10 @@ func mergeIngressPolicies(existPolicy types.KnoxNetworkPolicy, policies []types.
 					if newSelector == existSelector {
 						ingressMatched, updated, mergedPolicy.Spec.Ingress[i].ToHTTPs = mergeHttpRules(existIngress, newIngress)
 						if ingressMatched {
+							if kafkas, ok := mergeKafkaRules(existIngress.ToKafkas, newIngress.ToKafkas); ok {
+								mergedPolicy.Spec.Ingress[i].ToKafkas = kafkas
+								updated = true
+							}
 							break
 						}
 					}
@@ -1594,6 +1629,10 @@ func mergeIngressPolicies(existPolicy types.KnoxNetworkPolicy, policies []types.
 					if newEntity == existEntity {
 						ingressMatched, updated, mergedPolicy.Spec.Ingress[i].ToHTTPs = mergeHttpRules(existIngress, newIngress)
 						if ingressMatched {
+							if kafkas, ok := mergeKafkaRules(existIngress.ToKafkas, newIngress.ToKafkas); ok {
+								mergedPolicy.Spec.Ingress[i].ToKafkas = kafkas
+								updated = true
+							}
 							break
 						}
 					}
@@ -1632,6 +1671,10 @@ func mergeEgressPolicies(existPolicy types.KnoxNetworkPolicy, policies []types.K
 					if newSelector == existSelector {
 						egressMatched, updated, mergedPolicy.Spec.Egress[i].ToHTTPs = mergeHttpRules(existEgress, newEgress)
 						if egressMatched {
+							if kafkas, ok := mergeKafkaRules(existEgress.ToKafkas, newEgress.ToKafkas); ok {
+								mergedPolicy.Spec.Egress[i].ToKafkas = kafkas
+								updated = true
+							}
 							break
 						}
 					}
@@ -1648,22 +1691,46 @@ func mergeEgressPolicies(existPolicy types.KnoxNetworkPolicy, policies []types.K
 					if newEntity == existEntity {
 						egressMatched, updated, mergedPolicy.Spec.Egress[i].ToHTTPs = mergeHttpRules(existEgress, newEgress)
 						if egressMatched {
+							if kafkas, ok := mergeKafkaRules(existEgress.ToKafkas, newEgress.ToKafkas); ok {
+								mergedPolicy.Spec.Egress[i].ToKafkas = kafkas
+								updated = true
+							}
 							break
 						}
 					}
 				}
-			} else if len(newEgress.ToFQDNs) > 0 {
-				newFQDN := newEgress.ToFQDNs[0].MatchNames[0]
+			} else if len(newEgress.ToCIDRs) > 0 {
+				for i, existEgress := range mergedPolicy.Spec.Egress {
+					if len(existEgress.ToCIDRs) == 0 {
+						continue
+					}
 
+					// the cidrs may be aggregated in a supernet of the existing rule
+					if cidrsContained(newEgress.ToCIDRs[0].CIDRs, existEgress.ToCIDRs[0].CIDRs) {
+						egressMatched, updated, mergedPolicy.Spec.Egress[i].ToHTTPs = mergeHttpRules(existEgress, newEgress)
+						if egressMatched {
+							if kafkas, ok := mergeKafkaRules(existEgress.ToKafkas, newEgress.ToKafkas); ok {
+								mergedPolicy.Spec.Egress[i].ToKafkas = kafkas
+								updated = true
+							}
+							break
+						}
+					}
+				}
+			} else if len(newEgress.ToFQDNs) > 0 {
 				for i, existEgress := range mergedPolicy.Spec.Egress {
 					if len(existEgress.ToFQDNs) == 0 {
 						continue
 					}
-					existFQDN := existEgress.ToFQDNs[0].MatchNames[0]
 
-					if newFQDN == existFQDN {
+					// the names may be matched by a pattern of the existing rule
+					if fqdnsContained(newEgress.ToFQDNs, existEgress.ToFQDNs) {
 						egressMatched, updated, mergedPolicy.Spec.Egress[i].ToHTTPs = mergeHttpRules(existEgress, newEgress)
 						if egressMatched {
+							if kafkas, ok := mergeKafkaRules(existEgress.ToKafkas, newEgress.ToKafkas); ok {
+								mergedPolicy.Spec.Egress[i].ToKafkas = kafkas
+								updated = true
+							}
 							break
 						}
 					}
@@ -1714,9 +1781,17 @@ func mergeHttpRules(existRule types.L47Rule, newRule types.L47Rule) (bool, bool,
 	// Handling Ingress/Egress with L4 and HTTP rules
 	mergedHttpRule := existHttpRule
 	updated := false
-	if existPortRule[0].Equal(newPortRule[0]) {
+
+	portMatched := existPortRule[0].Equal(newPortRule[0])
+	if !portMatched && len(existHttpRule) == 0 && len(newHttpRule) == 0 &&
+		len(existRule.GetKafkaRules()) == 0 && len(newRule.GetKafkaRules()) == 0 {
+		// the port of a l4 rule may be in an aggregated port range
+		portMatched = existPortRule[0].Contains(newPortRule[0])
+	}
+
+	if portMatched {
 		for _, h := range newHttpRule {
-			if !libs.ContainsElement(existHttpRule, h) {
+			if !httpRuleContained(existHttpRule, h) {
 				mergedHttpRule = append(mergedHttpRule, h)
 				updated = true
 			}
@@ -1758,20 +1833,20 @@ func convertKnoxNetworkLogToKnoxNetworkPolicy(log *types.KnoxNetworkLog, pods []
 			ingress.ToPorts = append(ingress.ToPorts, egress.ToPorts...)
 		} else {
 			// 1.4 Set the icmp code/type
-			family := "IPv4"
-			if log.Protocol == libs.IPProtocolICMPv6 {
-				family = "IPv6"
-			}
+			family := libs.GetICMPFamily(log.Protocol)
 			egress.ICMPs = []types.SpecICMP{{Family: family, Type: uint8(log.ICMPType)}}
 			ingress.ICMPs = append(ingress.ICMPs, egress.ICMPs...)
 		}
 
 		if log.L7Protocol == libs.L7ProtocolHTTP {
-			httpRule := types.SpecHTTP{Method: log.HTTPMethod, Path: log.HTTPPath}
+			httpRule := getHTTPRule(log, log.DstNamespace, iPolicy.Spec.Selector.MatchLabels)
 			egress.ToHTTPs = []types.SpecHTTP{httpRule}
 			ingress.ToHTTPs = []types.SpecHTTP{httpRule}
 		}
 
+		egress.ToKafkas = getKafkaRules(log, cfg.GetCfgNetworkRuleTypes())
+		ingress.ToKafkas = egress.ToKafkas
+
 		ePolicy.Spec.Egress = append(ePolicy.Spec.Egress, egress)
 		iPolicy.Spec.Ingress = append(iPolicy.Spec.Ingress, ingress)
 
@@ -1800,17 +1875,15 @@ func convertKnoxNetworkLogToKnoxNetworkPolicy(log *types.KnoxNetworkLog, pods []
 				ingress.ToPorts = []types.SpecPort{{Port: strconv.Itoa(log.DstPort), Protocol: libs.GetProtocol(log.Protocol)}}
 			} else {
 				// 2.4 Set the icmp code/type
-				family := "IPv4"
-				if log.Protocol == libs.IPProtocolICMPv6 {
-					family = "IPv6"
-				}
+				family := libs.GetICMPFamily(log.Protocol)
 				ingress.ICMPs = []types.SpecICMP{{Family: family, Type: uint8(log.ICMPType)}}
 			}
 
 			if log.L7Protocol == libs.L7ProtocolHTTP {
-				httpRule := types.SpecHTTP{Method: log.HTTPMethod, Path: log.HTTPPath}
+				httpRule := getHTTPRule(log, log.DstNamespace, iPolicy.Spec.Selector.MatchLabels)
 				ingress.ToHTTPs = []types.SpecHTTP{httpRule}
 			}
+			ingress.ToKafkas = getKafkaRules(log, cfg.GetCfgNetworkRuleTypes())
 
 			iPolicy.Spec.Ingress = append(iPolicy.Spec.Ingress, ingress)
 			iPolicy.Metadata["namespace"] = log.DstNamespace
@@ -1831,8 +1904,10 @@ func convertKnoxNetworkLogToKnoxNetworkPolicy(log *types.KnoxNetworkLog, pods []
 		dstEntity := getEntityFromReservedLabels(log.DstReservedLabels)
 		if dstEntity != "" {
 			if dstEntity == "world" && log.DNSQuery != "" {
-				fqdn := types.SpecFQDN{[]string{log.DNSQuery}}
+				fqdn := types.SpecFQDN{MatchNames: []string{log.DNSQuery}}
 				egress.ToFQDNs = append(egress.ToFQDNs, fqdn)
+			} else if cidr, ok := getWorldCIDR(dstEntity, log.DstIP); ok {
+				egress.ToCIDRs = []types.SpecCIDR{{CIDRs: []string{cidr}}}
 			} else {
 				egress.ToEntities = append(egress.ToEntities, dstEntity)
 			}
@@ -1842,17 +1917,16 @@ func convertKnoxNetworkLogToKnoxNetworkPolicy(log *types.KnoxNetworkLog, pods []
 				egress.ToPorts = []types.SpecPort{{Port: strconv.Itoa(log.DstPort), Protocol: libs.GetProtocol(log.Protocol)}}
 			} else {
 				// 3.4 Set the icmp code/type
-				family := "IPv4"
-				if log.Protocol == libs.IPProtocolICMPv6 {
-					family = "IPv6"
-				}
+				family := libs.GetICMPFamily(log.Protocol)
 				egress.ICMPs = []types.SpecICMP{{Family: family, Type: uint8(log.ICMPType)}}
 			}
 
 			if log.L7Protocol == libs.L7ProtocolHTTP {
-				httpRule := types.SpecHTTP{Method: log.HTTPMethod, Path: log.HTTPPath}
+				// the external apis are matched by the hosts of their openapi documents
+				httpRule := getHTTPRule(log, log.DstNamespace, nil)
 				egress.ToHTTPs = []types.SpecHTTP{httpRule}
 			}
+			egress.ToKafkas = getKafkaRules(log, cfg.GetCfgNetworkRuleTypes())
 
 			ePolicy.Spec.Egress = append(ePolicy.Spec.Egress, egress)
 			ePolicy.Metadata["namespace"] = log.SrcNamespace
@@ -1952,6 +2026,15 @@ func isValidPolicy(policy *types.KnoxNetworkPolicy) bool {
 	return true
 }
 
+// getWorldCIDR returns the cidr of an address out of the cluster, if the world addresses are discovered as cidrs,
+// as the cidr aggregation requires, and the cidr rules are discovered
+func getWorldCIDR(entity, ip string) (string, bool) {
+	if entity != "world" || !(WorldCIDRs || isCIDRAggregationEnabled()) || cfg.GetCfgNetworkRuleTypes()&TO_CIDRS == 0 {
+		return "", false
+	}
+	return getCIDR(ip, CIDRBits, CIDRv6Bits)
+}
+
 func getEntityFromReservedLabels(reservedLabels []string) string {
 	entities := []string{}
 
@@ -1977,7 +2060,7 @@ func getEntityFromReservedLabels(reservedLabels []string) string {
 }
 
 func getEndpointMatchLabels(podName string, pods []types.Pod) map[string]string {
-	podLabels := getLabelsFromPod(podName, pods)
+	podLabels := filterSelectorLabels(getLabelsFromPod(podName, pods))
 	matchLabels := getLabelMapFromArray(podLabels)
 	return matchLabels
 }
@@ -2052,6 +2135,7 @@ func PopulateNetworkPoliciesFromNetworkLogs(networkLogs []types.KnoxNetworkLog)
 		namespaces, services, endpoints, pods, err := cluster.GetAllClusterResources(clusterName)
 		if err != nil {
 			log.Error().Msg(err.Error())
+			currentRun.AddError(err)
 			continue
 		}
 
@@ -2063,6 +2147,9 @@ func PopulateNetworkPoliciesFromNetworkLogs(networkLogs []types.KnoxNetworkLog)
 		// update service ports (k8s service, endpoint, kube-dns)
 		updateServiceEndpoint(services, endpoints, pods)
 
+		// select the pods of the openapi documents by the service selectors of the cluster
+		resolveOpenAPIServices(services)
+
 		log.Info().Msgf("FilterNetworkLogsByConfig for cluster [%s]", clusterName)
 		// filter ignoring network logs from configuration
 		filteredLogs := FilterNetworkLogsByConfig(networkLogs, pods)
@@ -2075,13 +2162,16 @@ func PopulateNetworkPoliciesFromNetworkLogs(networkLogs []types.KnoxNetworkLog)
 			if len(logsPerNamespace) == 0 {
 				continue
 			}
+			currentRun.AddNamespace(clusterName, namespace)
 
 			// reset flow id track at each target namespace
 			clearTrackFlowIDMaps()
 
 			log.Info().Msgf("DiscoverNetworkPolicy for cluster [%s] namespace [%s]", clusterName, namespace)
 			// discover network policies based on the network logs
+			discoveryStart := time.Now()
 			discoveredNetPolicies := DiscoverNetworkPolicy(namespace, logsPerNamespace, services, pods)
+			metrics.ObserveDiscovery(metrics.PolicyTypeNetwork, clusterName, namespace, discoveryStart)
 
 			// Segregate policies based on policy namespace
 			// Context:
@@ -2122,16 +2212,44 @@ func PopulateNetworkPoliciesFromNetworkLogs(networkLogs []types.KnoxNetworkLog)
 				libs.InsertNetworkPolicies(CfgDB, newPolicies)
 				writeNetworkPoliciesYamlToDB(newPolicies)
 			}
+			markOutdatedPolicyYamls()
 			log.Info().Msgf("-> Network policy discovery done for namespace: [%s], [%d] policies updated, [%d] policies newly discovered", namespace, len(updatedPolicies), len(newPolicies))
 		}
 
 		// update cluster global variables
 		updateMultiClusterVariables(clusterName)
+
+		// record the progress of the run at each cluster
+		currentRun.AddNamespace(clusterName, "")
+		libs.SaveDiscoveryRun(CfgDB, currentRun)
 	}
 
 	return discoveredNetworkPolicies
 }
 
+// newNetworkPolicyYaml builds the policy yaml stored in the db from a converted policy
+func newNetworkPolicyYaml(kind, cluster string, metadata map[string]string, labels types.LabelMap, policy interface{}) (types.PolicyYaml, error) {
+	jsonBytes, err := json.Marshal(policy)
+	if err != nil {
+		return types.PolicyYaml{}, err
+	}
+	yamlBytes, err := yaml.JSONToYAML(jsonBytes)
+	if err != nil {
+		return types.PolicyYaml{}, err
+	}
+
+	return types.PolicyYaml{
+		Type:      types.PolicyTypeNetwork,
+		Kind:      kind,
+		Name:      metadata["name"],
+		Namespace: metadata["namespace"],
+		Cluster:   cluster,
+		Labels:    labels,
+		Yaml:      yamlBytes,
+		Replaces:  getReplacedPolicy(metadata["name"]),
+	}, nil
+}
+
 func writeNetworkPoliciesYamlToDB(policies []types.KnoxNetworkPolicy) {
 	clusters := []string{}
 
@@ -2145,17 +2263,6 @@ func writeNetworkPoliciesYamlToDB(policies []types.KnoxNetworkPolicy) {
 	res := []types.PolicyYaml{}
 
 	for i, ciliumPolicy := range ciliumPolicies {
-		jsonBytes, err := json.Marshal(ciliumPolicy)
-		if err != nil {
-			log.Error().Msg(err.Error())
-			continue
-		}
-		yamlBytes, err := yaml.JSONToYAML(jsonBytes)
-		if err != nil {
-			log.Error().Msg(err.Error())
-			continue
-		}
-
 		var labels types.LabelMap
 		if ciliumPolicy.Kind == cu.ResourceTypeCiliumNetworkPolicy {
 			labels = ciliumPolicy.Spec.EndpointSelector.MatchLabels
@@ -2163,26 +2270,123 @@ func writeNetworkPoliciesYamlToDB(policies []types.KnoxNetworkPolicy) {
 			labels = ciliumPolicy.Spec.NodeSelector.MatchLabels
 		}
 
-		policyYaml := types.PolicyYaml{
-			Type:      types.PolicyTypeNetwork,
-			Kind:      ciliumPolicy.Kind,
-			Name:      ciliumPolicy.Metadata["name"],
-			Namespace: ciliumPolicy.Metadata["namespace"],
-			Cluster:   clusters[i],
-			Labels:    labels,
-			Yaml:      yamlBytes,
+		policyYaml, err := newNetworkPolicyYaml(ciliumPolicy.Kind, clusters[i], ciliumPolicy.Metadata, labels, ciliumPolicy)
+		if err != nil {
+			log.Error().Msg(err.Error())
+			continue
 		}
 		res = append(res, policyYaml)
+	}
+
+	// the calico policies are stored alongside the cilium ones, under their own kinds
+	if libs.ContainsElement(cfg.GetCfgNetworkPolicyFormats(), types.NetworkPolicyFormatCalico) {
+		for i, policy := range policies {
+			calicoPolicy, ok, _ := plugin.ConvertKnoxNetworkPolicyToCalicoPolicy(policy)
+			if !ok {
+				continue
+			}
+
+			kind := types.KindCalicoNetworkPolicy
+			if calicoPolicy.Kind == types.CalicoGlobalNwPolicyKind {
+				kind = types.KindCalicoGlobalNetworkPolicy
+			}
+
+			policyYaml, err := newNetworkPolicyYaml(kind, clusters[i], calicoPolicy.Metadata, policy.Spec.Selector.MatchLabels, calicoPolicy)
+			if err != nil {
+				log.Error().Msg(err.Error())
+				continue
+			}
+			res = append(res, policyYaml)
+		}
+	}
+
+	// the istio authorization policies enforce the http rules in the mesh
+	if libs.ContainsElement(cfg.GetCfgNetworkPolicyFormats(), types.NetworkPolicyFormatIstio) {
+		for i, policy := range policies {
+			istioPolicy, ok, _ := plugin.ConvertKnoxNetworkPolicyToIstioPolicy(policy)
+			if !ok {
+				continue
+			}
 
-		PolicyStore.Publish(&policyYaml)
+			policyYaml, err := newNetworkPolicyYaml(istioPolicy.Kind, clusters[i], istioPolicy.Metadata, istioPolicy.Spec.Selector.MatchLabels, istioPolicy)
+			if err != nil {
+				log.Error().Msg(err.Error())
+				continue
+			}
+			res = append(res, policyYaml)
+		}
 	}
 
-	if err := libs.UpdateOrInsertPolicyYamls(CfgDB, res); err != nil {
+	// the policies are published once written, with their revisions
+	if err := PolicyStore.UpdateAndPublish(CfgDB, res); err != nil {
 		log.Error().Msgf(err.Error())
+		currentRun.AddError(err)
+	}
+}
+
+// setReplacedPolicy records the policy outdated by the new one
+func setReplacedPolicy(outdated, newName string) {
+	replacedPoliciesMutex.Lock()
+	defer replacedPoliciesMutex.Unlock()
+
+	replacedPolicies[outdated] = newName
+}
+
+// getReplacedPolicy returns the name of the policy outdated by the given one, if any
+func getReplacedPolicy(name string) string {
+	replacedPoliciesMutex.Lock()
+	defer replacedPoliciesMutex.Unlock()
+
+	for outdated, newName := range replacedPolicies {
+		if newName == name {
+			return outdated
+		}
+	}
+	return ""
+}
+
+// markOutdatedPolicyYamls publishes the outdated event of the policies replaced since the last call
+func markOutdatedPolicyYamls() {
+	replacedPoliciesMutex.Lock()
+	replaced := replacedPolicies
+	replacedPolicies = map[string]string{}
+	replacedPoliciesMutex.Unlock()
+
+	if len(replaced) == 0 {
+		return
+	}
+
+	if err := PolicyStore.MarkPolicyYamls(CfgDB, types.PolicyTypeNetwork, types.PolicyEventOutdated, replaced); err != nil {
+		log.Error().Msgf("Failed to mark the outdated network policies: %v", err)
+		currentRun.AddError(err)
+	}
+}
+
+// PublishDeletedPolicies publishes the deleted event of all the live network policies, once they are cleared from the db
+func PublishDeletedPolicies(cfgDB types.ConfigDB) {
+	if err := PolicyStore.MarkPolicyYamls(cfgDB, types.PolicyTypeNetwork, types.PolicyEventDeleted, nil); err != nil {
+		log.Error().Msgf("Failed to mark the deleted network policies: %v", err)
 	}
 }
 
+// discoverNetworkPolicyRun discovers the policies from the network logs, and records the run in the db
+func discoverNetworkPolicyRun(trigger string, networkLogs []types.KnoxNetworkLog) {
+	currentRun = libs.StartDiscoveryRun(CfgDB, types.PolicyTypeNetwork, trigger, len(networkLogs))
+	defer func() {
+		currentRun.Finish()
+		libs.SaveDiscoveryRun(CfgDB, currentRun)
+		currentRun = nil
+	}()
+
+	PopulateNetworkPoliciesFromNetworkLogs(networkLogs)
+}
+
+// DiscoverNetworkPolicyMain is the discovery job of the cron
 func DiscoverNetworkPolicyMain() {
+	discoverNetworkPolicyMain(types.DiscoveryTriggerCron)
+}
+
+func discoverNetworkPolicyMain(trigger string) {
 	if NetworkWorkerStatus == STATUS_RUNNING {
 		return
 	} else {
@@ -2202,18 +2406,53 @@ func DiscoverNetworkPolicyMain() {
 		return
 	}
 
-	PopulateNetworkPoliciesFromNetworkLogs(allNetworkLogs)
+	discoverNetworkPolicyRun(trigger, allNetworkLogs)
+}
+
+// DiscoverNetworkPolicyPending runs a last discovery on the buffered network logs regardless of the operation trigger,
+// so that the logs received since the last run are not lost on shutdown
+func DiscoverNetworkPolicyPending() {
+	if NetworkWorkerStatus == STATUS_RUNNING {
+		return
+	}
+
+	NetworkWorkerStatus = STATUS_RUNNING
+	defer func() {
+		NetworkWorkerStatus = STATUS_IDLE
+	}()
+
+	InitNetPolicyDiscoveryConfiguration()
+
+	// the file is read from the beginning at each run, there is nothing pending
+	if NetworkLogFrom != "hubble" && NetworkLogFrom != "feed-consumer" {
+		return
+	}
+
+	OperationTrigger = 1
 
+	allNetworkLogs := getNetworkLogs()
+	if len(allNetworkLogs) == 0 {
+		return
+	}
+
+	log.Info().Msgf("Discovering network policies from [%d] pending network logs", len(allNetworkLogs))
+	discoverNetworkPolicyRun(types.DiscoveryTriggerShutdown, allNetworkLogs)
 }
 
 // ===================================== //
 // == Network Policy Discovery Worker == //
 // ===================================== //
 
-func StartNetworkLogRcvr() {
+func StartNetworkLogRcvr(stopChan chan struct{}) {
 	for {
+		select {
+		case <-stopChan:
+			return
+		default:
+		}
+
 		if cfg.GetCfgNetworkLogFrom() == "hubble" {
-			plugin.StartHubbleRelay(NetworkStopChan /* &NetworkWaitG, */, cfg.GetCfgCiliumHubble())
+			plugin.StartHubbleRelay(stopChan /* &NetworkWaitG, */, cfg.GetCfgCiliumHubble())
 		} else if cfg.GetCfgNetworkLogFrom() == "feed-consumer" {
 			fc.ConsumerMutex.Lock()
 			fc.StartConsumer()
@@ -2224,7 +2463,9 @@ func StartNetworkLogRcvr() {
 }
 
 func StartNetworkCronJob() {
-	go StartNetworkLogRcvr()
+	// the previous stop channel is closed if the worker has been stopped before
+	NetworkStopChan = make(chan struct{})
+	go StartNetworkLogRcvr(NetworkStopChan)
 
 	// init cron job
 	NetworkCronJob = cron.New()
@@ -2251,7 +2492,8 @@ func StopNetworkCronJob() {
 	}
 }
 
-func StartNetworkWorker() {
+// StartNetworkWorker starts the cron job, or runs the one-time discovery recorded with the given trigger
+func StartNetworkWorker(trigger string) {
 	if NetworkWorkerStatus != STATUS_IDLE {
 		log.Info().Msg("There is no idle network policy discovery worker")
 		return
@@ -2262,7 +2504,7 @@ func StartNetworkWorker() {
 	} else if cfg.GetCfgNetOperationMode() == OP_MODE_CRONJOB { // every time intervals
 		StartNetworkCronJob()
 	} else { // one-time generation
-		DiscoverNetworkPolicyMain()
+		discoverNetworkPolicyMain(trigger)
 		log.Info().Msgf("Auto network policy onetime job done")
 	}
 }
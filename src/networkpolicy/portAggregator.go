@@ -0,0 +1,213 @@
+package networkpolicy
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+// PortRangeMinPorts is the number of ports of a peer collapsed into a range, less than 2 disables it
+var PortRangeMinPorts int
+
+// PortRangeMaxGap is the number of unobserved ports a range spans between two observed ports
+var PortRangeMaxGap int
+
+// ============================ //
+// == Port Range Aggregation == //
+// ============================ //
+
+// portInterval is a port or a port range of a protocol, with the number of the ports observed in it
+type portInterval struct {
+	start  int
+	end    int
+	weight int
+}
+
+// collapsePorts collapses the runs of the ports, no farther than maxGap from each other, into ranges
+// if there are minPorts of them, the existing ranges absorb the ports of their runs
+func collapsePorts(intervals []portInterval, minPorts, maxGap int) []portInterval {
+	sort.Slice(intervals, func(i, j int) bool {
+		if intervals[i].start != intervals[j].start {
+			return intervals[i].start < intervals[j].start
+		}
+		return intervals[i].end < intervals[j].end
+	})
+
+	res := []portInterval{}
+	run := []portInterval{}
+	runEnd, runWeight := 0, 0
+
+	flush := func() {
+		if len(run) > 1 && runWeight >= minPorts {
+			res = append(res, portInterval{start: run[0].start, end: runEnd, weight: runWeight})
+		} else {
+			res = append(res, run...)
+		}
+		run = []portInterval{}
+		runEnd, runWeight = 0, 0
+	}
+
+	for _, interval := range intervals {
+		if len(run) > 0 && interval.start-runEnd-1 > maxGap {
+			flush()
+		}
+		run = append(run, interval)
+		if interval.end > runEnd {
+			runEnd = interval.end
+		}
+		runWeight += interval.weight
+	}
+	if len(run) > 0 {
+		flush()
+	}
+
+	return res
+}
+
+// aggregatePorts collapses the numbered ports of each protocol into ranges,
+// ok is false if no range is built or extended
+func aggregatePorts(ports []types.SpecPort) ([]types.SpecPort, bool) {
+	res := []types.SpecPort{}
+	protocols := []string{}
+	intervalsPerProtocol := map[string][]portInterval{}
+
+	for _, port := range ports {
+		start, err := strconv.Atoi(port.Port)
+		// the named ports and port 0, all the ports, are kept as they are
+		if err != nil || start == 0 {
+			res = append(res, port)
+			continue
+		}
+
+		interval := portInterval{start: start, end: start, weight: 1}
+		if port.EndPort > start {
+			// the range of a previous aggregation collapses the ports next to it
+			interval.end = port.EndPort
+			interval.weight = PortRangeMinPorts
+		}
+
+		if _, ok := intervalsPerProtocol[port.Protocol]; !ok {
+			protocols = append(protocols, port.Protocol)
+		}
+		if !libs.ContainsElement(intervalsPerProtocol[port.Protocol], interval) {
+			intervalsPerProtocol[port.Protocol] = append(intervalsPerProtocol[port.Protocol], interval)
+		}
+	}
+
+	updated := false
+	for _, protocol := range protocols {
+		intervals := intervalsPerProtocol[protocol]
+		collapsed := collapsePorts(intervals, PortRangeMinPorts, PortRangeMaxGap)
+		if len(collapsed) != len(intervals) {
+			updated = true
+		}
+
+		for _, interval := range collapsed {
+			port := types.SpecPort{Port: strconv.Itoa(interval.start), Protocol: protocol}
+			if interval.end > interval.start {
+				port.EndPort = interval.end
+			}
+			res = append(res, port)
+		}
+	}
+
+	return res, updated
+}
+
+// portPeerKey identifies the peer of a l4 rule, the json of the rule without the ports,
+// ok is false if the rule has l7 or icmp rules
+func portPeerKey(rule interface{}, ports []types.SpecPort, l47 types.L47Rule) (string, bool) {
+	if len(ports) == 0 || len(l47.GetICMPRules()) > 0 || len(l47.GetHTTPRules()) > 0 || len(l47.GetKafkaRules()) > 0 {
+		return "", false
+	}
+
+	key, err := json.Marshal(rule)
+	if err != nil {
+		return "", false
+	}
+	return string(key), true
+}
+
+// aggregatePeerPortRanges collapses the ports of the n rules of the same peer, peerPorts returns the peer key
+// and the ports of a rule, ok is false if the rule is not aggregated. rebuild appends a rule with the given ports,
+// a rule per port or range at the first rule of the peer, or the rule as it is if the ports are nil
+func aggregatePeerPortRanges(n int, peerPorts func(i int) (string, []types.SpecPort, bool), rebuild func(i int, ports []types.SpecPort)) {
+	keys := []string{}
+	portsPerKey := map[string][]types.SpecPort{}
+	for i := 0; i < n; i++ {
+		key, ports, ok := peerPorts(i)
+		keys = append(keys, key)
+		if ok {
+			portsPerKey[key] = append(portsPerKey[key], ports...)
+		}
+	}
+
+	aggregatedPorts := map[string][]types.SpecPort{}
+	for key, ports := range portsPerKey {
+		if aggregated, updated := aggregatePorts(ports); updated {
+			aggregatedPorts[key] = aggregated
+		}
+	}
+
+	emittedKeys := map[string]bool{}
+	for i := 0; i < n; i++ {
+		ports, ok := aggregatedPorts[keys[i]]
+		if !ok {
+			rebuild(i, nil)
+			continue
+		}
+		if emittedKeys[keys[i]] {
+			continue
+		}
+		emittedKeys[keys[i]] = true
+
+		for _, port := range ports {
+			rebuild(i, []types.SpecPort{port})
+		}
+	}
+}
+
+// aggregatePortRanges collapses the ports of the l4 rules of the same peer into port ranges,
+// a rule per port or range at the first rule of the peer
+func aggregatePortRanges(policy *types.KnoxNetworkPolicy) {
+	if PortRangeMinPorts < 2 {
+		return
+	}
+
+	// egress rules
+	egresses := []types.Egress{}
+	aggregatePeerPortRanges(len(policy.Spec.Egress), func(i int) (string, []types.SpecPort, bool) {
+		egress := policy.Spec.Egress[i]
+		peer := egress
+		peer.ToPorts = nil
+		key, ok := portPeerKey(peer, egress.ToPorts, egress)
+		return key, egress.ToPorts, ok
+	}, func(i int, ports []types.SpecPort) {
+		rule := policy.Spec.Egress[i]
+		if ports != nil {
+			rule.ToPorts = ports
+		}
+		egresses = append(egresses, rule)
+	})
+	policy.Spec.Egress = egresses
+
+	// ingress rules
+	ingresses := []types.Ingress{}
+	aggregatePeerPortRanges(len(policy.Spec.Ingress), func(i int) (string, []types.SpecPort, bool) {
+		ingress := policy.Spec.Ingress[i]
+		peer := ingress
+		peer.ToPorts = nil
+		key, ok := portPeerKey(peer, ingress.ToPorts, ingress)
+		return key, ingress.ToPorts, ok
+	}, func(i int, ports []types.SpecPort) {
+		rule := policy.Spec.Ingress[i]
+		if ports != nil {
+			rule.ToPorts = ports
+		}
+		ingresses = append(ingresses, rule)
+	})
+	policy.Spec.Ingress = ingresses
+}
@@ -0,0 +1,90 @@
+package networkpolicy
+
+import (
+	"testing"
+
+	types "github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func setPortRange(t *testing.T, minPorts, maxGap int) {
+	PortRangeMinPorts, PortRangeMaxGap = minPorts, maxGap
+	t.Cleanup(func() {
+		PortRangeMinPorts, PortRangeMaxGap = 0, 0
+	})
+}
+
+// ============================ //
+// == Port Range Aggregation == //
+// ============================ //
+
+func TestAggregatePorts(t *testing.T) {
+	setPortRange(t, 3, 2)
+
+	// the dense ports collapse, the stray ports and the named ports are kept
+	actual, updated := aggregatePorts([]types.SpecPort{
+		{Port: "30004", Protocol: "udp"}, {Port: "30000", Protocol: "udp"}, {Port: "30002", Protocol: "udp"},
+		{Port: "30100", Protocol: "udp"}, {Port: "30001", Protocol: "tcp"}, {Port: "http", Protocol: "tcp"},
+	})
+	assert.True(t, updated)
+	assert.Equal(t, []types.SpecPort{
+		{Port: "http", Protocol: "tcp"},
+		{Port: "30000", EndPort: 30004, Protocol: "udp"}, {Port: "30100", Protocol: "udp"},
+		{Port: "30001", Protocol: "tcp"},
+	}, actual)
+
+	// below the threshold, nothing changes
+	_, updated = aggregatePorts([]types.SpecPort{{Port: "80", Protocol: "tcp"}, {Port: "81", Protocol: "tcp"}})
+	assert.False(t, updated)
+
+	// an existing range absorbs a port next to it
+	actual, updated = aggregatePorts([]types.SpecPort{{Port: "30000", EndPort: 30004, Protocol: "udp"}, {Port: "30007", Protocol: "udp"}})
+	assert.True(t, updated)
+	assert.Equal(t, []types.SpecPort{{Port: "30000", EndPort: 30007, Protocol: "udp"}}, actual)
+}
+
+func TestAggregatePortRanges(t *testing.T) {
+	setPortRange(t, 3, 0)
+
+	client := map[string]string{"app": "client"}
+	policy := types.KnoxNetworkPolicy{}
+	policy.Spec.Ingress = []types.Ingress{
+		{MatchLabels: client, ToPorts: []types.SpecPort{{Port: "5000", Protocol: "udp"}}},
+		{MatchLabels: map[string]string{"app": "admin"}, ToPorts: []types.SpecPort{{Port: "5001", Protocol: "udp"}}},
+		{MatchLabels: client, ToPorts: []types.SpecPort{{Port: "5001", Protocol: "udp"}}},
+		{MatchLabels: client, ToPorts: []types.SpecPort{{Port: "5002", Protocol: "udp"}}},
+		{MatchLabels: client, ToPorts: []types.SpecPort{{Port: "8080", Protocol: "tcp"}}, ToHTTPs: []types.SpecHTTP{{Method: "GET", Path: "/"}}},
+	}
+
+	aggregatePortRanges(&policy)
+
+	assert.Len(t, policy.Spec.Ingress, 3)
+	assert.Equal(t, []types.SpecPort{{Port: "5000", EndPort: 5002, Protocol: "udp"}}, policy.Spec.Ingress[0].ToPorts)
+	assert.Equal(t, map[string]string{"app": "admin"}, policy.Spec.Ingress[1].MatchLabels)
+	assert.Len(t, policy.Spec.Ingress[2].ToHTTPs, 1)
+
+	// a new port in the range does not update the policy
+	newPolicy := types.KnoxNetworkPolicy{}
+	newPolicy.Spec.Ingress = []types.Ingress{{MatchLabels: client, ToPorts: []types.SpecPort{{Port: "5001", Protocol: "udp"}}}}
+
+	_, updated := mergeIngressPolicies(policy, []types.KnoxNetworkPolicy{newPolicy})
+	assert.False(t, updated)
+}
+
+func TestAggregatePortRangesEgress(t *testing.T) {
+	setPortRange(t, 3, 1)
+
+	https := []types.SpecPort{{Port: "443", Protocol: "tcp"}}
+	policy := types.KnoxNetworkPolicy{}
+	policy.Spec.Egress = []types.Egress{
+		{ToEntities: []string{"kube-apiserver"}, ToPorts: https},
+		{ToCIDRs: []types.SpecCIDR{{CIDRs: []string{"10.0.0.1/32"}}}, ToPorts: []types.SpecPort{{Port: "9000", Protocol: "tcp"}}},
+		{ToCIDRs: []types.SpecCIDR{{CIDRs: []string{"10.0.0.1/32"}}}, ToPorts: []types.SpecPort{{Port: "9001", Protocol: "tcp"}, {Port: "9003", Protocol: "tcp"}}},
+	}
+
+	aggregatePortRanges(&policy)
+
+	assert.Len(t, policy.Spec.Egress, 2)
+	assert.Equal(t, https, policy.Spec.Egress[0].ToPorts)
+	assert.Equal(t, []types.SpecPort{{Port: "9000", EndPort: 9003, Protocol: "tcp"}}, policy.Spec.Egress[1].ToPorts)
+}
@@ -98,6 +98,30 @@ func InitObservability() {
 	}
 }
 
+// StopObservability stops the cron jobs, then persists the buffered logs and summaries and publishes the pending summaries
+func StopObservability() {
+	if ObsCronJob != nil {
+		ObsCronJob.Stop()
+		ObsCronJob = nil
+	}
+
+	if PublisherCronJob != nil {
+		PublisherCronJob.Stop()
+		PublisherCronJob = nil
+	}
+
+	if cfg.GetCfgObservabilityEnable() {
+		// stores the buffered logs and upserts the system summaries into the db
+		ObservabilityCronJob()
+	}
+
+	if cfg.GetCfgPublisherEnable() {
+		ProcessSystemSummary()
+	}
+
+	log.Info().Msg("Observability stopped")
+}
+
 func ObservabilityCronJob() {
 	if cfg.GetCfgObservabilitySysObsStatus() {
 		ProcessSystemLogs()
@@ -0,0 +1,367 @@
+package plugin
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+const (
+	// AdminPolicyPriorityBase is the priority of the first proposed admin network policy
+	AdminPolicyPriorityBase = 100
+
+	// AdminPolicyPriorityMax is the highest priority value of an admin network policy
+	AdminPolicyPriorityMax = 1000
+
+	// BaselineAdminNetworkPolicyName is the name of the baseline policy, a singleton in the cluster
+	BaselineAdminNetworkPolicyName = "default"
+)
+
+// worldNetworks are the networks of all the ipv4 and ipv6 addresses
+var worldNetworks = []string{"0.0.0.0/0", "::/0"}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// ====================================== //
+// == Admin Network Policy Aggregation == //
+// ====================================== //
+
+// commonRule is a rule discovered in several namespaces, the same peer and ports
+type commonRule struct {
+	key        string
+	direction  string
+	name       string
+	peer       types.AdminNetworkPolicyPeer
+	ports      []types.AdminNetworkPolicyPort
+	namespaces []string
+}
+
+// adminPolicyName builds a valid object name of the name parts
+func adminPolicyName(parts ...string) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(strings.Join(parts, "-")), "-")
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return strings.Trim(name, "-")
+}
+
+// namespaceSelector selects the namespaces by name
+func namespaceSelector(namespaces ...string) types.AdminNetworkPolicySelector {
+	if len(namespaces) == 1 {
+		return types.AdminNetworkPolicySelector{MatchLabels: map[string]string{K8sNamespaceNameLabel: namespaces[0]}}
+	}
+	return types.AdminNetworkPolicySelector{
+		MatchExpressions: []types.LabelSelectorRequirement{
+			{Key: K8sNamespaceNameLabel, Operator: "In", Values: namespaces},
+		},
+	}
+}
+
+// adminPolicyPorts converts the knox ports, nil means all the ports
+func adminPolicyPorts(ports []types.SpecPort) ([]types.AdminNetworkPolicyPort, []string) {
+	res := []types.AdminNetworkPolicyPort{}
+	names := []string{}
+
+	for _, port := range ports {
+		protocol := strings.ToUpper(port.Protocol)
+		if protocol == "" {
+			protocol = "TCP"
+		}
+
+		// port 0 means all the ports
+		if port.Port == "" || port.Port == "0" {
+			return nil, nil
+		}
+
+		portVal, err := strconv.Atoi(port.Port)
+		if err != nil {
+			res = append(res, types.AdminNetworkPolicyPort{NamedPort: port.Port})
+			names = append(names, port.Port)
+			continue
+		}
+
+		protocols := []string{protocol}
+		if protocol == "ANY" {
+			protocols = []string{"TCP", "UDP", "SCTP"}
+		}
+		for _, proto := range protocols {
+			if port.EndPort > portVal {
+				res = append(res, types.AdminNetworkPolicyPort{
+					PortRange: &types.AdminNetworkPolicyPortRange{Protocol: proto, Start: portVal, End: port.EndPort},
+				})
+				continue
+			}
+			res = append(res, types.AdminNetworkPolicyPort{
+				PortNumber: &types.AdminNetworkPolicyPortNumber{Protocol: proto, Port: portVal},
+			})
+		}
+		if port.EndPort > portVal {
+			names = append(names, protocol+"-"+port.Port+"-"+strconv.Itoa(port.EndPort))
+		} else {
+			names = append(names, protocol+"-"+port.Port)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+	return res, names
+}
+
+// adminPolicyPeer builds the peer of a label rule, ok is false if the peer is in the namespace of the policy
+func adminPolicyPeer(namespace string, matchLabels map[string]string) (types.AdminNetworkPolicyPeer, []string, bool) {
+	peerNamespace := ""
+	labels := map[string]string{}
+	for k, v := range matchLabels {
+		if libs.ContainsElement(knoxNamespaceLabels, k) {
+			peerNamespace = v
+			continue
+		}
+		labels[k] = v
+	}
+
+	// a cluster wide policy cannot select the own namespace of each subject
+	if peerNamespace == "" || peerNamespace == namespace {
+		return types.AdminNetworkPolicyPeer{}, nil, false
+	}
+
+	names := []string{peerNamespace}
+	if len(labels) == 0 {
+		selector := namespaceSelector(peerNamespace)
+		return types.AdminNetworkPolicyPeer{Namespaces: &selector}, names, true
+	}
+
+	labels = k8sLabels(labels)
+	keys := []string{}
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		names = append(names, labels[k])
+	}
+
+	return types.AdminNetworkPolicyPeer{
+		Pods: &types.AdminNetworkPolicyPods{
+			NamespaceSelector: namespaceSelector(peerNamespace),
+			PodSelector:       types.AdminNetworkPolicySelector{MatchLabels: labels},
+		},
+	}, names, true
+}
+
+// adminPolicyAggregator collects the rules of the namespaced policies, per peer and ports
+type adminPolicyAggregator struct {
+	rules map[string]*commonRule
+
+	namespaces      []string
+	worldNamespaces []string
+}
+
+func (a *adminPolicyAggregator) add(direction, namespace string, peer types.AdminNetworkPolicyPeer, peerNames []string, ports []types.SpecPort) {
+	adminPorts, portNames := adminPolicyPorts(ports)
+
+	// the json of the peer and the ports identifies the rule, its map keys are sorted
+	key, err := json.Marshal(struct {
+		Direction string
+		Peer      types.AdminNetworkPolicyPeer
+		Ports     []types.AdminNetworkPolicyPort
+	}{direction, peer, adminPorts})
+	if err != nil {
+		return
+	}
+
+	rule, ok := a.rules[string(key)]
+	if !ok {
+		nameParts := append([]string{direction}, peerNames...)
+		rule = &commonRule{
+			key:       string(key),
+			direction: direction,
+			name:      adminPolicyName(append(nameParts, portNames...)...),
+			peer:      peer,
+			ports:     adminPorts,
+		}
+		a.rules[string(key)] = rule
+	}
+
+	if !libs.ContainsElement(rule.namespaces, namespace) {
+		rule.namespaces = append(rule.namespaces, namespace)
+	}
+}
+
+func (a *adminPolicyAggregator) addPolicy(policy types.KnoxNetworkPolicy) {
+	namespace := policy.Metadata["namespace"]
+	if policy.Kind == types.KindKnoxHostNetworkPolicy || namespace == "" {
+		return
+	}
+
+	if !libs.ContainsElement(a.namespaces, namespace) {
+		a.namespaces = append(a.namespaces, namespace)
+	}
+
+	for _, egress := range policy.Spec.Egress {
+		switch {
+		case egress.MatchLabels != nil:
+			if peer, names, ok := adminPolicyPeer(namespace, egress.MatchLabels); ok {
+				a.add(directionEgress, namespace, peer, names, egress.ToPorts)
+			}
+		case len(egress.ToCIDRs) > 0:
+			nets, _ := calicoNets(egress.ToCIDRs)
+			a.add(directionEgress, namespace, types.AdminNetworkPolicyPeer{Networks: nets}, nets, egress.ToPorts)
+			a.addWorldNamespace(namespace)
+		case len(egress.ToFQDNs) > 0 || len(egress.ToServices) > 0:
+			// the fqdns and the services without selectors are external addresses
+			a.addWorldNamespace(namespace)
+		case len(egress.ToEntities) > 0:
+			// the entities but the cluster have addresses out of the pod networks, e.g., the nodes
+			for _, entity := range egress.ToEntities {
+				if entity != "cluster" {
+					a.addWorldNamespace(namespace)
+				}
+			}
+		}
+	}
+
+	for _, ingress := range policy.Spec.Ingress {
+		if ingress.MatchLabels == nil {
+			continue
+		}
+		if peer, names, ok := adminPolicyPeer(namespace, ingress.MatchLabels); ok {
+			a.add(directionIngress, namespace, peer, names, ingress.ToPorts)
+		}
+	}
+}
+
+func (a *adminPolicyAggregator) addWorldNamespace(namespace string) {
+	if !libs.ContainsElement(a.worldNamespaces, namespace) {
+		a.worldNamespaces = append(a.worldNamespaces, namespace)
+	}
+}
+
+// adminPolicies proposes an admin network policy allowing each rule discovered in minNamespaces namespaces at least
+func (a *adminPolicyAggregator) adminPolicies(minNamespaces int) []types.AdminNetworkPolicy {
+	common := []*commonRule{}
+	for _, rule := range a.rules {
+		if len(rule.namespaces) >= minNamespaces {
+			common = append(common, rule)
+		}
+	}
+	sort.Slice(common, func(i, j int) bool {
+		if common[i].name != common[j].name {
+			return common[i].name < common[j].name
+		}
+		return common[i].key < common[j].key
+	})
+
+	res := []types.AdminNetworkPolicy{}
+	names := map[string]int{}
+
+	for i, rule := range common {
+		sort.Strings(rule.namespaces)
+
+		// the names are truncated, they may collide
+		name := adminPolicyName("common", rule.name)
+		if count := names[name]; count > 0 {
+			names[name]++
+			suffix := strconv.Itoa(count)
+			if len(name)+len(suffix) >= 63 {
+				name = name[:62-len(suffix)]
+			}
+			name = adminPolicyName(name, suffix)
+		} else {
+			names[name] = 1
+		}
+
+		adminRule := types.AdminNetworkPolicyRule{
+			Name:   rule.name,
+			Action: types.AdminNwPolicyActionAllow,
+			Ports:  rule.ports,
+		}
+		policy := types.AdminNetworkPolicy{
+			APIVersion: types.AdminNwPolicyAPIVersion,
+			Kind:       types.KindAdminNetworkPolicy,
+			Metadata:   map[string]string{"name": name},
+		}
+		subject := namespaceSelector(rule.namespaces...)
+		policy.Spec.Subject.Namespaces = &subject
+		// the policies only allow, their order does not matter past the highest priority value
+		policy.Spec.Priority = AdminPolicyPriorityBase + i
+		if policy.Spec.Priority > AdminPolicyPriorityMax {
+			policy.Spec.Priority = AdminPolicyPriorityMax
+		}
+
+		if rule.direction == directionEgress {
+			adminRule.To = []types.AdminNetworkPolicyPeer{rule.peer}
+			policy.Spec.Egress = []types.AdminNetworkPolicyRule{adminRule}
+		} else {
+			adminRule.From = []types.AdminNetworkPolicyPeer{rule.peer}
+			policy.Spec.Ingress = []types.AdminNetworkPolicyRule{adminRule}
+		}
+
+		res = append(res, policy)
+	}
+
+	return res
+}
+
+// baselinePolicy proposes to deny the egress to the world of the namespaces that never reached it,
+// the traffic inside the cluster is allowed first since the world network includes the pod addresses
+func (a *adminPolicyAggregator) baselinePolicy(minNamespaces int) (types.AdminNetworkPolicy, bool) {
+	restricted := []string{}
+	for _, namespace := range a.namespaces {
+		if !libs.ContainsElement(a.worldNamespaces, namespace) {
+			restricted = append(restricted, namespace)
+		}
+	}
+	if len(restricted) < minNamespaces {
+		return types.AdminNetworkPolicy{}, false
+	}
+	sort.Strings(restricted)
+
+	subject := namespaceSelector(restricted...)
+	return types.AdminNetworkPolicy{
+		APIVersion: types.AdminNwPolicyAPIVersion,
+		Kind:       types.KindBaselineAdminNetworkPolicy,
+		Metadata:   map[string]string{"name": BaselineAdminNetworkPolicyName},
+		Spec: types.AdminNetworkPolicySpec{
+			Subject: types.AdminNetworkPolicyPeer{Namespaces: &subject},
+			Egress: []types.AdminNetworkPolicyRule{
+				{
+					Name:   "allow-cluster",
+					Action: types.AdminNwPolicyActionAllow,
+					To:     []types.AdminNetworkPolicyPeer{{Namespaces: &types.AdminNetworkPolicySelector{}}},
+				},
+				{
+					Name:   "deny-world",
+					Action: types.AdminNwPolicyActionDeny,
+					To:     []types.AdminNetworkPolicyPeer{{Networks: worldNetworks}},
+				},
+			},
+		},
+	}, true
+}
+
+// ProposeAdminNetworkPolicies aggregates the namespaced knox policies into cluster wide guardrails,
+// the admin network policies of the rules common to minNamespaces namespaces at least and a baseline policy
+func ProposeAdminNetworkPolicies(policies []types.KnoxNetworkPolicy, minNamespaces int) []types.AdminNetworkPolicy {
+	// a rule is common to two namespaces at least
+	if minNamespaces < 2 {
+		minNamespaces = 2
+	}
+
+	aggregator := &adminPolicyAggregator{rules: map[string]*commonRule{}}
+	for _, policy := range policies {
+		aggregator.addPolicy(policy)
+	}
+
+	res := aggregator.adminPolicies(minNamespaces)
+	if baseline, ok := aggregator.baselinePolicy(minNamespaces); ok {
+		res = append(res, baseline)
+	}
+
+	return res
+}
@@ -0,0 +1,130 @@
+package plugin
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func dnsEgress() types.Egress {
+	return types.Egress{
+		MatchLabels: map[string]string{"k8s-app": "kube-dns", "k8s:io.kubernetes.pod.namespace": "kube-system"},
+		ToPorts:     []types.SpecPort{{Port: "53", Protocol: "UDP"}},
+	}
+}
+
+func TestProposeAdminNetworkPolicies(t *testing.T) {
+	policies := []types.KnoxNetworkPolicy{}
+	for _, namespace := range []string{"a", "b", "c"} {
+		policy := newKnoxPolicy(namespace, "policy-"+namespace)
+		policy.Spec.Egress = []types.Egress{
+			dnsEgress(),
+			// a peer in the own namespace of each policy is not common
+			{MatchLabels: map[string]string{"app": "db", "k8s:io.kubernetes.pod.namespace": namespace}},
+		}
+		policies = append(policies, policy)
+	}
+
+	// the world is only reached from c
+	policies[2].Spec.Egress = append(policies[2].Spec.Egress, types.Egress{ToEntities: []string{"world"}})
+
+	res := ProposeAdminNetworkPolicies(policies, 2)
+	assert.Len(t, res, 2)
+
+	anp := res[0]
+	assert.Equal(t, types.KindAdminNetworkPolicy, anp.Kind)
+	assert.Equal(t, "common-egress-kube-system-kube-dns-udp-53", anp.Metadata["name"])
+	assert.Equal(t, AdminPolicyPriorityBase, anp.Spec.Priority)
+	assert.Equal(t, []string{"a", "b", "c"}, anp.Spec.Subject.Namespaces.MatchExpressions[0].Values)
+
+	rule := anp.Spec.Egress[0]
+	assert.Equal(t, types.AdminNwPolicyActionAllow, rule.Action)
+	assert.Equal(t, map[string]string{K8sNamespaceNameLabel: "kube-system"}, rule.To[0].Pods.NamespaceSelector.MatchLabels)
+	assert.Equal(t, map[string]string{"k8s-app": "kube-dns"}, rule.To[0].Pods.PodSelector.MatchLabels)
+	assert.Equal(t, types.AdminNetworkPolicyPortNumber{Protocol: "UDP", Port: 53}, *rule.Ports[0].PortNumber)
+
+	// the namespaces which never reached the world are denied it
+	banp := res[1]
+	assert.Equal(t, types.KindBaselineAdminNetworkPolicy, banp.Kind)
+	assert.Equal(t, BaselineAdminNetworkPolicyName, banp.Metadata["name"])
+	assert.Equal(t, []string{"a", "b"}, banp.Spec.Subject.Namespaces.MatchExpressions[0].Values)
+	assert.Equal(t, types.AdminNwPolicyActionAllow, banp.Spec.Egress[0].Action)
+	assert.Equal(t, types.AdminNwPolicyActionDeny, banp.Spec.Egress[1].Action)
+	assert.Equal(t, []string{"0.0.0.0/0", "::/0"}, banp.Spec.Egress[1].To[0].Networks)
+}
+
+func TestProposeAdminNetworkPoliciesThreshold(t *testing.T) {
+	a := newKnoxPolicy("a", "policy-a")
+	a.Spec.Egress = []types.Egress{dnsEgress(), {ToEntities: []string{"world"}}}
+	a.Spec.Ingress = []types.Ingress{
+		{MatchLabels: map[string]string{"app": "prometheus", "k8s:io.kubernetes.pod.namespace": "monitoring"}},
+	}
+
+	b := newKnoxPolicy("b", "policy-b")
+	b.Spec.Egress = []types.Egress{{ToEntities: []string{"world"}}}
+	b.Spec.Ingress = a.Spec.Ingress
+
+	host := newKnoxPolicy("c", "policy-c")
+	host.Kind = types.KindKnoxHostNetworkPolicy
+	host.Spec.Egress = []types.Egress{dnsEgress()}
+
+	// dns is only discovered in a, the host policies are not aggregated
+	res := ProposeAdminNetworkPolicies([]types.KnoxNetworkPolicy{a, b, host}, 2)
+	assert.Len(t, res, 1)
+
+	assert.Equal(t, "common-ingress-monitoring-prometheus", res[0].Metadata["name"])
+	assert.Nil(t, res[0].Spec.Ingress[0].Ports)
+	assert.Equal(t, map[string]string{K8sNamespaceNameLabel: "monitoring"}, res[0].Spec.Ingress[0].From[0].Pods.NamespaceSelector.MatchLabels)
+
+	assert.Len(t, ProposeAdminNetworkPolicies([]types.KnoxNetworkPolicy{a, b}, 3), 0)
+}
+
+func TestProposeAdminNetworkPoliciesWorldEgress(t *testing.T) {
+	policies := []types.KnoxNetworkPolicy{}
+	egresses := []types.Egress{
+		{ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"api.vendor.com"}}}},
+		{ToServices: []types.SpecService{{ServiceName: "vendor-db", Namespace: "b"}}},
+		{ToEntities: []string{"kube-apiserver"}},
+		{ToEntities: []string{"cluster"}},
+		{},
+	}
+	for i, namespace := range []string{"a", "b", "c", "d", "e"} {
+		policy := newKnoxPolicy(namespace, "policy-"+namespace)
+		policy.Spec.Egress = []types.Egress{egresses[i]}
+		policies = append(policies, policy)
+	}
+
+	// the fqdns, the services and the entities out of the cluster reach the world
+	res := ProposeAdminNetworkPolicies(policies, 2)
+	assert.Len(t, res, 1)
+	assert.Equal(t, []string{"d", "e"}, res[0].Spec.Subject.Namespaces.MatchExpressions[0].Values)
+}
+
+func TestAdminPolicyPriorityMax(t *testing.T) {
+	policies := []types.KnoxNetworkPolicy{}
+	for _, namespace := range []string{"a", "b"} {
+		policy := newKnoxPolicy(namespace, "policy-"+namespace)
+		for port := 1; port <= AdminPolicyPriorityMax; port++ {
+			policy.Spec.Egress = append(policy.Spec.Egress, types.Egress{
+				ToCIDRs: []types.SpecCIDR{{CIDRs: []string{"10.0.0.1/32"}}},
+				ToPorts: []types.SpecPort{{Port: strconv.Itoa(port), Protocol: "TCP"}},
+			})
+		}
+		policies = append(policies, policy)
+	}
+
+	res := ProposeAdminNetworkPolicies(policies, 2)
+	assert.Len(t, res, AdminPolicyPriorityMax)
+	for _, policy := range res {
+		assert.LessOrEqual(t, policy.Spec.Priority, AdminPolicyPriorityMax)
+	}
+}
+
+func TestAdminPolicyName(t *testing.T) {
+	assert.Equal(t, "egress-10-0-0-0-8-tcp-443", adminPolicyName("egress", "10.0.0.0/8", "TCP-443"))
+
+	long := adminPolicyName("common", "egress", "a-very-long-namespace-name-for-the-test", "and-a-long-label-value")
+	assert.Len(t, long, 63)
+}
@@ -0,0 +1,369 @@
+package plugin
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+const (
+	// CalicoNamespaceNameLabel is the label calico sets with the name of each namespace
+	CalicoNamespaceNameLabel = "projectcalico.org/name"
+
+	calicoActionAllow = "Allow"
+)
+
+// =============================== //
+// == Calico Network Conversion == //
+// =============================== //
+
+// calicoConverter converts a knox network policy to a calico policy
+type calicoConverter struct {
+	ruleReporter
+}
+
+// calicoSelector builds the selector expression of the labels, all() if there is none
+func calicoSelector(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "all()"
+	}
+
+	exprs := []string{}
+	for k, v := range k8sLabels(labels) {
+		exprs = append(exprs, k+" == '"+v+"'")
+	}
+	sort.Strings(exprs)
+
+	return strings.Join(exprs, " && ")
+}
+
+// calicoPeer builds the peer of a label rule, with a namespace selector for a cross namespace peer
+func (c *calicoConverter) calicoPeer(matchLabels map[string]string) types.CalicoEntityRule {
+	labels := map[string]string{}
+	namespace := ""
+	for k, v := range matchLabels {
+		if libs.ContainsElement(knoxNamespaceLabels, k) {
+			namespace = v
+			continue
+		}
+		labels[k] = v
+	}
+
+	peer := types.CalicoEntityRule{Selector: calicoSelector(labels)}
+	if namespace != "" && namespace != c.policy.Metadata["namespace"] {
+		peer.NamespaceSelector = CalicoNamespaceNameLabel + " == '" + namespace + "'"
+	}
+
+	return peer
+}
+
+func calicoNets(cidrs []types.SpecCIDR) ([]string, []string) {
+	nets, notNets := []string{}, []string{}
+	for _, cidr := range cidrs {
+		nets = append(nets, cidr.CIDRs...)
+		notNets = append(notNets, cidr.Except...)
+	}
+	return nets, notNets
+}
+
+// calicoEntityPeer maps the cilium entities to a calico peer, ok is false if some entity has no equivalent
+func (c *calicoConverter) calicoEntityPeer(direction, rule string, entities []string) (types.CalicoEntityRule, bool) {
+	peer := types.CalicoEntityRule{}
+
+	for _, entity := range entities {
+		switch entity {
+		case "all":
+			// no constraint on the peer
+			return types.CalicoEntityRule{}, true
+		case "world":
+			peer.Nets = append(peer.Nets, worldNetworks...)
+		case "cluster":
+			peer.NamespaceSelector = "all()"
+		default:
+			c.report(direction, rule+"="+entity, "the entity "+entity+" has no calico equivalent")
+			return peer, false
+		}
+	}
+
+	return peer, true
+}
+
+// calicoPort converts a knox port to a calico port, a number, a range or a named port
+func calicoPort(port types.SpecPort) interface{} {
+	if portVal, err := strconv.Atoi(port.Port); err == nil {
+		// calico ranges are "start:end"
+		if port.EndPort > portVal {
+			return port.Port + ":" + strconv.Itoa(port.EndPort)
+		}
+		return portVal
+	}
+	return port.Port
+}
+
+// calicoWildcardSuffixes are the trailing wildcards of the aggregated http paths, matched by a calico prefix
+var calicoWildcardSuffixes = []string{"/.*", "/.+", "/.[^/]+", "/[^/]+", "/[0-9^/]+", "/[0-9]+"}
+
+// calicoPath converts a knox http path, a trailing wildcard becomes a prefix match,
+// ok is false if the path has a regex calico cannot express
+func calicoPath(path string) (types.CalicoHTTPPath, bool) {
+	res := types.CalicoHTTPPath{Exact: path}
+	for _, suffix := range calicoWildcardSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			path = strings.TrimSuffix(path, suffix) + "/"
+			res = types.CalicoHTTPPath{Prefix: path}
+			break
+		}
+	}
+
+	if strings.ContainsAny(path, `[]()*+?^$|\{}`) {
+		return types.CalicoHTTPPath{}, false
+	}
+	return res, true
+}
+
+// calicoHTTP converts the knox http rules, a match per method and path,
+// since a calico match allows each of its methods with each of its paths
+func (c *calicoConverter) calicoHTTP(direction string, https []types.SpecHTTP) []*types.CalicoHTTPMatch {
+	matches := []*types.CalicoHTTPMatch{}
+
+	for _, http := range https {
+		match := &types.CalicoHTTPMatch{}
+		if http.Method != "" {
+			match.Methods = []string{http.Method}
+		}
+
+		if http.Path != "" {
+			path, ok := calicoPath(http.Path)
+			if !ok {
+				c.report(direction, "toHTTPs="+http.Method+" "+http.Path, "the path regex has no calico exact or prefix match")
+				continue
+			}
+			match.Paths = []types.CalicoHTTPPath{path}
+		}
+
+		if !libs.ContainsElement(matches, match) {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches
+}
+
+// buildCalicoRules splits a knox rule into calico rules, calico rules have a single protocol
+func (c *calicoConverter) buildCalicoRules(direction string, peer types.CalicoEntityRule,
+	ports []types.SpecPort, icmps []types.SpecICMP, https []types.SpecHTTP) []types.CalicoRule {
+	rules := []types.CalicoRule{}
+
+	newRule := func(protocol string, peerPorts []interface{}) types.CalicoRule {
+		rule := types.CalicoRule{Action: calicoActionAllow, Protocol: protocol}
+
+		// the ports are always the ports of the destination
+		if direction == directionEgress {
+			rule.Destination = peer
+			rule.Destination.Ports = peerPorts
+		} else {
+			rule.Source = peer
+			rule.Destination.Ports = peerPorts
+		}
+		return rule
+	}
+
+	// group the ports per protocol, keeping the order of the protocols
+	protocols := []string{}
+	portsPerProtocol := map[string][]interface{}{}
+	for _, p := range ports {
+		protocol := strings.ToUpper(p.Protocol)
+		if protocol == "" {
+			protocol = "TCP"
+		}
+		if _, ok := portsPerProtocol[protocol]; !ok {
+			protocols = append(protocols, protocol)
+			portsPerProtocol[protocol] = []interface{}{}
+		}
+		// port 0 means all the ports of the protocol
+		if p.Port != "" && p.Port != "0" {
+			portsPerProtocol[protocol] = append(portsPerProtocol[protocol], calicoPort(p))
+		}
+	}
+
+	for _, protocol := range protocols {
+		if protocol == "ANY" {
+			for _, proto := range []string{"TCP", "UDP", "SCTP"} {
+				rules = append(rules, newRule(proto, portsPerProtocol[protocol]))
+			}
+			continue
+		}
+		rules = append(rules, newRule(protocol, portsPerProtocol[protocol]))
+	}
+
+	for _, icmp := range icmps {
+		protocol := "ICMP"
+		if strings.EqualFold(icmp.Family, "IPv6") {
+			protocol = "ICMPv6"
+		}
+		rule := newRule(protocol, nil)
+		rule.ICMP = &types.CalicoICMP{Type: int(icmp.Type)}
+		rules = append(rules, rule)
+	}
+
+	if len(rules) == 0 {
+		// no l4 constraint, all the traffic of the peer, http is only matched over tcp
+		protocol := ""
+		if len(https) > 0 {
+			protocol = "TCP"
+		}
+		rules = append(rules, newRule(protocol, nil))
+	}
+
+	for _, http := range https {
+		if http.Host != "" || len(http.Headers) > 0 {
+			c.report(direction, "toHTTPs="+http.Method+" "+http.Path, "calico matches the http methods and paths only, the host and headers are not enforced")
+		}
+	}
+
+	if len(https) > 0 {
+		// a tcp rule per http match, the tcp rules are dropped if none of the paths are expressible
+		matches := c.calicoHTTP(direction, https)
+		httpRules := []types.CalicoRule{}
+		for _, rule := range rules {
+			if rule.Protocol != "TCP" {
+				httpRules = append(httpRules, rule)
+				continue
+			}
+			for _, match := range matches {
+				httpRule := rule
+				httpRule.HTTP = match
+				httpRules = append(httpRules, httpRule)
+			}
+		}
+		rules = httpRules
+	}
+
+	return rules
+}
+
+func (c *calicoConverter) convertEgress(egress types.Egress) []types.CalicoRule {
+	var peer types.CalicoEntityRule
+
+	switch {
+	case egress.MatchLabels != nil:
+		peer = c.calicoPeer(egress.MatchLabels)
+	case len(egress.ToCIDRs) > 0:
+		peer.Nets, peer.NotNets = calicoNets(egress.ToCIDRs)
+	case len(egress.ToEntities) > 0:
+		var ok bool
+		if peer, ok = c.calicoEntityPeer(directionEgress, "toEntities", egress.ToEntities); !ok {
+			return nil
+		}
+	case len(egress.ToFQDNs) > 0:
+		for _, fqdn := range egress.ToFQDNs {
+			// calico domains match the wildcard patterns as well
+			peer.Domains = append(peer.Domains, fqdn.MatchNames...)
+			peer.Domains = append(peer.Domains, fqdn.MatchPatterns...)
+		}
+	case len(egress.ToServices) > 0:
+		// calico matches a single service per rule, and derives its ports from the service
+		rules := []types.CalicoRule{}
+		for _, svc := range egress.ToServices {
+			rules = append(rules, types.CalicoRule{
+				Action:      calicoActionAllow,
+				Destination: types.CalicoEntityRule{Services: &types.CalicoServiceMatch{Name: svc.ServiceName, Namespace: svc.Namespace}},
+			})
+		}
+		return rules
+	default:
+		c.report(directionEgress, "peer", "the rule has no peer")
+		return nil
+	}
+
+	if len(egress.ToKafkas) > 0 {
+		c.report(directionEgress, "toKafkas", "calico has no Kafka rules, only the ports are enforced")
+	}
+
+	return c.buildCalicoRules(directionEgress, peer, egress.ToPorts, egress.ICMPs, egress.ToHTTPs)
+}
+
+func (c *calicoConverter) convertIngress(ingress types.Ingress) []types.CalicoRule {
+	var peer types.CalicoEntityRule
+
+	switch {
+	case ingress.MatchLabels != nil:
+		peer = c.calicoPeer(ingress.MatchLabels)
+	case len(ingress.FromCIDRs) > 0:
+		peer.Nets, peer.NotNets = calicoNets(ingress.FromCIDRs)
+	case len(ingress.FromEntities) > 0:
+		var ok bool
+		if peer, ok = c.calicoEntityPeer(directionIngress, "fromEntities", ingress.FromEntities); !ok {
+			return nil
+		}
+	default:
+		c.report(directionIngress, "peer", "the rule has no peer")
+		return nil
+	}
+
+	if len(ingress.ToKafkas) > 0 {
+		c.report(directionIngress, "toKafkas", "calico has no Kafka rules, only the ports are enforced")
+	}
+
+	return c.buildCalicoRules(directionIngress, peer, ingress.ToPorts, ingress.ICMPs, ingress.ToHTTPs)
+}
+
+// convert returns the calico policy, ok is false if it has no rule
+func (c *calicoConverter) convert() (types.CalicoNetworkPolicy, bool) {
+	knp := c.policy
+
+	calicoPolicy := types.CalicoNetworkPolicy{
+		APIVersion: types.CalicoPolicyAPIVersion,
+		Kind:       types.CalicoNwPolicyKind,
+		Metadata:   map[string]string{"name": knp.Metadata["name"]},
+		Spec:       types.CalicoSpec{Selector: calicoSelector(knp.Spec.Selector.MatchLabels)},
+	}
+
+	// host policies select the host endpoints cluster wide
+	if knp.Kind == types.KindKnoxHostNetworkPolicy {
+		calicoPolicy.Kind = types.CalicoGlobalNwPolicyKind
+	} else {
+		calicoPolicy.Metadata["namespace"] = knp.Metadata["namespace"]
+	}
+
+	for _, eg := range knp.Spec.Egress {
+		calicoPolicy.Spec.Egress = append(calicoPolicy.Spec.Egress, c.convertEgress(eg)...)
+	}
+	for _, ing := range knp.Spec.Ingress {
+		calicoPolicy.Spec.Ingress = append(calicoPolicy.Spec.Ingress, c.convertIngress(ing)...)
+	}
+
+	if len(calicoPolicy.Spec.Ingress) > 0 {
+		calicoPolicy.Spec.Types = append(calicoPolicy.Spec.Types, "Ingress")
+	}
+	if len(calicoPolicy.Spec.Egress) > 0 {
+		calicoPolicy.Spec.Types = append(calicoPolicy.Spec.Types, "Egress")
+	}
+
+	return calicoPolicy, len(calicoPolicy.Spec.Types) > 0
+}
+
+// ConvertKnoxNetworkPolicyToCalicoPolicy converts a knox policy, and reports the rules calico cannot express
+func ConvertKnoxNetworkPolicyToCalicoPolicy(inPolicy types.KnoxNetworkPolicy) (types.CalicoNetworkPolicy, bool, []types.UnsupportedPolicyRule) {
+	converter := &calicoConverter{ruleReporter{policy: inPolicy}}
+	calicoPolicy, ok := converter.convert()
+	return calicoPolicy, ok, converter.unsupported
+}
+
+func ConvertKnoxPoliciesToCalicoPolicies(policies []types.KnoxNetworkPolicy) ([]types.CalicoNetworkPolicy, []types.UnsupportedPolicyRule) {
+	calicoPolicies := []types.CalicoNetworkPolicy{}
+	unsupported := []types.UnsupportedPolicyRule{}
+
+	for _, policy := range policies {
+		calicoPolicy, ok, rules := ConvertKnoxNetworkPolicyToCalicoPolicy(policy)
+		if ok {
+			calicoPolicies = append(calicoPolicies, calicoPolicy)
+		}
+		unsupported = append(unsupported, rules...)
+	}
+
+	return calicoPolicies, unsupported
+}
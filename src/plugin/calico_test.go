@@ -0,0 +1,122 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalicoSelector(t *testing.T) {
+	assert.Equal(t, "all()", calicoSelector(nil))
+	assert.Equal(t, "app == 'web' && tier == 'front'",
+		calicoSelector(map[string]string{"k8s:tier": "front", "app": "web"}))
+}
+
+func TestConvertCalicoPolicyPeers(t *testing.T) {
+	policy := newKnoxPolicy("default", "peers")
+	policy.Spec.Egress = []types.Egress{
+		{
+			MatchLabels: map[string]string{"app": "db", "k8s:io.kubernetes.pod.namespace": "storage"},
+			ToPorts:     []types.SpecPort{{Port: "3306", Protocol: "TCP"}, {Port: "9000", Protocol: "UDP"}},
+		},
+		{
+			ToCIDRs: []types.SpecCIDR{{CIDRs: []string{"10.0.0.0/8"}, Except: []string{"10.1.0.0/16"}}},
+		},
+		{
+			ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"example.com"}}},
+			ToPorts: []types.SpecPort{{Port: "443", Protocol: "TCP"}},
+		},
+	}
+	policy.Spec.Ingress = []types.Ingress{
+		{MatchLabels: map[string]string{"app": "client", "k8s:io.kubernetes.pod.namespace": "default"}},
+	}
+
+	res, ok, unsupported := ConvertKnoxNetworkPolicyToCalicoPolicy(policy)
+	assert.True(t, ok)
+	assert.Len(t, unsupported, 0)
+
+	assert.Equal(t, types.CalicoNwPolicyKind, res.Kind)
+	assert.Equal(t, "default", res.Metadata["namespace"])
+	assert.Equal(t, "app == 'web'", res.Spec.Selector)
+	assert.Equal(t, []string{"Ingress", "Egress"}, res.Spec.Types)
+
+	// a calico rule has a single protocol
+	egress := res.Spec.Egress
+	assert.Len(t, egress, 4)
+	assert.Equal(t, "TCP", egress[0].Protocol)
+	assert.Equal(t, "UDP", egress[1].Protocol)
+	assert.Equal(t, []interface{}{9000}, egress[1].Destination.Ports)
+	assert.Equal(t, "app == 'db'", egress[0].Destination.Selector)
+	assert.Equal(t, CalicoNamespaceNameLabel+" == 'storage'", egress[0].Destination.NamespaceSelector)
+
+	assert.Equal(t, []string{"10.0.0.0/8"}, egress[2].Destination.Nets)
+	assert.Equal(t, []string{"10.1.0.0/16"}, egress[2].Destination.NotNets)
+	assert.Equal(t, "", egress[2].Protocol)
+
+	assert.Equal(t, []string{"example.com"}, egress[3].Destination.Domains)
+
+	// same namespace peer
+	assert.Equal(t, "app == 'client'", res.Spec.Ingress[0].Source.Selector)
+	assert.Equal(t, "", res.Spec.Ingress[0].Source.NamespaceSelector)
+}
+
+func TestConvertCalicoPolicyHTTPAndICMP(t *testing.T) {
+	policy := newKnoxPolicy("default", "l7")
+	policy.Spec.Ingress = []types.Ingress{
+		{
+			MatchLabels: map[string]string{"app": "client"},
+			ToPorts:     []types.SpecPort{{Port: "8080", Protocol: "TCP"}},
+			ToHTTPs: []types.SpecHTTP{{Method: "GET", Path: "/health"}, {Method: "POST", Path: "/api/.*"},
+				{Method: "GET", Path: "/users/[0-9]+"}, {Method: "GET", Path: "/users/[0-9]+/orders"}},
+		},
+		{
+			MatchLabels: map[string]string{"app": "probe"},
+			ICMPs:       []types.SpecICMP{{Family: "IPv4", Type: 8}},
+		},
+	}
+
+	res, ok, unsupported := ConvertKnoxNetworkPolicyToCalicoPolicy(policy)
+	assert.True(t, ok)
+
+	// a rule per method and path, so that the methods and paths are not crossed
+	ingress := res.Spec.Ingress
+	assert.Len(t, ingress, 4)
+	assert.Equal(t, &types.CalicoHTTPMatch{Methods: []string{"GET"}, Paths: []types.CalicoHTTPPath{{Exact: "/health"}}}, ingress[0].HTTP)
+	assert.Equal(t, &types.CalicoHTTPMatch{Methods: []string{"POST"}, Paths: []types.CalicoHTTPPath{{Prefix: "/api/"}}}, ingress[1].HTTP)
+	assert.Equal(t, &types.CalicoHTTPMatch{Methods: []string{"GET"}, Paths: []types.CalicoHTTPPath{{Prefix: "/users/"}}}, ingress[2].HTTP)
+
+	// a regex segment in the middle of the path has no calico match
+	assert.Len(t, unsupported, 1)
+	assert.Equal(t, "toHTTPs=GET /users/[0-9]+/orders", unsupported[0].Rule)
+
+	assert.Equal(t, "ICMP", ingress[3].Protocol)
+	assert.Equal(t, 8, ingress[3].ICMP.Type)
+}
+
+func TestConvertCalicoPolicyGlobalAndUnsupported(t *testing.T) {
+	hostPolicy := newKnoxPolicy("default", "host")
+	hostPolicy.Kind = types.KindKnoxHostNetworkPolicy
+	hostPolicy.Spec.Egress = []types.Egress{
+		{ToEntities: []string{"world"}},
+		{ToEntities: []string{"kube-apiserver"}},
+	}
+
+	unsupportedPolicy := newKnoxPolicy("default", "unsupported")
+	unsupportedPolicy.Spec.Ingress = []types.Ingress{{FromEntities: []string{"remote-node"}}}
+
+	res, unsupported := ConvertKnoxPoliciesToCalicoPolicies([]types.KnoxNetworkPolicy{hostPolicy, unsupportedPolicy})
+
+	// the policy without any expressible rule is dropped
+	assert.Len(t, res, 1)
+	assert.Equal(t, types.CalicoGlobalNwPolicyKind, res[0].Kind)
+	assert.NotContains(t, res[0].Metadata, "namespace")
+	assert.Equal(t, []string{"Egress"}, res[0].Spec.Types)
+	assert.Equal(t, []string{"0.0.0.0/0", "::/0"}, res[0].Spec.Egress[0].Destination.Nets)
+
+	rules := []string{}
+	for _, rule := range unsupported {
+		rules = append(rules, rule.Rule)
+	}
+	assert.Equal(t, []string{"toEntities=kube-apiserver", "fromEntities=remote-node"}, rules)
+}
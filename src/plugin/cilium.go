@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,6 +14,7 @@ import (
 	"github.com/accuknox/auto-policy-discovery/src/config"
 	"github.com/accuknox/auto-policy-discovery/src/libs"
 	logger "github.com/accuknox/auto-policy-discovery/src/logging"
+	"github.com/accuknox/auto-policy-discovery/src/metrics"
 	obs "github.com/accuknox/auto-policy-discovery/src/observability"
 	"github.com/accuknox/auto-policy-discovery/src/types"
 	"github.com/rs/zerolog"
@@ -143,15 +145,81 @@ func getReservedLabelsIfExist(labels []string) []string {
 func getHTTP(flow *cilium.Flow) (string, string) {
 	if flow.L7 != nil && flow.L7.GetHttp() != nil {
 		if flow.L7.GetType() == 1 { // REQUEST only
-			method := flow.L7.GetHttp().GetMethod()
-			u, _ := url.Parse(flow.L7.GetHttp().GetUrl())
-			path := u.Path
+			method, path, _ := getHTTPRequestURL(flow.L7.GetHttp())
+			return method, path
+		}
+	}
+
+	return "", ""
+}
+
+// getHTTPRequestURL returns the method, path and host of the request url
+func getHTTPRequestURL(http *cilium.HTTP) (string, string, string) {
+	u, err := url.Parse(http.GetUrl())
+	if err != nil {
+		return http.GetMethod(), "", ""
+	}
+
+	path := u.Path
+	if strings.HasPrefix(path, "//") {
+		path = strings.Replace(path, "//", "/", 1)
+	}
+
+	return http.GetMethod(), path, u.Host
+}
 
-			if strings.HasPrefix(path, "//") {
-				path = strings.Replace(path, "//", "/", 1)
+// getHTTPHost returns the host of a request, if the http rules match the hosts
+func getHTTPHost(flow *cilium.Flow) string {
+	if !config.GetCfgHTTPRuleHost() {
+		return ""
+	}
+	_, _, host := getHTTPRequestURL(flow.L7.GetHttp())
+	return host
+}
+
+// getHTTPHeaders returns the configured headers of a request, as "name: value"
+func getHTTPHeaders(flow *cilium.Flow) []string {
+	names := config.GetCfgHTTPRuleHeaders()
+	if len(names) == 0 {
+		return nil
+	}
+
+	headers := []string{}
+	for _, header := range flow.L7.GetHttp().GetHeaders() {
+		for _, name := range names {
+			if strings.EqualFold(header.GetKey(), name) {
+				headers = append(headers, name+": "+header.GetValue())
 			}
+		}
+	}
+	if len(headers) == 0 {
+		return nil
+	}
 
-			return method, path
+	sort.Strings(headers)
+	return headers
+}
+
+// getHTTPErrorResponse returns the method, path and code of a 4xx/5xx response, if the requests answered so are skipped
+func getHTTPErrorResponse(flow *cilium.Flow) (string, string, int) {
+	if flow.L7 == nil || flow.L7.GetHttp() == nil || flow.L7.GetType() != 2 || !config.GetCfgHTTPSkipErrors() {
+		return "", "", 0
+	}
+
+	code := int(flow.L7.GetHttp().GetCode())
+	if code < 400 {
+		return "", "", 0
+	}
+
+	method, path, _ := getHTTPRequestURL(flow.L7.GetHttp())
+	return method, path, code
+}
+
+// getKafka returns the api key and the topic of a kafka request
+func getKafka(flow *cilium.Flow) (string, string) {
+	if flow.L7 != nil && flow.L7.GetKafka() != nil {
+		if flow.L7.GetType() == 1 { // REQUEST only
+			return strings.ToLower(flow.L7.GetKafka().GetApiKey()), flow.L7.GetKafka().GetTopic()
 		}
 	}
 
@@ -199,6 +267,7 @@ func ConvertCiliumFlowToKnoxNetworkLog(ciliumFlow *cilium.Flow) (types.KnoxNetwo
 	if ciliumFlow.IP != nil {
 		log.SrcIP = ciliumFlow.IP.Source
 		log.DstIP = ciliumFlow.IP.Destination
+		log.EtherType = libs.GetEtherType(log.SrcIP)
 	} else {
 		return log, false
 	}
@@ -212,7 +281,7 @@ func ConvertCiliumFlowToKnoxNetworkLog(ciliumFlow *cilium.Flow) (types.KnoxNetwo
 			// Sometimes, ICMP flow for certain `type` (like EchoReply)
 			// does not have the `IsReply` flag set in the Cilium Flow.
 			// So we cannot fully rely on `IsReply` flag in case of ICMP flows.
-			if libs.IsReplyICMP(log.ICMPType) {
+			if libs.IsReplyICMP(log.Protocol, log.ICMPType) {
 				log.IsReply = true
 			}
 		} else { // tcp & udp
@@ -229,12 +298,27 @@ func ConvertCiliumFlowToKnoxNetworkLog(ciliumFlow *cilium.Flow) (types.KnoxNetwo
 	// get L7 HTTP
 	if ciliumFlow.GetL7() != nil && ciliumFlow.L7.GetHttp() != nil {
 		log.HTTPMethod, log.HTTPPath = getHTTP(ciliumFlow)
-		if log.HTTPMethod == "" && log.HTTPPath == "" {
+		if log.HTTPMethod != "" || log.HTTPPath != "" {
+			log.HTTPHost = getHTTPHost(ciliumFlow)
+			log.HTTPHeaders = getHTTPHeaders(ciliumFlow)
+		} else if log.HTTPMethod, log.HTTPPath, log.HTTPCode = getHTTPErrorResponse(ciliumFlow); log.HTTPCode != 0 {
+			// the error responses skip their requests, they are not discovered themselves
+			log.IsReply = true
+		} else {
 			return log, false
 		}
 		log.L7Protocol = libs.L7ProtocolHTTP
 	}
 
+	// get L7 Kafka
+	if ciliumFlow.GetL7() != nil && ciliumFlow.L7.GetKafka() != nil {
+		log.KafkaAPIKey, log.KafkaTopic = getKafka(ciliumFlow)
+		if log.KafkaAPIKey == "" {
+			return log, false
+		}
+		log.L7Protocol = libs.L7ProtocolKafka
+	}
+
 	// get L7 DNS
 	if ciliumFlow.GetL7() != nil && ciliumFlow.L7.GetDns() != nil {
 		// if DSN response includes IPs
@@ -484,8 +568,116 @@ func GetFlowData(netLogEventType []byte, flowEventType interface{}) error {
 // == Network Policy Convertor == //
 // ============================== //
 
-// TODO: search core-dns? or statically return dns pod
-func getCoreDNSEndpoint(services []types.Service) ([]types.CiliumEndpoint, []types.CiliumPortList) {
+// dnsClusterPatterns are the names of the cluster domain, the workloads resolve the services by them
+func dnsClusterPatterns(domain string) []string {
+	return []string{"*." + domain, "*.svc." + domain, "*.*.svc." + domain}
+}
+
+// dnsSearchNames are the expansions of a queried name by the search paths of the pods, <namespace>.svc.<domain>,
+// svc.<domain> and <domain>, the resolver queries them first and stops at a refused one
+func dnsSearchNames(name, domain string) []string {
+	return []string{name + ".*.svc." + domain, name + ".svc." + domain, name + "." + domain}
+}
+
+// isCoreDNSEndpoint checks if the labels select the kube-dns pods
+func isCoreDNSEndpoint(matchLabels map[string]string) bool {
+	return matchLabels["k8s-app"] == "kube-dns" || matchLabels["k8s:k8s-app"] == "kube-dns"
+}
+
+// ciliumDNSRules builds the dns rules of the names and patterns the workload queried,
+// nil if the policy has no fqdn rule
+func ciliumDNSRules(egresses []types.Egress) []types.SubRule {
+	names := []string{}
+	patterns := []string{}
+
+	for _, egress := range egresses {
+		for _, fqdn := range egress.ToFQDNs {
+			for _, name := range fqdn.MatchNames {
+				if !libs.ContainsElement(names, name) {
+					names = append(names, name)
+				}
+			}
+			for _, pattern := range fqdn.MatchPatterns {
+				if !libs.ContainsElement(patterns, pattern) {
+					patterns = append(patterns, pattern)
+				}
+			}
+		}
+	}
+
+	if len(names) == 0 && len(patterns) == 0 {
+		return nil
+	}
+
+	sort.Strings(names)
+	sort.Strings(patterns)
+
+	domain := config.GetCfgClusterDomain()
+
+	rules := []types.SubRule{}
+	for _, name := range names {
+		rules = append(rules, types.SubRule{"matchName": name})
+	}
+	for _, pattern := range patterns {
+		rules = append(rules, types.SubRule{"matchPattern": pattern})
+	}
+	for _, query := range append(names, patterns...) {
+		for _, search := range dnsSearchNames(query, domain) {
+			if strings.Contains(search, "*") {
+				rules = append(rules, types.SubRule{"matchPattern": search})
+			} else {
+				rules = append(rules, types.SubRule{"matchName": search})
+			}
+		}
+	}
+	for _, pattern := range dnsClusterPatterns(domain) {
+		rules = append(rules, types.SubRule{"matchPattern": pattern})
+	}
+	return rules
+}
+
+// addCiliumDNSRules restricts the dns port of the egress rules toward kube-dns to the dns rules,
+// or allows kube-dns by the dns rules if the policy has no such rule
+func addCiliumDNSRules(ciliumPolicy *types.CiliumNetworkPolicy, dnsRules []types.SubRule) {
+	added := false
+
+	for i, egress := range ciliumPolicy.Spec.Egress {
+		if len(egress.ToEndpoints) == 0 || !isCoreDNSEndpoint(egress.ToEndpoints[0].MatchLabels) {
+			continue
+		}
+
+		// the other ports of kube-dns, such as the metrics, are not parsed as dns
+		toPorts := []types.CiliumPortList{}
+		for _, portList := range egress.ToPorts {
+			dnsPorts, otherPorts := []types.CiliumPort{}, []types.CiliumPort{}
+			for _, port := range portList.Ports {
+				if port.Port == "53" && port.EndPort == 0 {
+					dnsPorts = append(dnsPorts, port)
+				} else {
+					otherPorts = append(otherPorts, port)
+				}
+			}
+
+			if len(dnsPorts) > 0 {
+				toPorts = append(toPorts, types.CiliumPortList{Ports: dnsPorts, Rules: map[string][]types.SubRule{"dns": dnsRules}})
+				added = true
+			}
+			if len(otherPorts) > 0 {
+				toPorts = append(toPorts, types.CiliumPortList{Ports: otherPorts, Rules: portList.Rules})
+			}
+		}
+		ciliumPolicy.Spec.Egress[i].ToPorts = toPorts
+	}
+
+	if !added {
+		coreDNS, toPorts := getCoreDNSEndpoint(nil, dnsRules)
+		ciliumPolicy.Spec.Egress = append(ciliumPolicy.Spec.Egress, types.CiliumEgress{ToEndpoints: coreDNS, ToPorts: toPorts})
+	}
+}
+
+// getCoreDNSEndpoint allows the queries of the dns rules toward kube-dns,
+// its ports are the ports of the kube-dns service, or 53/udp and 53/tcp if the services are not given
+func getCoreDNSEndpoint(services []types.Service, dnsRules []types.SubRule) ([]types.CiliumEndpoint, []types.CiliumPortList) {
 	matchLabel := map[string]string{
 		"k8s:io.kubernetes.pod.namespace": "kube-system",
 		"k8s-app":                         "kube-dns",
@@ -497,8 +689,10 @@ func getCoreDNSEndpoint(services []types.Service) ([]types.CiliumEndpoint, []typ
 	ciliumPort.Ports = []types.CiliumPort{}
 
 	if len(services) == 0 { // add statically
-		ciliumPort.Ports = append(ciliumPort.Ports, types.CiliumPort{
-			Port: strconv.Itoa(53), Protocol: strings.ToUpper("UDP")},
+		// the queries are retried over tcp once a response is truncated
+		ciliumPort.Ports = append(ciliumPort.Ports,
+			types.CiliumPort{Port: strconv.Itoa(53), Protocol: strings.ToUpper("UDP")},
+			types.CiliumPort{Port: strconv.Itoa(53), Protocol: strings.ToUpper("TCP")},
 		)
 	} else { // search DNS
 		for _, svc := range services {
@@ -512,8 +706,6 @@ func getCoreDNSEndpoint(services []types.Service) ([]types.CiliumEndpoint, []typ
 
 	toPorts := []types.CiliumPortList{ciliumPort}
 
-	// matchPattern
-	dnsRules := []types.SubRule{map[string]string{"matchPattern": "*"}}
 	toPorts[0].Rules = map[string][]types.SubRule{"dns": dnsRules}
 
 	return coreDNS, toPorts
@@ -541,6 +733,41 @@ func buildNewCiliumNetworkPolicy(inPolicy types.KnoxNetworkPolicy) types.CiliumN
 	return ciliumPolicy
 }
 
+// ciliumL7Rules builds the http and kafka rules of the ports, a port has the rules of a single l7 protocol
+func ciliumL7Rules(https []types.SpecHTTP, kafkas []types.SpecKafka) map[string][]types.SubRule {
+	if len(https) > 0 {
+		httpRules := []types.SubRule{}
+		for _, http := range https {
+			// matchPattern
+			rule := types.SubRule{"method": http.Method, "path": http.Path}
+			if http.Host != "" {
+				rule["host"] = http.Host
+			}
+			if len(http.Headers) > 0 {
+				rule["headers"] = http.Headers
+			}
+			httpRules = append(httpRules, rule)
+		}
+		return map[string][]types.SubRule{"http": httpRules}
+	}
+
+	if len(kafkas) > 0 {
+		kafkaRules := []types.SubRule{}
+		for _, kafka := range kafkas {
+			rule := types.SubRule{}
+			for k, v := range map[string]string{"role": kafka.Role, "apiKey": kafka.APIKey, "topic": kafka.Topic, "clientID": kafka.ClientID} {
+				if v != "" {
+					rule[k] = v
+				}
+			}
+			kafkaRules = append(kafkaRules, rule)
+		}
+		return map[string][]types.SubRule{"kafka": kafkaRules}
+	}
+
+	return nil
+}
+
 func ConvertKnoxNetworkPolicyToCiliumPolicy(inPolicy types.KnoxNetworkPolicy) types.CiliumNetworkPolicy {
 	ciliumPolicy := buildNewCiliumNetworkPolicy(inPolicy)
 
@@ -590,6 +817,9 @@ func ConvertKnoxNetworkPolicyToCiliumPolicy(inPolicy types.KnoxNetworkPolicy) ty
 					for _, matchName := range fqdn.MatchNames {
 						ciliumEgress.ToFQDNs = append(ciliumEgress.ToFQDNs, map[string]string{"matchName": matchName})
 					}
+					for _, matchPattern := range fqdn.MatchPatterns {
+						ciliumEgress.ToFQDNs = append(ciliumEgress.ToFQDNs, map[string]string{"matchPattern": matchPattern})
+					}
 				}
 			} else if len(knoxEgress.ToServices) > 0 {
 				// ================== //
@@ -623,22 +853,14 @@ func ConvertKnoxNetworkPolicyToCiliumPolicy(inPolicy types.KnoxNetworkPolicy) ty
 					ciliumEgress.ToPorts = []types.CiliumPortList{{Ports: []types.CiliumPort{}}}
 				}
 
-				// =============== //
-				// build HTTP rule //
-				// =============== //
-				if len(knoxEgress.ToHTTPs) > 0 {
-					ciliumEgress.ToPorts[0].Rules = map[string][]types.SubRule{}
-
-					httpRules := []types.SubRule{}
-					for _, http := range knoxEgress.ToHTTPs {
-						// matchPattern
-						httpRules = append(httpRules, map[string]string{"method": http.Method,
-							"path": http.Path})
-					}
-					ciliumEgress.ToPorts[0].Rules = map[string][]types.SubRule{"http": httpRules}
+				// ========================= //
+				// build HTTP and Kafka rule //
+				// ========================= //
+				if rules := ciliumL7Rules(knoxEgress.ToHTTPs, knoxEgress.ToKafkas); rules != nil {
+					ciliumEgress.ToPorts[0].Rules = rules
 				}
 
-				port := types.CiliumPort{Port: toPort.Port, Protocol: strings.ToUpper(toPort.Protocol)}
+				port := types.CiliumPort{Port: toPort.Port, EndPort: toPort.EndPort, Protocol: strings.ToUpper(toPort.Protocol)}
 				ciliumEgress.ToPorts[0].Ports = append(ciliumEgress.ToPorts[0].Ports, port)
 			}
 
@@ -658,6 +880,13 @@ func ConvertKnoxNetworkPolicyToCiliumPolicy(inPolicy types.KnoxNetworkPolicy) ty
 
 			ciliumPolicy.Spec.Egress = append(ciliumPolicy.Spec.Egress, ciliumEgress)
 		}
+
+		// ============== //
+		// build DNS rule //
+		// ============== //
+		if dnsRules := ciliumDNSRules(inPolicy.Spec.Egress); dnsRules != nil {
+			addCiliumDNSRules(&ciliumPolicy, dnsRules)
+		}
 	}
 
 	// ======= //
@@ -701,22 +930,14 @@ func ConvertKnoxNetworkPolicyToCiliumPolicy(inPolicy types.KnoxNetworkPolicy) ty
 					ciliumIngress.ToPorts = []types.CiliumPortList{{Ports: []types.CiliumPort{}}}
 				}
 
-				// =============== //
-				// build HTTP rule //
-				// =============== //
-				if len(knoxIngress.ToHTTPs) > 0 {
-					ciliumIngress.ToPorts[0].Rules = map[string][]types.SubRule{}
-
-					httpRules := []types.SubRule{}
-					for _, http := range knoxIngress.ToHTTPs {
-						// matchPattern
-						httpRules = append(httpRules, map[string]string{"method": http.Method,
-							"path": http.Path})
-					}
-					ciliumIngress.ToPorts[0].Rules = map[string][]types.SubRule{"http": httpRules}
+				// ========================= //
+				// build HTTP and Kafka rule //
+				// ========================= //
+				if rules := ciliumL7Rules(knoxIngress.ToHTTPs, knoxIngress.ToKafkas); rules != nil {
+					ciliumIngress.ToPorts[0].Rules = rules
 				}
 
-				port := types.CiliumPort{Port: toPort.Port, Protocol: strings.ToUpper(toPort.Protocol)}
+				port := types.CiliumPort{Port: toPort.Port, EndPort: toPort.EndPort, Protocol: strings.ToUpper(toPort.Protocol)}
 				ciliumIngress.ToPorts[0].Ports = append(ciliumIngress.ToPorts[0].Ports, port)
 			}
 
@@ -788,6 +1009,7 @@ func GetCiliumFlowsFromHubble(trigger int) []*cilium.Flow {
 
 	results = CiliumFlows          // copy
 	CiliumFlows = []*cilium.Flow{} // reset
+	metrics.SetBufferedLogs(metrics.BufferCiliumFlows, 0)
 	CiliumFlowsMutex.Unlock()
 
 	fisrtDoc := results[0]
@@ -838,7 +1060,7 @@ func StartHubbleRelay(StopChan chan struct{}, cfg types.ConfigCiliumHubble) {
 				Reply:    []bool{false},
 			},
 			{
-				Protocol: []string{"icmp", "http", "dns"},
+				Protocol: []string{"icmp", "http", "dns", "kafka"},
 			},
 		},
 		Blacklist: []*cilium.FlowFilter{
@@ -873,6 +1095,7 @@ func StartHubbleRelay(StopChan chan struct{}, cfg types.ConfigCiliumHubble) {
 
 				CiliumFlowsMutex.Lock()
 				CiliumFlows = append(CiliumFlows, flow)
+				metrics.SetBufferedLogs(metrics.BufferCiliumFlows, len(CiliumFlows))
 				CiliumFlowsMutex.Unlock()
 
 				if config.GetCfgObservabilityEnable() {
@@ -900,6 +1123,7 @@ func GetCiliumFlowsFromFeedConsumer(trigger int) []*types.KnoxNetworkLog {
 
 	results = CiliumFlowsFC                   // copy
 	CiliumFlowsFC = []*types.KnoxNetworkLog{} // reset
+	metrics.SetBufferedLogs(metrics.BufferCiliumFlowsFC, 0)
 
 	log.Info().Msgf("The total number of cilium feed-consumer traffic flow: [%d]", len(results))
 
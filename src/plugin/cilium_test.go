@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
 	"github.com/accuknox/auto-policy-discovery/src/types"
 	flow "github.com/cilium/cilium/api/v1/flow"
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestConvertCiliumFlowToKnoxLog(t *testing.T) {
@@ -78,10 +81,11 @@ func TestConvertCiliumFlowToKnoxLog(t *testing.T) {
 			"src_port": 6379,
 			"dst_port": 60416,
 			"direction": "INGRESS",
-			"action": "allow"
+			"action": "allow",
+			"ether_type": 2048
 		}
 	*/
-	logBytes := []byte("{\"src_namespace\":\"default\",\"src_pod_name\":\"redis-cart-74594bd569-gw2xb\",\"dst_reserved_labels\":[\"reserved:host\"],\"protocol\":6,\"src_ip\":\"10.0.1.31\",\"dst_ip\":\"10.0.1.144\",\"src_port\":6379,\"dst_port\":60416,\"direction\":\"INGRESS\",\"action\":\"allow\"}")
+	logBytes := []byte("{\"src_namespace\":\"default\",\"src_pod_name\":\"redis-cart-74594bd569-gw2xb\",\"dst_reserved_labels\":[\"reserved:host\"],\"protocol\":6,\"src_ip\":\"10.0.1.31\",\"dst_ip\":\"10.0.1.144\",\"src_port\":6379,\"dst_port\":60416,\"direction\":\"INGRESS\",\"action\":\"allow\",\"ether_type\":2048}")
 	flow := &flow.Flow{}
 	json.Unmarshal(flowBytes, flow)
 
@@ -182,3 +186,186 @@ func TestConvertKnoxPolicyToCiliumPolicy(t *testing.T) {
 		t.Errorf("they should be equal %v %v", expected, actual)
 	}
 }
+
+func TestConvertCiliumKafkaFlowToKnoxLog(t *testing.T) {
+	kafkaFlow := &flow.Flow{
+		Verdict: flow.Verdict_FORWARDED,
+		IP:      &flow.IP{Source: "10.0.1.31", Destination: "10.0.1.144"},
+		L4:      &flow.Layer4{Protocol: &flow.Layer4_TCP{TCP: &flow.TCP{SourcePort: 40312, DestinationPort: 9092}}},
+		L7: &flow.Layer7{
+			Type:   flow.L7FlowType_REQUEST,
+			Record: &flow.Layer7_Kafka{Kafka: &flow.Kafka{ApiKey: "Produce", ApiVersion: 5, Topic: "orders"}},
+		},
+		Source:           &flow.Endpoint{Namespace: "default", PodName: "producer"},
+		Destination:      &flow.Endpoint{Namespace: "default", PodName: "kafka-0"},
+		TrafficDirection: flow.TrafficDirection_EGRESS,
+	}
+
+	actual, ok := ConvertCiliumFlowToKnoxNetworkLog(kafkaFlow)
+	assert.True(t, ok)
+	assert.Equal(t, libs.L7ProtocolKafka, actual.L7Protocol)
+	assert.Equal(t, "produce", actual.KafkaAPIKey)
+	assert.Equal(t, "orders", actual.KafkaTopic)
+}
+
+func TestConvertCiliumHTTPFlowToKnoxLog(t *testing.T) {
+	config.CurrentCfg.ConfigNetPolicy.HTTPRuleHost = true
+	config.CurrentCfg.ConfigNetPolicy.HTTPRuleHeaders = []string{"X-Tenant"}
+	config.CurrentCfg.ConfigNetPolicy.HTTPSkipErrors = true
+	t.Cleanup(func() {
+		config.CurrentCfg.ConfigNetPolicy.HTTPRuleHost = false
+		config.CurrentCfg.ConfigNetPolicy.HTTPRuleHeaders = nil
+		config.CurrentCfg.ConfigNetPolicy.HTTPSkipErrors = false
+	})
+
+	httpFlow := &flow.Flow{
+		Verdict: flow.Verdict_FORWARDED,
+		IP:      &flow.IP{Source: "10.0.1.31", Destination: "10.0.1.144"},
+		L4:      &flow.Layer4{Protocol: &flow.Layer4_TCP{TCP: &flow.TCP{SourcePort: 40312, DestinationPort: 8080}}},
+		L7: &flow.Layer7{
+			Type: flow.L7FlowType_REQUEST,
+			Record: &flow.Layer7_Http{Http: &flow.HTTP{
+				Method: "GET",
+				Url:    "http://api.default:8080/users",
+				Headers: []*flow.HTTPHeader{
+					{Key: "x-tenant", Value: "blue"},
+					{Key: "Authorization", Value: "Bearer secret"},
+				},
+			}},
+		},
+		Source:           &flow.Endpoint{Namespace: "default", PodName: "client"},
+		Destination:      &flow.Endpoint{Namespace: "default", PodName: "api"},
+		TrafficDirection: flow.TrafficDirection_EGRESS,
+	}
+
+	actual, ok := ConvertCiliumFlowToKnoxNetworkLog(httpFlow)
+	assert.True(t, ok)
+	assert.Equal(t, "/users", actual.HTTPPath)
+	assert.Equal(t, "api.default:8080", actual.HTTPHost)
+	// the headers out of the allowlist are not recorded
+	assert.Equal(t, []string{"X-Tenant: blue"}, actual.HTTPHeaders)
+
+	// an error response is kept to skip its request
+	httpFlow.L7.Type = flow.L7FlowType_RESPONSE
+	httpFlow.L7.GetHttp().Code = 503
+	actual, ok = ConvertCiliumFlowToKnoxNetworkLog(httpFlow)
+	assert.True(t, ok)
+	assert.True(t, actual.IsReply)
+	assert.Equal(t, 503, actual.HTTPCode)
+
+	httpFlow.L7.GetHttp().Code = 200
+	_, ok = ConvertCiliumFlowToKnoxNetworkLog(httpFlow)
+	assert.False(t, ok)
+}
+
+func TestCiliumL7RulesHTTP(t *testing.T) {
+	rules := ciliumL7Rules([]types.SpecHTTP{
+		{Method: "GET", Path: "/users"},
+		{Method: "POST", Path: "/orders", Host: "api.default:8080", Headers: []string{"X-Tenant: blue"}},
+	}, nil)
+	assert.Equal(t, map[string][]types.SubRule{
+		"http": {
+			{"method": "GET", "path": "/users"},
+			{"method": "POST", "path": "/orders", "host": "api.default:8080", "headers": []string{"X-Tenant: blue"}},
+		},
+	}, rules)
+}
+
+func TestCiliumL7RulesKafka(t *testing.T) {
+	rules := ciliumL7Rules(nil, []types.SpecKafka{{Role: "produce", Topic: "orders"}, {APIKey: "apiversions"}})
+	assert.Equal(t, map[string][]types.SubRule{
+		"kafka": {{"role": "produce", "topic": "orders"}, {"apiKey": "apiversions"}},
+	}, rules)
+
+	assert.Nil(t, ciliumL7Rules(nil, nil))
+}
+
+func TestConvertKnoxFQDNPatternsToCiliumPolicy(t *testing.T) {
+	knoxPolicy := types.KnoxNetworkPolicy{Metadata: map[string]string{"name": "fqdn", "namespace": "default"}}
+	knoxPolicy.Spec.Selector.MatchLabels = map[string]string{"app": "web"}
+	knoxPolicy.Spec.Egress = []types.Egress{
+		{
+			ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"vendor.com"}, MatchPatterns: []string{"*.vendor.com"}}},
+			ToPorts: []types.SpecPort{{Port: "443", Protocol: "tcp"}},
+		},
+	}
+
+	actual := ConvertKnoxNetworkPolicyToCiliumPolicy(knoxPolicy)
+	assert.Equal(t, []types.CiliumFQDN{{"matchName": "vendor.com"}, {"matchPattern": "*.vendor.com"}}, actual.Spec.Egress[0].ToFQDNs)
+}
+
+func TestConvertKnoxFQDNPolicyDNSRules(t *testing.T) {
+	dns := []types.SpecPort{{Port: "53", Protocol: "udp"}}
+	knoxPolicy := types.KnoxNetworkPolicy{Metadata: map[string]string{"name": "fqdn", "namespace": "default"}}
+	knoxPolicy.Spec.Selector.MatchLabels = map[string]string{"app": "web"}
+	knoxPolicy.Spec.Egress = []types.Egress{
+		{MatchLabels: map[string]string{"k8s-app": "kube-dns", "k8s:io.kubernetes.pod.namespace": "kube-system"}, ToPorts: dns},
+		{ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"vendor.com"}, MatchPatterns: []string{"*.vendor.com"}}}},
+		{ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"example.com"}}}},
+	}
+
+	expected := map[string][]types.SubRule{
+		"dns": {
+			{"matchName": "example.com"}, {"matchName": "vendor.com"}, {"matchPattern": "*.vendor.com"},
+			{"matchPattern": "example.com.*.svc.cluster.local"}, {"matchName": "example.com.svc.cluster.local"}, {"matchName": "example.com.cluster.local"},
+			{"matchPattern": "vendor.com.*.svc.cluster.local"}, {"matchName": "vendor.com.svc.cluster.local"}, {"matchName": "vendor.com.cluster.local"},
+			{"matchPattern": "*.vendor.com.*.svc.cluster.local"}, {"matchPattern": "*.vendor.com.svc.cluster.local"}, {"matchPattern": "*.vendor.com.cluster.local"},
+			{"matchPattern": "*.cluster.local"}, {"matchPattern": "*.svc.cluster.local"}, {"matchPattern": "*.*.svc.cluster.local"},
+		},
+	}
+
+	// the discovered rule toward kube-dns allows the queried names only
+	actual := ConvertKnoxNetworkPolicyToCiliumPolicy(knoxPolicy)
+	assert.Len(t, actual.Spec.Egress, 3)
+	assert.Equal(t, expected, actual.Spec.Egress[0].ToPorts[0].Rules)
+
+	// a rule toward kube-dns is added if the policy has none
+	knoxPolicy.Spec.Egress = knoxPolicy.Spec.Egress[1:]
+	actual = ConvertKnoxNetworkPolicyToCiliumPolicy(knoxPolicy)
+	assert.Len(t, actual.Spec.Egress, 3)
+	assert.Equal(t, expected, actual.Spec.Egress[2].ToPorts[0].Rules)
+	assert.Equal(t, []types.CiliumPort{{Port: "53", Protocol: "UDP"}, {Port: "53", Protocol: "TCP"}}, actual.Spec.Egress[2].ToPorts[0].Ports)
+
+	// the other ports of kube-dns are not parsed as dns
+	knoxPolicy.Spec.Egress = append(knoxPolicy.Spec.Egress, types.Egress{
+		MatchLabels: map[string]string{"k8s-app": "kube-dns", "k8s:io.kubernetes.pod.namespace": "kube-system"},
+		ToPorts:     []types.SpecPort{{Port: "53", Protocol: "tcp"}, {Port: "9153", Protocol: "tcp"}},
+	})
+	actual = ConvertKnoxNetworkPolicyToCiliumPolicy(knoxPolicy)
+	assert.Len(t, actual.Spec.Egress, 3)
+	assert.Len(t, actual.Spec.Egress[2].ToPorts, 2)
+	assert.Equal(t, []types.CiliumPort{{Port: "53", Protocol: "TCP"}}, actual.Spec.Egress[2].ToPorts[0].Ports)
+	assert.Equal(t, expected, actual.Spec.Egress[2].ToPorts[0].Rules)
+	assert.Equal(t, []types.CiliumPort{{Port: "9153", Protocol: "TCP"}}, actual.Spec.Egress[2].ToPorts[1].Ports)
+	assert.Empty(t, actual.Spec.Egress[2].ToPorts[1].Rules)
+
+	// no dns rule without fqdns
+	knoxPolicy.Spec.Egress = []types.Egress{{MatchLabels: map[string]string{"k8s-app": "kube-dns"}, ToPorts: dns}}
+	actual = ConvertKnoxNetworkPolicyToCiliumPolicy(knoxPolicy)
+	assert.Nil(t, actual.Spec.Egress[0].ToPorts[0].Rules)
+}
+
+func TestCiliumDNSRulesClusterDomain(t *testing.T) {
+	config.CurrentCfg.ConfigNetPolicy.ClusterDomain = "corp.internal"
+	t.Cleanup(func() {
+		config.CurrentCfg.ConfigNetPolicy.ClusterDomain = ""
+	})
+
+	rules := ciliumDNSRules([]types.Egress{{ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"api.vendor.com"}}}}})
+	assert.Equal(t, []types.SubRule{
+		{"matchName": "api.vendor.com"},
+		{"matchPattern": "api.vendor.com.*.svc.corp.internal"}, {"matchName": "api.vendor.com.svc.corp.internal"}, {"matchName": "api.vendor.com.corp.internal"},
+		{"matchPattern": "*.corp.internal"}, {"matchPattern": "*.svc.corp.internal"}, {"matchPattern": "*.*.svc.corp.internal"},
+	}, rules)
+}
+
+func TestConvertKnoxPortRangeToCiliumPolicy(t *testing.T) {
+	knoxPolicy := types.KnoxNetworkPolicy{Metadata: map[string]string{"name": "port-range", "namespace": "default"}}
+	knoxPolicy.Spec.Selector.MatchLabels = map[string]string{"app": "rtp"}
+	knoxPolicy.Spec.Ingress = []types.Ingress{
+		{MatchLabels: map[string]string{"app": "client"}, ToPorts: []types.SpecPort{{Port: "30000", EndPort: 30100, Protocol: "udp"}}},
+	}
+
+	actual := ConvertKnoxNetworkPolicyToCiliumPolicy(knoxPolicy)
+	assert.Equal(t, []types.CiliumPort{{Port: "30000", EndPort: 30100, Protocol: "UDP"}}, actual.Spec.Ingress[0].ToPorts[0].Ports)
+}
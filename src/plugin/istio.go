@@ -0,0 +1,240 @@
+package plugin
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/types"
+)
+
+const (
+	// IstioTrustDomain is the trust domain of the service account principals
+	IstioTrustDomain = "cluster.local"
+
+	ciliumServiceAccountLabel = "io.cilium.k8s.policy.serviceaccount"
+)
+
+// knoxServiceAccountLabels are the labels carrying the service account of a pod
+var knoxServiceAccountLabels = []string{"k8s:" + ciliumServiceAccountLabel, ciliumServiceAccountLabel}
+
+// ============================= //
+// == Istio Policy Conversion == //
+// ============================= //
+
+// istioConverter converts a knox network policy to an istio authorization policy
+type istioConverter struct {
+	ruleReporter
+}
+
+// istioPathSegments maps the wildcard segments of the aggregated http paths to the istio path templates
+var istioPathSegments = []struct{ regex, template string }{
+	{"/.[^/]+", "/{*}"},
+	{"/[^/]+", "/{*}"},
+	{"/[0-9^/]+", "/{*}"},
+	{"/[0-9]+", "/{*}"},
+}
+
+// istioPath converts a knox http path, ok is false if the path has a regex istio cannot express
+func istioPath(path string) (string, bool) {
+	// a trailing wildcard matches all the sub paths
+	for _, suffix := range []string{"/.+", "/.*"} {
+		if strings.HasSuffix(path, suffix) {
+			path = strings.TrimSuffix(path, suffix) + "/{**}"
+		}
+	}
+
+	for _, segment := range istioPathSegments {
+		path = strings.ReplaceAll(path, segment.regex+"/", segment.template+"/")
+		if strings.HasSuffix(path, segment.regex) {
+			path = strings.TrimSuffix(path, segment.regex) + segment.template
+		}
+	}
+
+	if strings.ContainsAny(strings.NewReplacer("{**}", "", "{*}", "").Replace(path), `[]().*+?^$|\{}`) {
+		return "", false
+	}
+	return path, true
+}
+
+// istioSource builds the source of a label rule, the service account if known, else the namespace
+func (c *istioConverter) istioSource(matchLabels map[string]string) types.IstioSource {
+	namespace := c.policy.Metadata["namespace"]
+	serviceAccount := ""
+	podLabels := false
+
+	for k, v := range matchLabels {
+		switch {
+		case libs.ContainsElement(knoxNamespaceLabels, k):
+			namespace = v
+		case libs.ContainsElement(knoxServiceAccountLabels, k):
+			serviceAccount = v
+		default:
+			podLabels = true
+		}
+	}
+
+	if serviceAccount != "" {
+		return types.IstioSource{
+			Principals: []string{IstioTrustDomain + "/ns/" + namespace + "/sa/" + serviceAccount},
+		}
+	}
+
+	if podLabels {
+		c.report(directionIngress, "matchLabels",
+			"istio matches the sources by service account or namespace, the rule allows the whole namespace "+namespace)
+	}
+	return types.IstioSource{Namespaces: []string{namespace}}
+}
+
+// istioOperations builds the operations of the rule, an operation per http rule with its method and path,
+// since an operation matches each of its methods with each of its paths
+func (c *istioConverter) istioOperations(ports []types.SpecPort, https []types.SpecHTTP) ([]types.IstioOperation, bool) {
+	portOperation := types.IstioOperation{}
+
+	for _, port := range ports {
+		if port.EndPort != 0 {
+			c.report(directionIngress, "toPorts="+port.Port+"-"+strconv.Itoa(port.EndPort), "istio matches the ports one by one, not the port ranges")
+			return nil, false
+		}
+		// port 0 means all the ports
+		if port.Port != "" && port.Port != "0" && !libs.ContainsElement(portOperation.Ports, port.Port) {
+			portOperation.Ports = append(portOperation.Ports, port.Port)
+		}
+	}
+	sort.Strings(portOperation.Ports)
+
+	if len(https) == 0 {
+		if len(portOperation.Ports) == 0 {
+			return nil, true
+		}
+		return []types.IstioOperation{portOperation}, true
+	}
+
+	operations := []types.IstioOperation{}
+	for _, http := range https {
+		operation := types.IstioOperation{Ports: portOperation.Ports}
+		if http.Method != "" {
+			operation.Methods = []string{http.Method}
+		}
+		if http.Host != "" {
+			operation.Hosts = []string{http.Host}
+		}
+		if len(http.Headers) > 0 {
+			c.report(directionIngress, "toHTTPs="+strings.Join(http.Headers, ","), "the headers are not matched by the istio operation")
+		}
+
+		if http.Path != "" {
+			path, ok := istioPath(http.Path)
+			if !ok {
+				c.report(directionIngress, "toHTTPs="+http.Path, "the path regex has no istio path template")
+				return nil, false
+			}
+			operation.Paths = []string{path}
+		}
+
+		if !libs.ContainsElement(operations, operation) {
+			operations = append(operations, operation)
+		}
+	}
+
+	return operations, true
+}
+
+func (c *istioConverter) convertIngress(ingress types.Ingress) (types.IstioRule, bool) {
+	rule := types.IstioRule{}
+
+	switch {
+	case ingress.MatchLabels != nil:
+		rule.From = []types.IstioFrom{{Source: c.istioSource(ingress.MatchLabels)}}
+	case len(ingress.FromCIDRs) > 0:
+		source := types.IstioSource{}
+		source.IPBlocks, source.NotIPBlocks = calicoNets(ingress.FromCIDRs)
+		rule.From = []types.IstioFrom{{Source: source}}
+	case len(ingress.FromEntities) > 0:
+		c.report(directionIngress, "fromEntities="+strings.Join(ingress.FromEntities, ","), "istio has no entity sources")
+		return rule, false
+	default:
+		c.report(directionIngress, "peer", "the rule has no peer")
+		return rule, false
+	}
+
+	if len(ingress.ToKafkas) > 0 {
+		c.report(directionIngress, "toKafkas", "istio authorization policies have no Kafka rules, only the ports are allowed")
+	}
+
+	operations, ok := c.istioOperations(ingress.ToPorts, ingress.ToHTTPs)
+	if !ok {
+		return rule, false
+	}
+	for _, operation := range operations {
+		rule.To = append(rule.To, types.IstioTo{Operation: operation})
+	}
+
+	return rule, true
+}
+
+// convert returns the authorization policy of the ingress rules, ok is false if the policy has no http rule.
+// The l4 rules of the policy are converted too, since an ALLOW policy denies the requests none of its rules match.
+func (c *istioConverter) convert() (types.IstioAuthorizationPolicy, bool) {
+	knp := c.policy
+
+	hasHTTP := false
+	for _, ing := range knp.Spec.Ingress {
+		if len(ing.ToHTTPs) > 0 {
+			hasHTTP = true
+		}
+	}
+	if !hasHTTP {
+		return types.IstioAuthorizationPolicy{}, false
+	}
+
+	if knp.Kind == types.KindKnoxHostNetworkPolicy {
+		c.report("", "nodeSelector", "istio authorization policies only select workloads")
+		return types.IstioAuthorizationPolicy{}, false
+	}
+
+	istioPolicy := types.IstioAuthorizationPolicy{
+		APIVersion: types.IstioPolicyAPIVersion,
+		Kind:       types.KindIstioAuthorizationPolicy,
+		Metadata: map[string]string{
+			"name":      knp.Metadata["name"],
+			"namespace": knp.Metadata["namespace"],
+		},
+		Spec: types.IstioSpec{
+			Selector: types.Selector{MatchLabels: k8sLabels(knp.Spec.Selector.MatchLabels)},
+			Action:   types.IstioActionAllow,
+		},
+	}
+
+	for _, ing := range knp.Spec.Ingress {
+		if rule, ok := c.convertIngress(ing); ok {
+			istioPolicy.Spec.Rules = append(istioPolicy.Spec.Rules, rule)
+		}
+	}
+
+	return istioPolicy, len(istioPolicy.Spec.Rules) > 0
+}
+
+// ConvertKnoxNetworkPolicyToIstioPolicy converts the ingress http rules of a knox policy to an authorization policy
+func ConvertKnoxNetworkPolicyToIstioPolicy(inPolicy types.KnoxNetworkPolicy) (types.IstioAuthorizationPolicy, bool, []types.UnsupportedPolicyRule) {
+	converter := &istioConverter{ruleReporter{policy: inPolicy}}
+	istioPolicy, ok := converter.convert()
+	return istioPolicy, ok, converter.unsupported
+}
+
+func ConvertKnoxPoliciesToIstioPolicies(policies []types.KnoxNetworkPolicy) ([]types.IstioAuthorizationPolicy, []types.UnsupportedPolicyRule) {
+	istioPolicies := []types.IstioAuthorizationPolicy{}
+	unsupported := []types.UnsupportedPolicyRule{}
+
+	for _, policy := range policies {
+		istioPolicy, ok, rules := ConvertKnoxNetworkPolicyToIstioPolicy(policy)
+		if ok {
+			istioPolicies = append(istioPolicies, istioPolicy)
+		}
+		unsupported = append(unsupported, rules...)
+	}
+
+	return istioPolicies, unsupported
+}
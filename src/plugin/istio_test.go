@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIstioPath(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/health":               "/health",
+		"/users/[0-9]+":         "/users/{*}",
+		"/users/[0-9]+/orders":  "/users/{*}/orders",
+		"/api/.+":               "/api/{**}",
+		"/files/.[^/]+/content": "/files/{*}/content",
+		"/pets/[^/]+/photos":    "/pets/{*}/photos",
+	} {
+		res, ok := istioPath(path)
+		assert.True(t, ok, path)
+		assert.Equal(t, expected, res)
+	}
+
+	// {**} only matches the trailing segments
+	_, ok := istioPath("/api/.+/status")
+	assert.False(t, ok)
+
+	_, ok = istioPath("/users/(admin|root)")
+	assert.False(t, ok)
+}
+
+func TestConvertIstioPolicy(t *testing.T) {
+	policy := newKnoxPolicy("default", "http")
+	policy.Spec.Selector.MatchLabels = map[string]string{"k8s:app": "web"}
+	policy.Spec.Ingress = []types.Ingress{
+		{
+			MatchLabels: map[string]string{"k8s:io.cilium.k8s.policy.serviceaccount": "client", "k8s:io.kubernetes.pod.namespace": "frontend"},
+			ToPorts:     []types.SpecPort{{Port: "8080", Protocol: "TCP"}},
+			ToHTTPs:     []types.SpecHTTP{{Method: "GET", Path: "/users/[0-9]+"}, {Method: "POST", Path: "/health"}},
+		},
+		{
+			FromCIDRs: []types.SpecCIDR{{CIDRs: []string{"10.0.0.0/8"}}},
+			ToPorts:   []types.SpecPort{{Port: "9090", Protocol: "TCP"}},
+		},
+	}
+
+	res, ok, unsupported := ConvertKnoxNetworkPolicyToIstioPolicy(policy)
+	assert.True(t, ok)
+	assert.Len(t, unsupported, 0)
+
+	assert.Equal(t, types.KindIstioAuthorizationPolicy, res.Kind)
+	assert.Equal(t, types.IstioActionAllow, res.Spec.Action)
+	assert.Equal(t, map[string]string{"app": "web"}, res.Spec.Selector.MatchLabels)
+	assert.Len(t, res.Spec.Rules, 2)
+
+	http := res.Spec.Rules[0]
+	assert.Equal(t, []string{"cluster.local/ns/frontend/sa/client"}, http.From[0].Source.Principals)
+	// an operation per http rule, so that the methods and paths are not crossed
+	assert.Equal(t, []types.IstioTo{
+		{Operation: types.IstioOperation{Ports: []string{"8080"}, Methods: []string{"GET"}, Paths: []string{"/users/{*}"}}},
+		{Operation: types.IstioOperation{Ports: []string{"8080"}, Methods: []string{"POST"}, Paths: []string{"/health"}}},
+	}, http.To)
+
+	// the l4 rules are kept, the allow policy would deny them otherwise
+	assert.Equal(t, []string{"10.0.0.0/8"}, res.Spec.Rules[1].From[0].Source.IPBlocks)
+	assert.Equal(t, []string{"9090"}, res.Spec.Rules[1].To[0].Operation.Ports)
+}
+
+func TestConvertIstioPolicyUnsupported(t *testing.T) {
+	l4Policy := newKnoxPolicy("default", "l4")
+	l4Policy.Spec.Ingress = []types.Ingress{{MatchLabels: map[string]string{"app": "client"}}}
+
+	policy := newKnoxPolicy("default", "labels")
+	policy.Spec.Ingress = []types.Ingress{
+		{
+			MatchLabels: map[string]string{"app": "client"},
+			ToHTTPs:     []types.SpecHTTP{{Method: "POST", Path: "/login"}},
+		},
+		{FromEntities: []string{"world"}},
+	}
+
+	res, unsupported := ConvertKnoxPoliciesToIstioPolicies([]types.KnoxNetworkPolicy{l4Policy, policy})
+
+	// only the policies with http rules are converted
+	assert.Len(t, res, 1)
+	assert.Equal(t, "labels", res[0].Metadata["name"])
+
+	// the pod labels are widened to the namespace of the policy
+	assert.Equal(t, []string{"default"}, res[0].Spec.Rules[0].From[0].Source.Namespaces)
+
+	rules := []string{}
+	for _, rule := range unsupported {
+		rules = append(rules, rule.Rule)
+	}
+	assert.Equal(t, []string{"matchLabels", "fromEntities=world"}, rules)
+}
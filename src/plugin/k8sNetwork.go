@@ -1,7 +1,10 @@
 package plugin
 
 import (
+	"net"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/accuknox/auto-policy-discovery/src/config"
 	"github.com/accuknox/auto-policy-discovery/src/libs"
@@ -12,98 +15,425 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-func ConvertKnoxNetPolicyToK8sNetworkPolicy(clustername, namespace string) []nv1.NetworkPolicy {
+const (
+	// K8sNamespaceNameLabel is the label the api server sets with the name of each namespace
+	K8sNamespaceNameLabel = "kubernetes.io/metadata.name"
 
-	knoxNetPolicies := libs.GetNetworkPolicies(config.CurrentCfg.ConfigDB, clustername, namespace, "latest", "", "")
-	log.Info().Msgf("No. of knox network policies - %d", len(knoxNetPolicies))
+	directionEgress  = "egress"
+	directionIngress = "ingress"
+)
 
-	if len(knoxNetPolicies) <= 0 {
-		return nil
-	}
+// the namespace of a knox peer is kept in its match labels
+var knoxNamespaceLabels = []string{"k8s:io.kubernetes.pod.namespace", "io.kubernetes.pod.namespace"}
 
-	res := []nv1.NetworkPolicy{}
+// ============================ //
+// == K8s Network Conversion == //
+// ============================ //
 
-	for _, knp := range knoxNetPolicies {
-		k8NetPol := nv1.NetworkPolicy{}
+// ruleReporter records the rules of a knox policy a converter cannot express.
+// The converters only isolate a direction if some of its rules are expressible,
+// else all of its traffic would be denied.
+type ruleReporter struct {
+	policy      types.KnoxNetworkPolicy
+	unsupported []types.UnsupportedPolicyRule
+}
 
-		k8NetPol.APIVersion = types.K8sNwPolicyAPIVersion
-		k8NetPol.Kind = types.K8sNwPolicyKind
-		k8NetPol.Name = knp.Metadata["name"]
-		k8NetPol.Namespace = knp.Metadata["namespace"]
-		k8NetPol.ClusterName = knp.Metadata["cluster_name"]
+func (r *ruleReporter) report(direction, rule, reason string) {
+	r.unsupported = append(r.unsupported, types.UnsupportedPolicyRule{
+		PolicyName: r.policy.Metadata["name"],
+		Namespace:  r.policy.Metadata["namespace"],
+		Direction:  direction,
+		Rule:       rule,
+		Reason:     reason,
+	})
+}
 
-		if len(knp.Spec.Egress) > 0 {
+// k8sConverter converts a knox network policy to a kubernetes network policy
+type k8sConverter struct {
+	ruleReporter
+}
 
-			for _, eg := range knp.Spec.Egress {
-				var egressRule nv1.NetworkPolicyEgressRule
-				var protocol v1.Protocol
+// k8sLabels drops the cilium source prefix of the labels
+func k8sLabels(labels map[string]string) map[string]string {
+	res := map[string]string{}
+	for k, v := range labels {
+		res[strings.TrimPrefix(k, "k8s:")] = v
+	}
+	return res
+}
 
-				if eg.ToPorts[0].Protocol == string(v1.ProtocolTCP) {
-					protocol = v1.ProtocolTCP
-				} else if eg.ToPorts[0].Protocol == string(v1.ProtocolUDP) {
-					protocol = v1.ProtocolUDP
-				}
-				portVal, _ := strconv.ParseInt(eg.ToPorts[0].Port, 10, 32)
-
-				port := nv1.NetworkPolicyPort{
-					Port: &intstr.IntOrString{
-						Type:   intstr.Int,
-						IntVal: int32(portVal),
-					},
-					Protocol: &protocol,
-				}
+// convertPorts converts the knox ports, nil means all the ports
+func (c *k8sConverter) convertPorts(direction string, ports []types.SpecPort) []nv1.NetworkPolicyPort {
+	var res []nv1.NetworkPolicyPort
 
-				to := nv1.NetworkPolicyPeer{
-					PodSelector: &metav1.LabelSelector{
-						MatchLabels: eg.MatchLabels,
-					},
-				}
+	for _, p := range ports {
+		var protocols []v1.Protocol
 
-				egressRule.Ports = append(egressRule.Ports, port)
-				egressRule.To = append(egressRule.To, to)
+		switch strings.ToUpper(p.Protocol) {
+		case "", string(v1.ProtocolTCP):
+			protocols = []v1.Protocol{v1.ProtocolTCP}
+		case string(v1.ProtocolUDP):
+			protocols = []v1.Protocol{v1.ProtocolUDP}
+		case string(v1.ProtocolSCTP):
+			protocols = []v1.Protocol{v1.ProtocolSCTP}
+		case "ANY":
+			protocols = []v1.Protocol{v1.ProtocolTCP, v1.ProtocolUDP, v1.ProtocolSCTP}
+		default:
+			c.report(direction, "toPorts="+p.Port+"/"+p.Protocol, "protocol "+p.Protocol+" is not supported")
+			continue
+		}
 
-				k8NetPol.Spec.Egress = append(k8NetPol.Spec.Egress, egressRule)
+		var port *intstr.IntOrString
+		var endPort *int32
+		if portVal, err := strconv.ParseInt(p.Port, 10, 32); err == nil {
+			// port 0 means all the ports of the protocol
+			if portVal > 0 {
+				val := intstr.FromInt(int(portVal))
+				port = &val
+			}
+			if portVal > 0 && p.EndPort > int(portVal) {
+				end := int32(p.EndPort)
+				endPort = &end
 			}
+		} else if p.Port != "" {
+			// named port
+			val := intstr.FromString(p.Port)
+			port = &val
+		}
+
+		for i := range protocols {
+			res = append(res, nv1.NetworkPolicyPort{Protocol: &protocols[i], Port: port, EndPort: endPort})
+		}
+	}
+
+	return res
+}
+
+// convertLabelPeer builds the peer of a label rule, with a namespace selector for a cross namespace peer
+func (c *k8sConverter) convertLabelPeer(matchLabels map[string]string) nv1.NetworkPolicyPeer {
+	labels := map[string]string{}
+	namespace := ""
+	for k, v := range matchLabels {
+		if libs.ContainsElement(knoxNamespaceLabels, k) {
+			namespace = v
+			continue
+		}
+		labels[k] = v
+	}
+
+	peer := nv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{MatchLabels: k8sLabels(labels)},
+	}
 
-			k8NetPol.Spec.PolicyTypes = append(k8NetPol.Spec.PolicyTypes, nv1.PolicyType(nv1.PolicyTypeEgress))
+	if namespace != "" && namespace != c.policy.Metadata["namespace"] {
+		peer.NamespaceSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{K8sNamespaceNameLabel: namespace},
 		}
+	}
 
-		if len(knp.Spec.Ingress) > 0 {
-			for _, ing := range knp.Spec.Ingress {
-				var ingressRule nv1.NetworkPolicyIngressRule
-				var protocol v1.Protocol
+	return peer
+}
 
-				if ing.ToPorts[0].Protocol == string(v1.ProtocolTCP) {
-					protocol = v1.ProtocolTCP
-				} else if ing.ToPorts[0].Protocol == string(v1.ProtocolUDP) {
-					protocol = v1.ProtocolUDP
+// convertCIDRPeers builds an ip block per cidr, with the excepted cidrs it contains,
+// the invalid cidrs are reported and skipped since the api server rejects them
+func (c *k8sConverter) convertCIDRPeers(direction string, cidrs []types.SpecCIDR) []nv1.NetworkPolicyPeer {
+	var res []nv1.NetworkPolicyPeer
+
+	for _, specCIDR := range cidrs {
+		for _, cidr := range specCIDR.CIDRs {
+			_, network, err := net.ParseCIDR(cidr)
+			if err != nil {
+				c.report(direction, "cidr="+cidr, "invalid cidr")
+				continue
+			}
+
+			ipBlock := &nv1.IPBlock{CIDR: cidr}
+			for _, except := range specCIDR.Except {
+				exceptIP, exceptNet, err := net.ParseCIDR(except)
+				if err != nil || !network.Contains(exceptIP) {
+					continue
 				}
-				portVal, _ := strconv.ParseInt(ing.ToPorts[0].Port, 10, 32)
-
-				port := nv1.NetworkPolicyPort{
-					Port: &intstr.IntOrString{
-						Type:   intstr.Int,
-						IntVal: int32(portVal),
-					},
-					Protocol: &protocol,
+				// the except must be strictly smaller than the cidr
+				cidrBits, _ := network.Mask.Size()
+				exceptBits, _ := exceptNet.Mask.Size()
+				if exceptBits > cidrBits {
+					ipBlock.Except = append(ipBlock.Except, except)
 				}
+			}
 
-				from := nv1.NetworkPolicyPeer{
-					PodSelector: &metav1.LabelSelector{
-						MatchLabels: ing.MatchLabels,
-					},
-				}
+			res = append(res, nv1.NetworkPolicyPeer{IPBlock: ipBlock})
+		}
+	}
+
+	return res
+}
+
+// ================== //
+// == Rule Merging == //
+// ================== //
+
+// k8sRule is a converted rule before the merge, nil ports means all the ports
+type k8sRule struct {
+	peers []nv1.NetworkPolicyPeer
+	ports []nv1.NetworkPolicyPort
+}
 
-				ingressRule.Ports = append(ingressRule.Ports, port)
-				ingressRule.From = append(ingressRule.From, from)
+func peerKey(peer nv1.NetworkPolicyPeer) string {
+	keys := []string{}
+	if peer.IPBlock != nil {
+		keys = append(keys, "cidr:"+peer.IPBlock.CIDR+"-"+strings.Join(peer.IPBlock.Except, ","))
+	}
+	if peer.NamespaceSelector != nil {
+		keys = append(keys, "ns:"+labelsKey(peer.NamespaceSelector.MatchLabels))
+	}
+	if peer.PodSelector != nil {
+		keys = append(keys, "pod:"+labelsKey(peer.PodSelector.MatchLabels))
+	}
+	return strings.Join(keys, "|")
+}
 
-				k8NetPol.Spec.Ingress = append(k8NetPol.Spec.Ingress, ingressRule)
+func labelsKey(labels map[string]string) string {
+	kv := []string{}
+	for k, v := range labels {
+		kv = append(kv, k+"="+v)
+	}
+	sort.Strings(kv)
+	return strings.Join(kv, ",")
+}
+
+func portKey(port nv1.NetworkPolicyPort) string {
+	key := string(*port.Protocol)
+	if port.Port != nil {
+		key += "/" + port.Port.String()
+	}
+	if port.EndPort != nil {
+		key += "-" + strconv.Itoa(int(*port.EndPort))
+	}
+	return key
+}
+
+// mergeK8sRules merges the ports of the rules per peer, then the peers of the rules with the same ports
+func mergeK8sRules(rules []k8sRule) []k8sRule {
+	type peerPorts struct {
+		peer     nv1.NetworkPolicyPeer
+		allPorts bool
+		ports    map[string]nv1.NetworkPolicyPort
+	}
+
+	peers := map[string]*peerPorts{}
+	order := []string{}
+
+	for _, rule := range rules {
+		for _, peer := range rule.peers {
+			key := peerKey(peer)
+			pp, ok := peers[key]
+			if !ok {
+				pp = &peerPorts{peer: peer, ports: map[string]nv1.NetworkPolicyPort{}}
+				peers[key] = pp
+				order = append(order, key)
 			}
-			k8NetPol.Spec.PolicyTypes = append(k8NetPol.Spec.PolicyTypes, nv1.PolicyType(nv1.PolicyTypeIngress))
+
+			if rule.ports == nil {
+				pp.allPorts = true
+			}
+			for _, port := range rule.ports {
+				pp.ports[portKey(port)] = port
+			}
+		}
+	}
+
+	// the peers allowed on the same ports share a rule
+	merged := []k8sRule{}
+	rulePerPorts := map[string]int{}
+
+	for _, key := range order {
+		pp := peers[key]
+
+		portKeys := []string{}
+		for k := range pp.ports {
+			portKeys = append(portKeys, k)
+		}
+		sort.Strings(portKeys)
+
+		var ports []nv1.NetworkPolicyPort
+		rulePortsKey := "all"
+		if !pp.allPorts {
+			for _, k := range portKeys {
+				ports = append(ports, pp.ports[k])
+			}
+			rulePortsKey = strings.Join(portKeys, ",")
+		}
+
+		if idx, ok := rulePerPorts[rulePortsKey]; ok {
+			merged[idx].peers = append(merged[idx].peers, pp.peer)
+			continue
 		}
 
-		res = append(res, k8NetPol)
+		rulePerPorts[rulePortsKey] = len(merged)
+		merged = append(merged, k8sRule{peers: []nv1.NetworkPolicyPeer{pp.peer}, ports: ports})
 	}
 
-	return res
+	return merged
+}
+
+// =================== //
+// == Rule Building == //
+// =================== //
+
+// convertRule converts the peer and the ports of a knox rule, ok is false if the rule is not expressible
+func (c *k8sConverter) convertRule(direction string, labels map[string]string, cidrs []types.SpecCIDR,
+	ports []types.SpecPort, icmps []types.SpecICMP, https []types.SpecHTTP) (k8sRule, bool) {
+	rule := k8sRule{}
+
+	if len(icmps) > 0 {
+		c.report(direction, "icmps", "ICMP is not supported by the kubernetes network policy")
+		if len(ports) == 0 {
+			// without ports, the rule would allow all the traffic of the peer
+			return rule, false
+		}
+	}
+
+	if labels != nil {
+		rule.peers = append(rule.peers, c.convertLabelPeer(labels))
+	}
+	rule.peers = append(rule.peers, c.convertCIDRPeers(direction, cidrs)...)
+
+	if len(rule.peers) == 0 {
+		c.report(direction, "peer", "the rule has no label or cidr peer")
+		return rule, false
+	}
+
+	if len(ports) > 0 {
+		rule.ports = c.convertPorts(direction, ports)
+		if len(rule.ports) == 0 {
+			// none of the ports are expressible, allowing all of them is too permissive
+			return rule, false
+		}
+	}
+
+	if len(https) > 0 {
+		c.report(direction, "toHTTPs", "HTTP rules are not supported by the kubernetes network policy, only the ports are enforced")
+	}
+
+	return rule, true
+}
+
+func (c *k8sConverter) convertEgress(egress types.Egress) (k8sRule, bool) {
+	if len(egress.ToFQDNs) > 0 {
+		for _, fqdn := range egress.ToFQDNs {
+			c.report(directionEgress, "toFQDNs="+strings.Join(append(append([]string{}, fqdn.MatchNames...), fqdn.MatchPatterns...), ","), "FQDN peers are not supported by the kubernetes network policy")
+		}
+		return k8sRule{}, false
+	}
+	if len(egress.ToEntities) > 0 {
+		c.report(directionEgress, "toEntities="+strings.Join(egress.ToEntities, ","), "entities are not supported by the kubernetes network policy")
+		return k8sRule{}, false
+	}
+	if len(egress.ToServices) > 0 {
+		for _, svc := range egress.ToServices {
+			c.report(directionEgress, "toServices="+svc.Namespace+"/"+svc.ServiceName, "service peers are not supported by the kubernetes network policy")
+		}
+		return k8sRule{}, false
+	}
+
+	if len(egress.ToKafkas) > 0 {
+		c.report(directionEgress, "toKafkas", "Kafka rules are not supported by the kubernetes network policy, only the ports are enforced")
+	}
+
+	return c.convertRule(directionEgress, egress.MatchLabels, egress.ToCIDRs, egress.ToPorts, egress.ICMPs, egress.ToHTTPs)
+}
+
+func (c *k8sConverter) convertIngress(ingress types.Ingress) (k8sRule, bool) {
+	if len(ingress.FromEntities) > 0 {
+		c.report(directionIngress, "fromEntities="+strings.Join(ingress.FromEntities, ","), "entities are not supported by the kubernetes network policy")
+		return k8sRule{}, false
+	}
+
+	if len(ingress.ToKafkas) > 0 {
+		c.report(directionIngress, "toKafkas", "Kafka rules are not supported by the kubernetes network policy, only the ports are enforced")
+	}
+
+	return c.convertRule(directionIngress, ingress.MatchLabels, ingress.FromCIDRs, ingress.ToPorts, ingress.ICMPs, ingress.ToHTTPs)
+}
+
+// convert returns the kubernetes network policy, ok is false if it has no rule
+func (c *k8sConverter) convert() (nv1.NetworkPolicy, bool) {
+	knp := c.policy
+
+	k8NetPol := nv1.NetworkPolicy{}
+	k8NetPol.APIVersion = types.K8sNwPolicyAPIVersion
+	k8NetPol.Kind = types.K8sNwPolicyKind
+	k8NetPol.Name = knp.Metadata["name"]
+	k8NetPol.Namespace = knp.Metadata["namespace"]
+	k8NetPol.ClusterName = knp.Metadata["cluster_name"]
+	k8NetPol.Spec.PodSelector = metav1.LabelSelector{MatchLabels: k8sLabels(knp.Spec.Selector.MatchLabels)}
+
+	if knp.Kind == types.KindKnoxHostNetworkPolicy {
+		c.report("", "nodeSelector", "host policies are not supported by the kubernetes network policy")
+		return k8NetPol, false
+	}
+
+	egressRules := []k8sRule{}
+	for _, eg := range knp.Spec.Egress {
+		if rule, ok := c.convertEgress(eg); ok {
+			egressRules = append(egressRules, rule)
+		}
+	}
+
+	ingressRules := []k8sRule{}
+	for _, ing := range knp.Spec.Ingress {
+		if rule, ok := c.convertIngress(ing); ok {
+			ingressRules = append(ingressRules, rule)
+		}
+	}
+
+	for _, rule := range mergeK8sRules(egressRules) {
+		k8NetPol.Spec.Egress = append(k8NetPol.Spec.Egress, nv1.NetworkPolicyEgressRule{To: rule.peers, Ports: rule.ports})
+	}
+	if len(k8NetPol.Spec.Egress) > 0 {
+		k8NetPol.Spec.PolicyTypes = append(k8NetPol.Spec.PolicyTypes, nv1.PolicyTypeEgress)
+	}
+
+	for _, rule := range mergeK8sRules(ingressRules) {
+		k8NetPol.Spec.Ingress = append(k8NetPol.Spec.Ingress, nv1.NetworkPolicyIngressRule{From: rule.peers, Ports: rule.ports})
+	}
+	if len(k8NetPol.Spec.Ingress) > 0 {
+		k8NetPol.Spec.PolicyTypes = append(k8NetPol.Spec.PolicyTypes, nv1.PolicyTypeIngress)
+	}
+
+	return k8NetPol, len(k8NetPol.Spec.PolicyTypes) > 0
+}
+
+// ConvertKnoxNetworkPoliciesToK8sNetworkPolicies converts the knox policies to kubernetes network policies,
+// and reports the rules that cannot be expressed
+func ConvertKnoxNetworkPoliciesToK8sNetworkPolicies(knoxNetPolicies []types.KnoxNetworkPolicy) ([]nv1.NetworkPolicy, []types.UnsupportedPolicyRule) {
+	res := []nv1.NetworkPolicy{}
+	unsupported := []types.UnsupportedPolicyRule{}
+
+	for _, knp := range knoxNetPolicies {
+		converter := &k8sConverter{ruleReporter{policy: knp}}
+		if k8NetPol, ok := converter.convert(); ok {
+			res = append(res, k8NetPol)
+		}
+		unsupported = append(unsupported, converter.unsupported...)
+	}
+
+	return res, unsupported
+}
+
+func ConvertKnoxNetPolicyToK8sNetworkPolicy(clustername, namespace string) ([]nv1.NetworkPolicy, []types.UnsupportedPolicyRule) {
+
+	knoxNetPolicies := libs.GetNetworkPolicies(config.CurrentCfg.ConfigDB, clustername, namespace, "latest", "", "")
+	log.Info().Msgf("No. of knox network policies - %d", len(knoxNetPolicies))
+
+	if len(knoxNetPolicies) <= 0 {
+		return nil, nil
+	}
+
+	res, unsupported := ConvertKnoxNetworkPoliciesToK8sNetworkPolicies(knoxNetPolicies)
+	for _, rule := range unsupported {
+		log.Warn().Msgf("Policy [%s/%s] %s rule [%s] not converted: %s", rule.Namespace, rule.PolicyName, rule.Direction, rule.Rule, rule.Reason)
+	}
+
+	return res, unsupported
 }
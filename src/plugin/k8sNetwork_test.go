@@ -0,0 +1,201 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	nv1 "k8s.io/api/networking/v1"
+)
+
+func newKnoxPolicy(namespace, name string) types.KnoxNetworkPolicy {
+	return types.KnoxNetworkPolicy{
+		Kind:     types.KindKnoxNetworkPolicy,
+		Metadata: map[string]string{"name": name, "namespace": namespace},
+		Spec: types.Spec{
+			Selector: types.Selector{MatchLabels: map[string]string{"app": "web"}},
+		},
+	}
+}
+
+func TestConvertK8sNetworkPolicyPortless(t *testing.T) {
+	policy := newKnoxPolicy("default", "portless")
+	policy.Spec.Ingress = []types.Ingress{{MatchLabels: map[string]string{"app": "client"}}}
+
+	res, unsupported := ConvertKnoxNetworkPoliciesToK8sNetworkPolicies([]types.KnoxNetworkPolicy{policy})
+	assert.Len(t, unsupported, 0)
+	assert.Len(t, res, 1)
+
+	assert.Equal(t, map[string]string{"app": "web"}, res[0].Spec.PodSelector.MatchLabels)
+	assert.Equal(t, []nv1.PolicyType{nv1.PolicyTypeIngress}, res[0].Spec.PolicyTypes)
+	assert.Len(t, res[0].Spec.Ingress, 1)
+	assert.Nil(t, res[0].Spec.Ingress[0].Ports)
+}
+
+func TestConvertK8sNetworkPolicyPeers(t *testing.T) {
+	policy := newKnoxPolicy("default", "peers")
+	policy.Spec.Egress = []types.Egress{
+		{
+			MatchLabels: map[string]string{"app": "db", "k8s:io.kubernetes.pod.namespace": "storage"},
+			ToPorts:     []types.SpecPort{{Port: "3306", Protocol: "TCP"}, {Port: "9000", Protocol: "SCTP"}},
+		},
+		{
+			MatchLabels: map[string]string{"app": "cache", "k8s:io.kubernetes.pod.namespace": "default"},
+			ToPorts:     []types.SpecPort{{Port: "6379", Protocol: "TCP"}},
+		},
+		{
+			ToCIDRs: []types.SpecCIDR{{CIDRs: []string{"10.0.0.0/8"}, Except: []string{"10.1.0.0/16", "192.168.0.0/16"}}},
+			ToPorts: []types.SpecPort{{Port: "53", Protocol: "UDP"}},
+		},
+	}
+
+	res, unsupported := ConvertKnoxNetworkPoliciesToK8sNetworkPolicies([]types.KnoxNetworkPolicy{policy})
+	assert.Len(t, unsupported, 0)
+	assert.Len(t, res, 1)
+
+	egress := res[0].Spec.Egress
+	assert.Len(t, egress, 3)
+
+	// cross namespace peer, with all its ports
+	assert.Equal(t, map[string]string{K8sNamespaceNameLabel: "storage"}, egress[0].To[0].NamespaceSelector.MatchLabels)
+	assert.Equal(t, map[string]string{"app": "db"}, egress[0].To[0].PodSelector.MatchLabels)
+	assert.Len(t, egress[0].Ports, 2)
+	assert.Equal(t, v1.ProtocolSCTP, *egress[0].Ports[0].Protocol)
+	assert.Equal(t, int32(9000), egress[0].Ports[0].Port.IntVal)
+
+	// same namespace peer
+	assert.Nil(t, egress[1].To[0].NamespaceSelector)
+	assert.Equal(t, map[string]string{"app": "cache"}, egress[1].To[0].PodSelector.MatchLabels)
+
+	// the except outside of the cidr is dropped
+	assert.Equal(t, "10.0.0.0/8", egress[2].To[0].IPBlock.CIDR)
+	assert.Equal(t, []string{"10.1.0.0/16"}, egress[2].To[0].IPBlock.Except)
+	assert.Equal(t, v1.ProtocolUDP, *egress[2].Ports[0].Protocol)
+}
+
+func TestConvertK8sNetworkPolicyInvalidCIDR(t *testing.T) {
+	policy := newKnoxPolicy("default", "invalid-cidr")
+	policy.Spec.Egress = []types.Egress{
+		{
+			ToCIDRs: []types.SpecCIDR{{CIDRs: []string{"10.0.0.0/33", "192.168.0.0/16"}}},
+			ToPorts: []types.SpecPort{{Port: "443", Protocol: "TCP"}},
+		},
+		{
+			ToCIDRs: []types.SpecCIDR{{CIDRs: []string{"invalid"}}},
+			ToPorts: []types.SpecPort{{Port: "80", Protocol: "TCP"}},
+		},
+	}
+
+	res, unsupported := ConvertKnoxNetworkPoliciesToK8sNetworkPolicies([]types.KnoxNetworkPolicy{policy})
+	assert.Len(t, res, 1)
+
+	// the invalid cidrs are skipped, a rule without a valid cidr is dropped
+	egress := res[0].Spec.Egress
+	assert.Len(t, egress, 1)
+	assert.Len(t, egress[0].To, 1)
+	assert.Equal(t, "192.168.0.0/16", egress[0].To[0].IPBlock.CIDR)
+
+	assert.Len(t, unsupported, 3)
+	assert.Equal(t, "cidr=10.0.0.0/33", unsupported[0].Rule)
+	assert.Equal(t, "cidr=invalid", unsupported[1].Rule)
+	assert.Equal(t, "peer", unsupported[2].Rule)
+}
+
+func TestConvertK8sNetworkPolicyMerge(t *testing.T) {
+	policy := newKnoxPolicy("default", "merge")
+	policy.Spec.Ingress = []types.Ingress{
+		{MatchLabels: map[string]string{"app": "a"}, ToPorts: []types.SpecPort{{Port: "80", Protocol: "TCP"}}},
+		{MatchLabels: map[string]string{"app": "a"}, ToPorts: []types.SpecPort{{Port: "443", Protocol: "TCP"}}},
+		{MatchLabels: map[string]string{"app": "a"}, ToPorts: []types.SpecPort{{Port: "80", Protocol: "TCP"}}},
+		{MatchLabels: map[string]string{"app": "b"}, ToPorts: []types.SpecPort{{Port: "443", Protocol: "TCP"}, {Port: "80", Protocol: "TCP"}}},
+	}
+
+	res, _ := ConvertKnoxNetworkPoliciesToK8sNetworkPolicies([]types.KnoxNetworkPolicy{policy})
+	assert.Len(t, res, 1)
+
+	// both peers are allowed on 80 and 443, they share a rule
+	ingress := res[0].Spec.Ingress
+	assert.Len(t, ingress, 1)
+	assert.Len(t, ingress[0].From, 2)
+	assert.Len(t, ingress[0].Ports, 2)
+}
+
+func TestConvertK8sNetworkPolicyUnsupported(t *testing.T) {
+	policy := newKnoxPolicy("default", "unsupported")
+	policy.Spec.Egress = []types.Egress{
+		{ToFQDNs: []types.SpecFQDN{{MatchNames: []string{"example.com"}}}, ToPorts: []types.SpecPort{{Port: "443", Protocol: "TCP"}}},
+		{ToEntities: []string{"world"}},
+		{
+			MatchLabels: map[string]string{"app": "api"},
+			ToPorts:     []types.SpecPort{{Port: "8080", Protocol: "TCP"}},
+			ToHTTPs:     []types.SpecHTTP{{Method: "GET", Path: "/health"}},
+		},
+	}
+	policy.Spec.Ingress = []types.Ingress{{FromEntities: []string{"host"}}}
+
+	hostPolicy := newKnoxPolicy("default", "host")
+	hostPolicy.Kind = types.KindKnoxHostNetworkPolicy
+
+	res, unsupported := ConvertKnoxNetworkPoliciesToK8sNetworkPolicies([]types.KnoxNetworkPolicy{policy, hostPolicy})
+	assert.Len(t, res, 1)
+
+	// the ingress has no expressible rule, it is not isolated
+	assert.Equal(t, []nv1.PolicyType{nv1.PolicyTypeEgress}, res[0].Spec.PolicyTypes)
+	assert.Len(t, res[0].Spec.Egress, 1)
+	assert.Equal(t, int32(8080), res[0].Spec.Egress[0].Ports[0].Port.IntVal)
+
+	rules := []string{}
+	for _, rule := range unsupported {
+		rules = append(rules, rule.Rule)
+	}
+	assert.Equal(t, []string{"toFQDNs=example.com", "toEntities=world", "toHTTPs", "fromEntities=host", "nodeSelector"}, rules)
+	assert.Equal(t, "unsupported", unsupported[0].PolicyName)
+	assert.Equal(t, "egress", unsupported[0].Direction)
+}
+
+func TestConvertK8sNetworkPolicyPortRange(t *testing.T) {
+	policy := newKnoxPolicy("default", "port-range")
+	policy.Spec.Ingress = []types.Ingress{
+		{MatchLabels: map[string]string{"app": "client"}, ToPorts: []types.SpecPort{{Port: "30000", EndPort: 30100, Protocol: "UDP"}}},
+	}
+
+	res, unsupported := ConvertKnoxNetworkPoliciesToK8sNetworkPolicies([]types.KnoxNetworkPolicy{policy})
+	assert.Len(t, unsupported, 0)
+	assert.Len(t, res, 1)
+
+	ports := res[0].Spec.Ingress[0].Ports
+	assert.Len(t, ports, 1)
+	assert.Equal(t, 30000, ports[0].Port.IntValue())
+	assert.Equal(t, int32(30100), *ports[0].EndPort)
+	assert.Equal(t, v1.ProtocolUDP, *ports[0].Protocol)
+}
+
+func TestConvertK8sNetworkPolicyPortRangeMerge(t *testing.T) {
+	policy := newKnoxPolicy("default", "port-range-merge")
+	policy.Spec.Ingress = []types.Ingress{
+		{MatchLabels: map[string]string{"app": "a"}, ToPorts: []types.SpecPort{{Port: "8000", Protocol: "TCP"}}},
+		{MatchLabels: map[string]string{"app": "b"}, ToPorts: []types.SpecPort{{Port: "8000", EndPort: 8100, Protocol: "TCP"}}},
+		{MatchLabels: map[string]string{"app": "c"}, ToPorts: []types.SpecPort{{Port: "8000", Protocol: "TCP"}}},
+		{MatchLabels: map[string]string{"app": "c"}, ToPorts: []types.SpecPort{{Port: "8000", EndPort: 8100, Protocol: "TCP"}}},
+	}
+
+	res, unsupported := ConvertKnoxNetworkPoliciesToK8sNetworkPolicies([]types.KnoxNetworkPolicy{policy})
+	assert.Len(t, unsupported, 0)
+	assert.Len(t, res, 1)
+
+	// a port and a range with the same start are different ports
+	ingress := res[0].Spec.Ingress
+	assert.Len(t, ingress, 3)
+
+	assert.Equal(t, map[string]string{"app": "a"}, ingress[0].From[0].PodSelector.MatchLabels)
+	assert.Len(t, ingress[0].Ports, 1)
+	assert.Nil(t, ingress[0].Ports[0].EndPort)
+
+	assert.Equal(t, map[string]string{"app": "b"}, ingress[1].From[0].PodSelector.MatchLabels)
+	assert.Len(t, ingress[1].Ports, 1)
+	assert.Equal(t, int32(8100), *ingress[1].Ports[0].EndPort)
+
+	assert.Equal(t, map[string]string{"app": "c"}, ingress[2].From[0].PodSelector.MatchLabels)
+	assert.Len(t, ingress[2].Ports, 2)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/accuknox/auto-policy-discovery/src/common"
 	"github.com/accuknox/auto-policy-discovery/src/config"
 	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/metrics"
 	obs "github.com/accuknox/auto-policy-discovery/src/observability"
 	"github.com/accuknox/auto-policy-discovery/src/types"
 	pb "github.com/kubearmor/KubeArmor/protobuf"
@@ -337,6 +338,7 @@ func GetSystemAlertsFromKubeArmorRelay(trigger int) []*pb.Log {
 
 	results = KubeArmorRelayLogs     // copy
 	KubeArmorRelayLogs = []*pb.Log{} // reset
+	metrics.SetBufferedLogs(metrics.BufferKubeArmorLogs, 0)
 	KubeArmorRelayLogsMutex.Unlock()
 
 	log.Info().Msgf("The total number of KubeArmor relay traffic flow: [%d] from %s ~ to %s", len(results),
@@ -424,6 +426,7 @@ func StartKubeArmorRelay(StopChan chan struct{}, cfg types.ConfigKubeArmorRelay)
 
 				KubeArmorRelayLogsMutex.Lock()
 				KubeArmorRelayLogs = append(KubeArmorRelayLogs, res)
+				metrics.SetBufferedLogs(metrics.BufferKubeArmorLogs, len(KubeArmorRelayLogs))
 				KubeArmorRelayLogsMutex.Unlock()
 
 				if config.GetCfgObservabilityEnable() {
@@ -487,6 +490,7 @@ func StartKubeArmorRelay(StopChan chan struct{}, cfg types.ConfigKubeArmorRelay)
 
 				KubeArmorRelayLogsMutex.Lock()
 				KubeArmorRelayLogs = append(KubeArmorRelayLogs, &log)
+				metrics.SetBufferedLogs(metrics.BufferKubeArmorLogs, len(KubeArmorRelayLogs))
 				KubeArmorRelayLogsMutex.Unlock()
 
 				if config.GetCfgObservabilityEnable() {
@@ -520,6 +524,7 @@ func GetSystemLogsFromFeedConsumer(trigger int) []*types.KnoxSystemLog {
 
 	results = KubeArmorFCLogs                  // copy
 	KubeArmorFCLogs = []*types.KnoxSystemLog{} // reset
+	metrics.SetBufferedLogs(metrics.BufferKubeArmorLogsFC, 0)
 
 	log.Info().Msgf("The total number of KubeArmor feed-consumer traffic flow: [%d]", len(results))
 
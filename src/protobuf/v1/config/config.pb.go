@@ -422,31 +422,121 @@ func (x *NetworkLogFilter) GetPortNumber() string {
 	return ""
 }
 
+type HTTPOpenAPISpec struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Namespace string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Service   string   `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	Labels    []string `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty"`
+	File      string   `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
+}
+
+func (x *HTTPOpenAPISpec) Reset() {
+	*x = HTTPOpenAPISpec{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_config_config_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HTTPOpenAPISpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HTTPOpenAPISpec) ProtoMessage() {}
+
+func (x *HTTPOpenAPISpec) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_config_config_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HTTPOpenAPISpec.ProtoReflect.Descriptor instead.
+func (*HTTPOpenAPISpec) Descriptor() ([]byte, []int) {
+	return file_v1_config_config_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HTTPOpenAPISpec) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *HTTPOpenAPISpec) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *HTTPOpenAPISpec) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *HTTPOpenAPISpec) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
 type ConfigNetworkPolicy struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	OperationMode           int32               `protobuf:"varint,1,opt,name=operation_mode,json=operationMode,proto3" json:"operation_mode,omitempty"`
-	CronjobTimeInterval     string              `protobuf:"bytes,2,opt,name=cronjob_time_interval,json=cronjobTimeInterval,proto3" json:"cronjob_time_interval,omitempty"`
-	OneTimeJobTimeSelection string              `protobuf:"bytes,3,opt,name=one_time_job_time_selection,json=oneTimeJobTimeSelection,proto3" json:"one_time_job_time_selection,omitempty"`
-	NetworkLogFrom          string              `protobuf:"bytes,4,opt,name=network_log_from,json=networkLogFrom,proto3" json:"network_log_from,omitempty"`
-	NetworkLogFile          string              `protobuf:"bytes,5,opt,name=network_log_file,json=networkLogFile,proto3" json:"network_log_file,omitempty"`
-	NetworkPolicyTo         string              `protobuf:"bytes,6,opt,name=network_policy_to,json=networkPolicyTo,proto3" json:"network_policy_to,omitempty"`
-	NetworkPolicyDir        string              `protobuf:"bytes,7,opt,name=network_policy_dir,json=networkPolicyDir,proto3" json:"network_policy_dir,omitempty"`
-	NetworkPolicyTypes      int32               `protobuf:"varint,8,opt,name=network_policy_types,json=networkPolicyTypes,proto3" json:"network_policy_types,omitempty"`
-	NetworkPolicyRuleTypes  int32               `protobuf:"varint,9,opt,name=network_policy_rule_types,json=networkPolicyRuleTypes,proto3" json:"network_policy_rule_types,omitempty"`
-	NetworkPolicyCidrbits   int32               `protobuf:"varint,10,opt,name=network_policy_cidrbits,json=networkPolicyCidrbits,proto3" json:"network_policy_cidrbits,omitempty"`
-	NetworkPolicyLogFilters []*NetworkLogFilter `protobuf:"bytes,11,rep,name=network_policy_log_filters,json=networkPolicyLogFilters,proto3" json:"network_policy_log_filters,omitempty"`
-	NetworkPolicyL3Level    int32               `protobuf:"varint,12,opt,name=network_policy_l3_level,json=networkPolicyL3Level,proto3" json:"network_policy_l3_level,omitempty"`
-	NetworkPolicyL4Level    int32               `protobuf:"varint,13,opt,name=network_policy_l4_level,json=networkPolicyL4Level,proto3" json:"network_policy_l4_level,omitempty"`
-	NetworkPolicyL7Level    int32               `protobuf:"varint,14,opt,name=network_policy_l7_level,json=networkPolicyL7Level,proto3" json:"network_policy_l7_level,omitempty"`
+	OperationMode              int32               `protobuf:"varint,1,opt,name=operation_mode,json=operationMode,proto3" json:"operation_mode,omitempty"`
+	CronjobTimeInterval        string              `protobuf:"bytes,2,opt,name=cronjob_time_interval,json=cronjobTimeInterval,proto3" json:"cronjob_time_interval,omitempty"`
+	OneTimeJobTimeSelection    string              `protobuf:"bytes,3,opt,name=one_time_job_time_selection,json=oneTimeJobTimeSelection,proto3" json:"one_time_job_time_selection,omitempty"`
+	NetworkLogFrom             string              `protobuf:"bytes,4,opt,name=network_log_from,json=networkLogFrom,proto3" json:"network_log_from,omitempty"`
+	NetworkLogFile             string              `protobuf:"bytes,5,opt,name=network_log_file,json=networkLogFile,proto3" json:"network_log_file,omitempty"`
+	NetworkPolicyTo            string              `protobuf:"bytes,6,opt,name=network_policy_to,json=networkPolicyTo,proto3" json:"network_policy_to,omitempty"`
+	NetworkPolicyDir           string              `protobuf:"bytes,7,opt,name=network_policy_dir,json=networkPolicyDir,proto3" json:"network_policy_dir,omitempty"`
+	NetworkPolicyTypes         int32               `protobuf:"varint,8,opt,name=network_policy_types,json=networkPolicyTypes,proto3" json:"network_policy_types,omitempty"`
+	NetworkPolicyRuleTypes     int32               `protobuf:"varint,9,opt,name=network_policy_rule_types,json=networkPolicyRuleTypes,proto3" json:"network_policy_rule_types,omitempty"`
+	NetworkPolicyCidrbits      int32               `protobuf:"varint,10,opt,name=network_policy_cidrbits,json=networkPolicyCidrbits,proto3" json:"network_policy_cidrbits,omitempty"`
+	NetworkPolicyLogFilters    []*NetworkLogFilter `protobuf:"bytes,11,rep,name=network_policy_log_filters,json=networkPolicyLogFilters,proto3" json:"network_policy_log_filters,omitempty"`
+	NetworkPolicyL3Level       int32               `protobuf:"varint,12,opt,name=network_policy_l3_level,json=networkPolicyL3Level,proto3" json:"network_policy_l3_level,omitempty"`
+	NetworkPolicyL4Level       int32               `protobuf:"varint,13,opt,name=network_policy_l4_level,json=networkPolicyL4Level,proto3" json:"network_policy_l4_level,omitempty"`
+	NetworkPolicyL7Level       int32               `protobuf:"varint,14,opt,name=network_policy_l7_level,json=networkPolicyL7Level,proto3" json:"network_policy_l7_level,omitempty"`
+	NetworkPolicyFormats       []string            `protobuf:"bytes,15,rep,name=network_policy_formats,json=networkPolicyFormats,proto3" json:"network_policy_formats,omitempty"`
+	AdminPolicyMinNamespaces   int32               `protobuf:"varint,16,opt,name=admin_policy_min_namespaces,json=adminPolicyMinNamespaces,proto3" json:"admin_policy_min_namespaces,omitempty"`
+	NetworkPolicyCidrv6Bits    int32               `protobuf:"varint,17,opt,name=network_policy_cidrv6bits,json=networkPolicyCidrv6bits,proto3" json:"network_policy_cidrv6bits,omitempty"`
+	CidrAggregationMinIps      int32               `protobuf:"varint,18,opt,name=cidr_aggregation_min_ips,json=cidrAggregationMinIps,proto3" json:"cidr_aggregation_min_ips,omitempty"`
+	CidrAggregationMaxPrefix   int32               `protobuf:"varint,19,opt,name=cidr_aggregation_max_prefix,json=cidrAggregationMaxPrefix,proto3" json:"cidr_aggregation_max_prefix,omitempty"`
+	CidrAggregationMaxPrefixV6 int32               `protobuf:"varint,20,opt,name=cidr_aggregation_max_prefix_v6,json=cidrAggregationMaxPrefixV6,proto3" json:"cidr_aggregation_max_prefix_v6,omitempty"`
+	CidrProviderRangesFile     string              `protobuf:"bytes,21,opt,name=cidr_provider_ranges_file,json=cidrProviderRangesFile,proto3" json:"cidr_provider_ranges_file,omitempty"`
+	HttpOpenapiSpecs           []*HTTPOpenAPISpec  `protobuf:"bytes,22,rep,name=http_openapi_specs,json=httpOpenapiSpecs,proto3" json:"http_openapi_specs,omitempty"`
+	FqdnPatternMinNames        int32               `protobuf:"varint,23,opt,name=fqdn_pattern_min_names,json=fqdnPatternMinNames,proto3" json:"fqdn_pattern_min_names,omitempty"`
+	HttpRuleHost               bool                `protobuf:"varint,24,opt,name=http_rule_host,json=httpRuleHost,proto3" json:"http_rule_host,omitempty"`
+	HttpRuleHeaders            []string            `protobuf:"bytes,25,rep,name=http_rule_headers,json=httpRuleHeaders,proto3" json:"http_rule_headers,omitempty"`
+	HttpSkipErrors             bool                `protobuf:"varint,26,opt,name=http_skip_errors,json=httpSkipErrors,proto3" json:"http_skip_errors,omitempty"`
+	PortRangeMinPorts          int32               `protobuf:"varint,27,opt,name=port_range_min_ports,json=portRangeMinPorts,proto3" json:"port_range_min_ports,omitempty"`
+	PortRangeMaxGap            int32               `protobuf:"varint,28,opt,name=port_range_max_gap,json=portRangeMaxGap,proto3" json:"port_range_max_gap,omitempty"`
+	LabelPolicyInclude         []string            `protobuf:"bytes,29,rep,name=label_policy_include,json=labelPolicyInclude,proto3" json:"label_policy_include,omitempty"`
+	LabelPolicyExclude         []string            `protobuf:"bytes,30,rep,name=label_policy_exclude,json=labelPolicyExclude,proto3" json:"label_policy_exclude,omitempty"`
+	LabelPolicyPreferredKeys   []string            `protobuf:"bytes,31,rep,name=label_policy_preferred_keys,json=labelPolicyPreferredKeys,proto3" json:"label_policy_preferred_keys,omitempty"`
+	WorldCidrs                 bool                `protobuf:"varint,32,opt,name=world_cidrs,json=worldCidrs,proto3" json:"world_cidrs,omitempty"`
+	ClusterDomain              string              `protobuf:"bytes,33,opt,name=cluster_domain,json=clusterDomain,proto3" json:"cluster_domain,omitempty"`
 }
 
 func (x *ConfigNetworkPolicy) Reset() {
 	*x = ConfigNetworkPolicy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1_config_config_proto_msgTypes[6]
+		mi := &file_v1_config_config_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -459,7 +549,7 @@ func (x *ConfigNetworkPolicy) String() string {
 func (*ConfigNetworkPolicy) ProtoMessage() {}
 
 func (x *ConfigNetworkPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_config_config_proto_msgTypes[6]
+	mi := &file_v1_config_config_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -472,7 +562,7 @@ func (x *ConfigNetworkPolicy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConfigNetworkPolicy.ProtoReflect.Descriptor instead.
 func (*ConfigNetworkPolicy) Descriptor() ([]byte, []int) {
-	return file_v1_config_config_proto_rawDescGZIP(), []int{6}
+	return file_v1_config_config_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ConfigNetworkPolicy) GetOperationMode() int32 {
@@ -573,6 +663,139 @@ func (x *ConfigNetworkPolicy) GetNetworkPolicyL7Level() int32 {
 	return 0
 }
 
+func (x *ConfigNetworkPolicy) GetNetworkPolicyFormats() []string {
+	if x != nil {
+		return x.NetworkPolicyFormats
+	}
+	return nil
+}
+
+func (x *ConfigNetworkPolicy) GetAdminPolicyMinNamespaces() int32 {
+	if x != nil {
+		return x.AdminPolicyMinNamespaces
+	}
+	return 0
+}
+
+func (x *ConfigNetworkPolicy) GetNetworkPolicyCidrv6Bits() int32 {
+	if x != nil {
+		return x.NetworkPolicyCidrv6Bits
+	}
+	return 0
+}
+
+func (x *ConfigNetworkPolicy) GetCidrAggregationMinIps() int32 {
+	if x != nil {
+		return x.CidrAggregationMinIps
+	}
+	return 0
+}
+
+func (x *ConfigNetworkPolicy) GetCidrAggregationMaxPrefix() int32 {
+	if x != nil {
+		return x.CidrAggregationMaxPrefix
+	}
+	return 0
+}
+
+func (x *ConfigNetworkPolicy) GetCidrAggregationMaxPrefixV6() int32 {
+	if x != nil {
+		return x.CidrAggregationMaxPrefixV6
+	}
+	return 0
+}
+
+func (x *ConfigNetworkPolicy) GetCidrProviderRangesFile() string {
+	if x != nil {
+		return x.CidrProviderRangesFile
+	}
+	return ""
+}
+
+func (x *ConfigNetworkPolicy) GetHttpOpenapiSpecs() []*HTTPOpenAPISpec {
+	if x != nil {
+		return x.HttpOpenapiSpecs
+	}
+	return nil
+}
+
+func (x *ConfigNetworkPolicy) GetFqdnPatternMinNames() int32 {
+	if x != nil {
+		return x.FqdnPatternMinNames
+	}
+	return 0
+}
+
+func (x *ConfigNetworkPolicy) GetHttpRuleHost() bool {
+	if x != nil {
+		return x.HttpRuleHost
+	}
+	return false
+}
+
+func (x *ConfigNetworkPolicy) GetHttpRuleHeaders() []string {
+	if x != nil {
+		return x.HttpRuleHeaders
+	}
+	return nil
+}
+
+func (x *ConfigNetworkPolicy) GetHttpSkipErrors() bool {
+	if x != nil {
+		return x.HttpSkipErrors
+	}
+	return false
+}
+
+func (x *ConfigNetworkPolicy) GetPortRangeMinPorts() int32 {
+	if x != nil {
+		return x.PortRangeMinPorts
+	}
+	return 0
+}
+
+func (x *ConfigNetworkPolicy) GetPortRangeMaxGap() int32 {
+	if x != nil {
+		return x.PortRangeMaxGap
+	}
+	return 0
+}
+
+func (x *ConfigNetworkPolicy) GetLabelPolicyInclude() []string {
+	if x != nil {
+		return x.LabelPolicyInclude
+	}
+	return nil
+}
+
+func (x *ConfigNetworkPolicy) GetLabelPolicyExclude() []string {
+	if x != nil {
+		return x.LabelPolicyExclude
+	}
+	return nil
+}
+
+func (x *ConfigNetworkPolicy) GetLabelPolicyPreferredKeys() []string {
+	if x != nil {
+		return x.LabelPolicyPreferredKeys
+	}
+	return nil
+}
+
+func (x *ConfigNetworkPolicy) GetWorldCidrs() bool {
+	if x != nil {
+		return x.WorldCidrs
+	}
+	return false
+}
+
+func (x *ConfigNetworkPolicy) GetClusterDomain() string {
+	if x != nil {
+		return x.ClusterDomain
+	}
+	return ""
+}
+
 type SystemLogFilter struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -589,7 +812,7 @@ type SystemLogFilter struct {
 func (x *SystemLogFilter) Reset() {
 	*x = SystemLogFilter{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1_config_config_proto_msgTypes[7]
+		mi := &file_v1_config_config_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -602,7 +825,7 @@ func (x *SystemLogFilter) String() string {
 func (*SystemLogFilter) ProtoMessage() {}
 
 func (x *SystemLogFilter) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_config_config_proto_msgTypes[7]
+	mi := &file_v1_config_config_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -615,7 +838,7 @@ func (x *SystemLogFilter) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SystemLogFilter.ProtoReflect.Descriptor instead.
 func (*SystemLogFilter) Descriptor() ([]byte, []int) {
-	return file_v1_config_config_proto_rawDescGZIP(), []int{7}
+	return file_v1_config_config_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *SystemLogFilter) GetNamespace() string {
@@ -680,7 +903,7 @@ type ConfigSystemPolicy struct {
 func (x *ConfigSystemPolicy) Reset() {
 	*x = ConfigSystemPolicy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1_config_config_proto_msgTypes[8]
+		mi := &file_v1_config_config_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -693,7 +916,7 @@ func (x *ConfigSystemPolicy) String() string {
 func (*ConfigSystemPolicy) ProtoMessage() {}
 
 func (x *ConfigSystemPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_config_config_proto_msgTypes[8]
+	mi := &file_v1_config_config_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -706,7 +929,7 @@ func (x *ConfigSystemPolicy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConfigSystemPolicy.ProtoReflect.Descriptor instead.
 func (*ConfigSystemPolicy) Descriptor() ([]byte, []int) {
-	return file_v1_config_config_proto_rawDescGZIP(), []int{8}
+	return file_v1_config_config_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ConfigSystemPolicy) GetOperationMode() int32 {
@@ -791,7 +1014,7 @@ type ConfigClusterMgmt struct {
 func (x *ConfigClusterMgmt) Reset() {
 	*x = ConfigClusterMgmt{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1_config_config_proto_msgTypes[9]
+		mi := &file_v1_config_config_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -804,7 +1027,7 @@ func (x *ConfigClusterMgmt) String() string {
 func (*ConfigClusterMgmt) ProtoMessage() {}
 
 func (x *ConfigClusterMgmt) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_config_config_proto_msgTypes[9]
+	mi := &file_v1_config_config_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -817,7 +1040,7 @@ func (x *ConfigClusterMgmt) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConfigClusterMgmt.ProtoReflect.Descriptor instead.
 func (*ConfigClusterMgmt) Descriptor() ([]byte, []int) {
-	return file_v1_config_config_proto_rawDescGZIP(), []int{9}
+	return file_v1_config_config_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ConfigClusterMgmt) GetClusterInfoFrom() string {
@@ -852,7 +1075,7 @@ type Config struct {
 func (x *Config) Reset() {
 	*x = Config{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1_config_config_proto_msgTypes[10]
+		mi := &file_v1_config_config_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -865,7 +1088,7 @@ func (x *Config) String() string {
 func (*Config) ProtoMessage() {}
 
 func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_config_config_proto_msgTypes[10]
+	mi := &file_v1_config_config_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -878,7 +1101,7 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Config.ProtoReflect.Descriptor instead.
 func (*Config) Descriptor() ([]byte, []int) {
-	return file_v1_config_config_proto_rawDescGZIP(), []int{10}
+	return file_v1_config_config_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *Config) GetConfigName() string {
@@ -995,167 +1218,237 @@ var file_v1_config_config_proto_rawDesc = []byte{
 	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x1f,
 	0x0a, 0x0b, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x06, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x6f, 0x72, 0x74, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22,
-	0x80, 0x06, 0x0a, 0x13, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72,
-	0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x6f, 0x70, 0x65, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x0d, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x32,
-	0x0a, 0x15, 0x63, 0x72, 0x6f, 0x6e, 0x6a, 0x6f, 0x62, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x69,
-	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x63,
-	0x72, 0x6f, 0x6e, 0x6a, 0x6f, 0x62, 0x54, 0x69, 0x6d, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76,
-	0x61, 0x6c, 0x12, 0x3c, 0x0a, 0x1b, 0x6f, 0x6e, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6a,
-	0x6f, 0x62, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x17, 0x6f, 0x6e, 0x65, 0x54, 0x69, 0x6d, 0x65,
-	0x4a, 0x6f, 0x62, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x28, 0x0a, 0x10, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6c, 0x6f, 0x67, 0x5f,
-	0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6e, 0x65, 0x74, 0x77,
-	0x6f, 0x72, 0x6b, 0x4c, 0x6f, 0x67, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x28, 0x0a, 0x10, 0x6e, 0x65,
-	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4c, 0x6f, 0x67,
-	0x46, 0x69, 0x6c, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f,
-	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x74, 0x6f, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x54, 0x6f,
-	0x12, 0x2c, 0x0a, 0x12, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69,
-	0x63, 0x79, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x6e, 0x65,
-	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x44, 0x69, 0x72, 0x12, 0x30,
-	0x0a, 0x14, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79,
-	0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x12, 0x6e, 0x65,
-	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x54, 0x79, 0x70, 0x65, 0x73,
-	0x12, 0x39, 0x0a, 0x19, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69,
-	0x63, 0x79, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x09, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x16, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69,
-	0x63, 0x79, 0x52, 0x75, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x17, 0x6e,
-	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x63, 0x69,
-	0x64, 0x72, 0x62, 0x69, 0x74, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x15, 0x6e, 0x65,
-	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x43, 0x69, 0x64, 0x72, 0x62,
-	0x69, 0x74, 0x73, 0x12, 0x58, 0x0a, 0x1a, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70,
-	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
-	0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4c, 0x6f, 0x67, 0x46, 0x69,
-	0x6c, 0x74, 0x65, 0x72, 0x52, 0x17, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c,
-	0x69, 0x63, 0x79, 0x4c, 0x6f, 0x67, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x12, 0x35, 0x0a,
-	0x17, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f,
-	0x6c, 0x33, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x14,
-	0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x4c, 0x33, 0x4c,
-	0x65, 0x76, 0x65, 0x6c, 0x12, 0x35, 0x0a, 0x17, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f,
-	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x6c, 0x34, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18,
-	0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x14, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f,
-	0x6c, 0x69, 0x63, 0x79, 0x4c, 0x34, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x35, 0x0a, 0x17, 0x6e,
-	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x6c, 0x37,
-	0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x05, 0x52, 0x14, 0x6e, 0x65,
-	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x4c, 0x37, 0x4c, 0x65, 0x76,
-	0x65, 0x6c, 0x22, 0xd3, 0x01, 0x0a, 0x0f, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x4c, 0x6f, 0x67,
-	0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70,
-	0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73,
-	0x70, 0x61, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x02,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x21, 0x0a, 0x0c,
-	0x66, 0x69, 0x6c, 0x65, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x0b, 0x66, 0x69, 0x6c, 0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x73, 0x12,
-	0x27, 0x0a, 0x0f, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61,
-	0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73,
-	0x73, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65,
-	0x5f, 0x64, 0x69, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c,
-	0x65, 0x44, 0x69, 0x72, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
-	0x5f, 0x64, 0x69, 0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f,
-	0x63, 0x65, 0x73, 0x73, 0x44, 0x69, 0x72, 0x73, 0x22, 0xb0, 0x04, 0x0a, 0x12, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12,
-	0x25, 0x0a, 0x0e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x6f, 0x64,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x32, 0x0a, 0x15, 0x63, 0x72, 0x6f, 0x6e, 0x6a, 0x6f,
-	0x62, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x63, 0x72, 0x6f, 0x6e, 0x6a, 0x6f, 0x62, 0x54, 0x69,
-	0x6d, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x3c, 0x0a, 0x1b, 0x6f, 0x6e,
-	0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6a, 0x6f, 0x62, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f,
-	0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x17, 0x6f, 0x6e, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x4a, 0x6f, 0x62, 0x54, 0x69, 0x6d, 0x65, 0x53,
-	0x65, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x79, 0x73, 0x74,
-	0x65, 0x6d, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0d, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x4c, 0x6f, 0x67, 0x46, 0x72, 0x6f, 0x6d,
-	0x12, 0x26, 0x0a, 0x0f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x66,
-	0x69, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x79, 0x73, 0x74, 0x65,
-	0x6d, 0x4c, 0x6f, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x73, 0x79, 0x73, 0x74,
-	0x65, 0x6d, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x74, 0x6f, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0e, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
-	0x54, 0x6f, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x70, 0x6f, 0x6c,
-	0x69, 0x63, 0x79, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73,
-	0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x44, 0x69, 0x72, 0x12, 0x55,
-	0x0a, 0x19, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f,
-	0x6c, 0x6f, 0x67, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x1a, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x53, 0x79,
-	0x73, 0x74, 0x65, 0x6d, 0x4c, 0x6f, 0x67, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x16, 0x73,
-	0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x4c, 0x6f, 0x67, 0x46, 0x69,
-	0x6c, 0x74, 0x65, 0x72, 0x73, 0x12, 0x41, 0x0a, 0x1d, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f,
-	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x70, 0x72, 0x6f, 0x63, 0x5f, 0x66, 0x72, 0x6f, 0x6d,
-	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1a, 0x73, 0x79,
-	0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x50, 0x72, 0x6f, 0x63, 0x46, 0x72,
-	0x6f, 0x6d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x41, 0x0a, 0x1d, 0x73, 0x79, 0x73, 0x74,
-	0x65, 0x6d, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x66,
-	0x72, 0x6f, 0x6d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x1a, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x46, 0x69, 0x6c,
-	0x65, 0x46, 0x72, 0x6f, 0x6d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x69, 0x0a, 0x11, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x67, 0x6d, 0x74,
-	0x12, 0x2a, 0x0a, 0x11, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x66, 0x6f,
-	0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6c, 0x75,
-	0x73, 0x74, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x28, 0x0a, 0x10,
-	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x6d, 0x67, 0x6d, 0x74, 0x5f, 0x75, 0x72, 0x6c,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4d,
-	0x67, 0x6d, 0x74, 0x55, 0x72, 0x6c, 0x22, 0x8e, 0x04, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4e, 0x61,
-	0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x30, 0x0a, 0x09, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x5f, 0x64, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x75, 0x0a, 0x0f, 0x48, 0x54, 0x54, 0x50, 0x4f, 0x70, 0x65, 0x6e, 0x41, 0x50, 0x49, 0x53, 0x70,
+	0x65, 0x63, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x22, 0xec, 0x0d, 0x0a, 0x13, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x25,
+	0x0a, 0x0e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x6f, 0x64, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x32, 0x0a, 0x15, 0x63, 0x72, 0x6f, 0x6e, 0x6a, 0x6f, 0x62,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x63, 0x72, 0x6f, 0x6e, 0x6a, 0x6f, 0x62, 0x54, 0x69, 0x6d,
+	0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x3c, 0x0a, 0x1b, 0x6f, 0x6e, 0x65,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6a, 0x6f, 0x62, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x73,
+	0x65, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x17,
+	0x6f, 0x6e, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x4a, 0x6f, 0x62, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x65,
+	0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x10, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4c, 0x6f, 0x67, 0x46, 0x72, 0x6f,
+	0x6d, 0x12, 0x28, 0x0a, 0x10, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6c, 0x6f, 0x67,
+	0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x4c, 0x6f, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x6e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x74, 0x6f,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x54, 0x6f, 0x12, 0x2c, 0x0a, 0x12, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x10, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x44, 0x69, 0x72, 0x12, 0x30, 0x0a, 0x14, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x12, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x19, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x16, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x75, 0x6c, 0x65, 0x54, 0x79, 0x70,
+	0x65, 0x73, 0x12, 0x36, 0x0a, 0x17, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x5f, 0x63, 0x69, 0x64, 0x72, 0x62, 0x69, 0x74, 0x73, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x15, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x43, 0x69, 0x64, 0x72, 0x62, 0x69, 0x74, 0x73, 0x12, 0x58, 0x0a, 0x1a, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x6c, 0x6f, 0x67,
+	0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b,
+	0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x4c, 0x6f, 0x67, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x17, 0x6e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x4c, 0x6f, 0x67, 0x46, 0x69, 0x6c,
+	0x74, 0x65, 0x72, 0x73, 0x12, 0x35, 0x0a, 0x17, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f,
+	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x6c, 0x33, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x14, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x4c, 0x33, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x35, 0x0a, 0x17, 0x6e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x6c, 0x34,
+	0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x14, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x4c, 0x34, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x12, 0x35, 0x0a, 0x17, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x5f, 0x6c, 0x37, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x14, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x4c, 0x37, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x34, 0x0a, 0x16, 0x6e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x66, 0x6f, 0x72, 0x6d,
+	0x61, 0x74, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x09, 0x52, 0x14, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x73, 0x12,
+	0x3d, 0x0a, 0x1b, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f,
+	0x6d, 0x69, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x18, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x4d, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x3a,
+	0x0a, 0x19, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x5f, 0x63, 0x69, 0x64, 0x72, 0x76, 0x36, 0x62, 0x69, 0x74, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x17, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x43, 0x69, 0x64, 0x72, 0x76, 0x36, 0x62, 0x69, 0x74, 0x73, 0x12, 0x37, 0x0a, 0x18, 0x63, 0x69,
+	0x64, 0x72, 0x5f, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d,
+	0x69, 0x6e, 0x5f, 0x69, 0x70, 0x73, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x52, 0x15, 0x63, 0x69,
+	0x64, 0x72, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x69, 0x6e,
+	0x49, 0x70, 0x73, 0x12, 0x3d, 0x0a, 0x1b, 0x63, 0x69, 0x64, 0x72, 0x5f, 0x61, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x72, 0x65, 0x66,
+	0x69, 0x78, 0x18, 0x13, 0x20, 0x01, 0x28, 0x05, 0x52, 0x18, 0x63, 0x69, 0x64, 0x72, 0x41, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x61, 0x78, 0x50, 0x72, 0x65, 0x66,
+	0x69, 0x78, 0x12, 0x42, 0x0a, 0x1e, 0x63, 0x69, 0x64, 0x72, 0x5f, 0x61, 0x67, 0x67, 0x72, 0x65,
+	0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x5f, 0x76, 0x36, 0x18, 0x14, 0x20, 0x01, 0x28, 0x05, 0x52, 0x1a, 0x63, 0x69, 0x64, 0x72,
+	0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x61, 0x78, 0x50, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x56, 0x36, 0x12, 0x39, 0x0a, 0x19, 0x63, 0x69, 0x64, 0x72, 0x5f, 0x70,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x5f, 0x66,
+	0x69, 0x6c, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x63, 0x69, 0x64, 0x72, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x46, 0x69, 0x6c,
+	0x65, 0x12, 0x48, 0x0a, 0x12, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x6f, 0x70, 0x65, 0x6e, 0x61, 0x70,
+	0x69, 0x5f, 0x73, 0x70, 0x65, 0x63, 0x73, 0x18, 0x16, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x48, 0x54, 0x54, 0x50, 0x4f, 0x70,
+	0x65, 0x6e, 0x41, 0x50, 0x49, 0x53, 0x70, 0x65, 0x63, 0x52, 0x10, 0x68, 0x74, 0x74, 0x70, 0x4f,
+	0x70, 0x65, 0x6e, 0x61, 0x70, 0x69, 0x53, 0x70, 0x65, 0x63, 0x73, 0x12, 0x33, 0x0a, 0x16, 0x66,
+	0x71, 0x64, 0x6e, 0x5f, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x5f, 0x6d, 0x69, 0x6e, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x17, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x66, 0x71, 0x64,
+	0x6e, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x4d, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x73,
+	0x12, 0x24, 0x0a, 0x0e, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x68, 0x6f,
+	0x73, 0x74, 0x18, 0x18, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x68, 0x74, 0x74, 0x70, 0x52, 0x75,
+	0x6c, 0x65, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x72,
+	0x75, 0x6c, 0x65, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x19, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0f, 0x68, 0x74, 0x74, 0x70, 0x52, 0x75, 0x6c, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x73, 0x6b, 0x69, 0x70, 0x5f,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x68, 0x74,
+	0x74, 0x70, 0x53, 0x6b, 0x69, 0x70, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x2f, 0x0a, 0x14,
+	0x70, 0x6f, 0x72, 0x74, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x70,
+	0x6f, 0x72, 0x74, 0x73, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x70, 0x6f, 0x72, 0x74,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x4d, 0x69, 0x6e, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x2b, 0x0a,
+	0x12, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x6d, 0x61, 0x78, 0x5f,
+	0x67, 0x61, 0x70, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x70, 0x6f, 0x72, 0x74, 0x52,
+	0x61, 0x6e, 0x67, 0x65, 0x4d, 0x61, 0x78, 0x47, 0x61, 0x70, 0x12, 0x30, 0x0a, 0x14, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x18, 0x1d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x12, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x12, 0x30, 0x0a, 0x14,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x65, 0x78, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x18, 0x1e, 0x20, 0x03, 0x28, 0x09, 0x52, 0x12, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x12, 0x3d,
+	0x0a, 0x1b, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x70,
+	0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x1f, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x18, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x1f, 0x0a,
+	0x0b, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x5f, 0x63, 0x69, 0x64, 0x72, 0x73, 0x18, 0x20, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x43, 0x69, 0x64, 0x72, 0x73, 0x12, 0x25,
+	0x0a, 0x0e, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x18, 0x21, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x44,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x22, 0xd3, 0x01, 0x0a, 0x0f, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x4c, 0x6f, 0x67, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d,
+	0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61,
+	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12,
+	0x21, 0x0a, 0x0c, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x66, 0x69, 0x6c, 0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61,
+	0x74, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x66, 0x6f,
+	0x72, 0x6d, 0x61, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x72, 0x6f,
+	0x63, 0x65, 0x73, 0x73, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x66,
+	0x69, 0x6c, 0x65, 0x5f, 0x64, 0x69, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08,
+	0x66, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x72, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x5f, 0x64, 0x69, 0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b,
+	0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x44, 0x69, 0x72, 0x73, 0x22, 0xb0, 0x04, 0x0a, 0x12,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x6f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x32, 0x0a, 0x15, 0x63, 0x72, 0x6f,
+	0x6e, 0x6a, 0x6f, 0x62, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x63, 0x72, 0x6f, 0x6e, 0x6a, 0x6f,
+	0x62, 0x54, 0x69, 0x6d, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x3c, 0x0a,
+	0x1b, 0x6f, 0x6e, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6a, 0x6f, 0x62, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x5f, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x17, 0x6f, 0x6e, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x4a, 0x6f, 0x62, 0x54, 0x69,
+	0x6d, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x0f, 0x73,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x4c, 0x6f, 0x67, 0x46,
+	0x72, 0x6f, 0x6d, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x6c, 0x6f,
+	0x67, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x4c, 0x6f, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x73,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x74, 0x6f, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x54, 0x6f, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f,
+	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x44, 0x69,
+	0x72, 0x12, 0x55, 0x0a, 0x19, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x70, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x08,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x4c, 0x6f, 0x67, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x52, 0x16, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x4c, 0x6f,
+	0x67, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x12, 0x41, 0x0a, 0x1d, 0x73, 0x79, 0x73, 0x74,
+	0x65, 0x6d, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x70, 0x72, 0x6f, 0x63, 0x5f, 0x66,
+	0x72, 0x6f, 0x6d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x1a, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x50, 0x72, 0x6f,
+	0x63, 0x46, 0x72, 0x6f, 0x6d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x41, 0x0a, 0x1d, 0x73,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x66, 0x69, 0x6c,
+	0x65, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x1a, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x46, 0x69, 0x6c, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x69,
+	0x0a, 0x11, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4d,
+	0x67, 0x6d, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x69,
+	0x6e, 0x66, 0x6f, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f,
+	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x46, 0x72, 0x6f, 0x6d, 0x12,
+	0x28, 0x0a, 0x10, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x6d, 0x67, 0x6d, 0x74, 0x5f,
+	0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6c, 0x75, 0x73, 0x74,
+	0x65, 0x72, 0x4d, 0x67, 0x6d, 0x74, 0x55, 0x72, 0x6c, 0x22, 0x8e, 0x04, 0x0a, 0x06, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x30, 0x0a,
+	0x09, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x64, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x44, 0x42, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x44, 0x62, 0x12,
+	0x4f, 0x0a, 0x14, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x63, 0x69, 0x6c, 0x69, 0x75, 0x6d,
+	0x5f, 0x68, 0x75, 0x62, 0x62, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
 	0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x44, 0x42, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x44, 0x62, 0x12, 0x4f, 0x0a, 0x14,
-	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x63, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x5f, 0x68, 0x75,
-	0x62, 0x62, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x31, 0x2e,
-	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x43, 0x69, 0x6c,
-	0x69, 0x75, 0x6d, 0x48, 0x75, 0x62, 0x62, 0x6c, 0x65, 0x52, 0x12, 0x63, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x43, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x48, 0x75, 0x62, 0x62, 0x6c, 0x65, 0x12, 0x52, 0x0a,
-	0x15, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f,
-	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x76,
-	0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4e,
-	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x13, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63,
-	0x79, 0x12, 0x4f, 0x0a, 0x14, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x73, 0x79, 0x73, 0x74,
-	0x65, 0x6d, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1d, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x12,
-	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69,
-	0x63, 0x79, 0x12, 0x4c, 0x0a, 0x13, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x63, 0x6c, 0x75,
-	0x73, 0x74, 0x65, 0x72, 0x5f, 0x6d, 0x67, 0x6d, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1c, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x67, 0x6d, 0x74, 0x52, 0x11, 0x63,
+	0x43, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x48, 0x75, 0x62, 0x62, 0x6c, 0x65, 0x52, 0x12, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x43, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x48, 0x75, 0x62, 0x62, 0x6c, 0x65,
+	0x12, 0x52, 0x0a, 0x15, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1e, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52,
+	0x13, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x12, 0x4f, 0x0a, 0x14, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x73,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x52, 0x12, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x4c, 0x0a, 0x13, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f,
+	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x6d, 0x67, 0x6d, 0x74, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43,
 	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x67, 0x6d, 0x74,
-	0x12, 0x55, 0x0a, 0x16, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x6b, 0x75, 0x62, 0x65, 0x61,
-	0x72, 0x6d, 0x6f, 0x72, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1f, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x4b, 0x75, 0x62, 0x65, 0x41, 0x72, 0x6d, 0x6f, 0x72, 0x52, 0x65, 0x6c, 0x61,
-	0x79, 0x52, 0x14, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4b, 0x75, 0x62, 0x65, 0x61, 0x72, 0x6d,
-	0x6f, 0x72, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x32, 0xc1, 0x02, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x3a, 0x0a, 0x03, 0x41, 0x64, 0x64, 0x12, 0x18,
-	0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e,
-	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x3d, 0x0a, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x63,
+	0x52, 0x11, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4d,
+	0x67, 0x6d, 0x74, 0x12, 0x55, 0x0a, 0x16, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x6b, 0x75,
+	0x62, 0x65, 0x61, 0x72, 0x6d, 0x6f, 0x72, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4b, 0x75, 0x62, 0x65, 0x41, 0x72, 0x6d, 0x6f, 0x72, 0x52,
+	0x65, 0x6c, 0x61, 0x79, 0x52, 0x14, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4b, 0x75, 0x62, 0x65,
+	0x61, 0x72, 0x6d, 0x6f, 0x72, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x32, 0xc1, 0x02, 0x0a, 0x0b, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x3a, 0x0a, 0x03, 0x41, 0x64,
+	0x64, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x76, 0x31,
+	0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x18, 0x2e,
+	0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x18, 0x2e, 0x76,
+	0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x3d, 0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x18, 0x2e, 0x76, 0x31,
+	0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3c, 0x0a, 0x05, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x63,
 	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75,
 	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d,
-	0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a,
-	0x05, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x19, 0x2e, 0x76, 0x31, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x35, 0x5a, 0x33, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x63, 0x63, 0x75, 0x6b, 0x6e,
-	0x6f, 0x78, 0x2f, 0x6b, 0x6e, 0x6f, 0x78, 0x41, 0x75, 0x74, 0x6f, 0x50, 0x6f, 0x6c, 0x69, 0x63,
-	0x79, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x35,
+	0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x63, 0x63,
+	0x75, 0x6b, 0x6e, 0x6f, 0x78, 0x2f, 0x6b, 0x6e, 0x6f, 0x78, 0x41, 0x75, 0x74, 0x6f, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1170,7 +1463,7 @@ func file_v1_config_config_proto_rawDescGZIP() []byte {
 	return file_v1_config_config_proto_rawDescData
 }
 
-var file_v1_config_config_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_v1_config_config_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_v1_config_config_proto_goTypes = []interface{}{
 	(*ConfigRequest)(nil),        // 0: v1.config.ConfigRequest
 	(*ConfigResponse)(nil),       // 1: v1.config.ConfigResponse
@@ -1178,38 +1471,40 @@ var file_v1_config_config_proto_goTypes = []interface{}{
 	(*ConfigCiliumHubble)(nil),   // 3: v1.config.ConfigCiliumHubble
 	(*ConfigKubeArmorRelay)(nil), // 4: v1.config.ConfigKubeArmorRelay
 	(*NetworkLogFilter)(nil),     // 5: v1.config.NetworkLogFilter
-	(*ConfigNetworkPolicy)(nil),  // 6: v1.config.ConfigNetworkPolicy
-	(*SystemLogFilter)(nil),      // 7: v1.config.SystemLogFilter
-	(*ConfigSystemPolicy)(nil),   // 8: v1.config.ConfigSystemPolicy
-	(*ConfigClusterMgmt)(nil),    // 9: v1.config.ConfigClusterMgmt
-	(*Config)(nil),               // 10: v1.config.Config
+	(*HTTPOpenAPISpec)(nil),      // 6: v1.config.HTTPOpenAPISpec
+	(*ConfigNetworkPolicy)(nil),  // 7: v1.config.ConfigNetworkPolicy
+	(*SystemLogFilter)(nil),      // 8: v1.config.SystemLogFilter
+	(*ConfigSystemPolicy)(nil),   // 9: v1.config.ConfigSystemPolicy
+	(*ConfigClusterMgmt)(nil),    // 10: v1.config.ConfigClusterMgmt
+	(*Config)(nil),               // 11: v1.config.Config
 }
 var file_v1_config_config_proto_depIdxs = []int32{
-	10, // 0: v1.config.ConfigRequest.config:type_name -> v1.config.Config
-	10, // 1: v1.config.ConfigResponse.config:type_name -> v1.config.Config
+	11, // 0: v1.config.ConfigRequest.config:type_name -> v1.config.Config
+	11, // 1: v1.config.ConfigResponse.config:type_name -> v1.config.Config
 	5,  // 2: v1.config.ConfigNetworkPolicy.network_policy_log_filters:type_name -> v1.config.NetworkLogFilter
-	7,  // 3: v1.config.ConfigSystemPolicy.system_policy_log_filters:type_name -> v1.config.SystemLogFilter
-	2,  // 4: v1.config.Config.config_db:type_name -> v1.config.ConfigDB
-	3,  // 5: v1.config.Config.config_cilium_hubble:type_name -> v1.config.ConfigCiliumHubble
-	6,  // 6: v1.config.Config.config_network_policy:type_name -> v1.config.ConfigNetworkPolicy
-	8,  // 7: v1.config.Config.config_system_policy:type_name -> v1.config.ConfigSystemPolicy
-	9,  // 8: v1.config.Config.config_cluster_mgmt:type_name -> v1.config.ConfigClusterMgmt
-	4,  // 9: v1.config.Config.config_kubearmor_relay:type_name -> v1.config.ConfigKubeArmorRelay
-	0,  // 10: v1.config.ConfigStore.Add:input_type -> v1.config.ConfigRequest
-	0,  // 11: v1.config.ConfigStore.Get:input_type -> v1.config.ConfigRequest
-	0,  // 12: v1.config.ConfigStore.Update:input_type -> v1.config.ConfigRequest
-	0,  // 13: v1.config.ConfigStore.Delete:input_type -> v1.config.ConfigRequest
-	0,  // 14: v1.config.ConfigStore.Apply:input_type -> v1.config.ConfigRequest
-	1,  // 15: v1.config.ConfigStore.Add:output_type -> v1.config.ConfigResponse
-	1,  // 16: v1.config.ConfigStore.Get:output_type -> v1.config.ConfigResponse
-	1,  // 17: v1.config.ConfigStore.Update:output_type -> v1.config.ConfigResponse
-	1,  // 18: v1.config.ConfigStore.Delete:output_type -> v1.config.ConfigResponse
-	1,  // 19: v1.config.ConfigStore.Apply:output_type -> v1.config.ConfigResponse
-	15, // [15:20] is the sub-list for method output_type
-	10, // [10:15] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	6,  // 3: v1.config.ConfigNetworkPolicy.http_openapi_specs:type_name -> v1.config.HTTPOpenAPISpec
+	8,  // 4: v1.config.ConfigSystemPolicy.system_policy_log_filters:type_name -> v1.config.SystemLogFilter
+	2,  // 5: v1.config.Config.config_db:type_name -> v1.config.ConfigDB
+	3,  // 6: v1.config.Config.config_cilium_hubble:type_name -> v1.config.ConfigCiliumHubble
+	7,  // 7: v1.config.Config.config_network_policy:type_name -> v1.config.ConfigNetworkPolicy
+	9,  // 8: v1.config.Config.config_system_policy:type_name -> v1.config.ConfigSystemPolicy
+	10, // 9: v1.config.Config.config_cluster_mgmt:type_name -> v1.config.ConfigClusterMgmt
+	4,  // 10: v1.config.Config.config_kubearmor_relay:type_name -> v1.config.ConfigKubeArmorRelay
+	0,  // 11: v1.config.ConfigStore.Add:input_type -> v1.config.ConfigRequest
+	0,  // 12: v1.config.ConfigStore.Get:input_type -> v1.config.ConfigRequest
+	0,  // 13: v1.config.ConfigStore.Update:input_type -> v1.config.ConfigRequest
+	0,  // 14: v1.config.ConfigStore.Delete:input_type -> v1.config.ConfigRequest
+	0,  // 15: v1.config.ConfigStore.Apply:input_type -> v1.config.ConfigRequest
+	1,  // 16: v1.config.ConfigStore.Add:output_type -> v1.config.ConfigResponse
+	1,  // 17: v1.config.ConfigStore.Get:output_type -> v1.config.ConfigResponse
+	1,  // 18: v1.config.ConfigStore.Update:output_type -> v1.config.ConfigResponse
+	1,  // 19: v1.config.ConfigStore.Delete:output_type -> v1.config.ConfigResponse
+	1,  // 20: v1.config.ConfigStore.Apply:output_type -> v1.config.ConfigResponse
+	16, // [16:21] is the sub-list for method output_type
+	11, // [11:16] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_v1_config_config_proto_init() }
@@ -1291,7 +1586,7 @@ func file_v1_config_config_proto_init() {
 			}
 		}
 		file_v1_config_config_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ConfigNetworkPolicy); i {
+			switch v := v.(*HTTPOpenAPISpec); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1303,7 +1598,7 @@ func file_v1_config_config_proto_init() {
 			}
 		}
 		file_v1_config_config_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SystemLogFilter); i {
+			switch v := v.(*ConfigNetworkPolicy); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1315,7 +1610,7 @@ func file_v1_config_config_proto_init() {
 			}
 		}
 		file_v1_config_config_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ConfigSystemPolicy); i {
+			switch v := v.(*SystemLogFilter); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1327,7 +1622,7 @@ func file_v1_config_config_proto_init() {
 			}
 		}
 		file_v1_config_config_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ConfigClusterMgmt); i {
+			switch v := v.(*ConfigSystemPolicy); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1339,6 +1634,18 @@ func file_v1_config_config_proto_init() {
 			}
 		}
 		file_v1_config_config_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfigClusterMgmt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_config_config_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Config); i {
 			case 0:
 				return &v.state
@@ -1357,7 +1664,7 @@ func file_v1_config_config_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_v1_config_config_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
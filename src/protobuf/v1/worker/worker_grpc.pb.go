@@ -26,6 +26,8 @@ type WorkerClient interface {
 	Start(ctx context.Context, in *WorkerRequest, opts ...grpc.CallOption) (*WorkerResponse, error)
 	Stop(ctx context.Context, in *WorkerRequest, opts ...grpc.CallOption) (*WorkerResponse, error)
 	Convert(ctx context.Context, in *WorkerRequest, opts ...grpc.CallOption) (*WorkerResponse, error)
+	ListRuns(ctx context.Context, in *ListRunsRequest, opts ...grpc.CallOption) (*ListRunsResponse, error)
+	GetRun(ctx context.Context, in *GetRunRequest, opts ...grpc.CallOption) (*DiscoveryRun, error)
 }
 
 type workerClient struct {
@@ -72,6 +74,24 @@ func (c *workerClient) Convert(ctx context.Context, in *WorkerRequest, opts ...g
 	return out, nil
 }
 
+func (c *workerClient) ListRuns(ctx context.Context, in *ListRunsRequest, opts ...grpc.CallOption) (*ListRunsResponse, error) {
+	out := new(ListRunsResponse)
+	err := c.cc.Invoke(ctx, "/v1.worker.Worker/ListRuns", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetRun(ctx context.Context, in *GetRunRequest, opts ...grpc.CallOption) (*DiscoveryRun, error) {
+	out := new(DiscoveryRun)
+	err := c.cc.Invoke(ctx, "/v1.worker.Worker/GetRun", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkerServer is the server API for Worker service.
 // All implementations must embed UnimplementedWorkerServer
 // for forward compatibility
@@ -80,6 +100,8 @@ type WorkerServer interface {
 	Start(context.Context, *WorkerRequest) (*WorkerResponse, error)
 	Stop(context.Context, *WorkerRequest) (*WorkerResponse, error)
 	Convert(context.Context, *WorkerRequest) (*WorkerResponse, error)
+	ListRuns(context.Context, *ListRunsRequest) (*ListRunsResponse, error)
+	GetRun(context.Context, *GetRunRequest) (*DiscoveryRun, error)
 	mustEmbedUnimplementedWorkerServer()
 }
 
@@ -99,6 +121,12 @@ func (UnimplementedWorkerServer) Stop(context.Context, *WorkerRequest) (*WorkerR
 func (UnimplementedWorkerServer) Convert(context.Context, *WorkerRequest) (*WorkerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Convert not implemented")
 }
+func (UnimplementedWorkerServer) ListRuns(context.Context, *ListRunsRequest) (*ListRunsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRuns not implemented")
+}
+func (UnimplementedWorkerServer) GetRun(context.Context, *GetRunRequest) (*DiscoveryRun, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRun not implemented")
+}
 func (UnimplementedWorkerServer) mustEmbedUnimplementedWorkerServer() {}
 
 // UnsafeWorkerServer may be embedded to opt out of forward compatibility for this service.
@@ -184,6 +212,42 @@ func _Worker_Convert_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Worker_ListRuns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRunsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).ListRuns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.worker.Worker/ListRuns",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).ListRuns(ctx, req.(*ListRunsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_GetRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.worker.Worker/GetRun",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetRun(ctx, req.(*GetRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Worker_ServiceDesc is the grpc.ServiceDesc for Worker service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -207,6 +271,14 @@ var Worker_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Convert",
 			Handler:    _Worker_Convert_Handler,
 		},
+		{
+			MethodName: "ListRuns",
+			Handler:    _Worker_ListRuns_Handler,
+		},
+		{
+			MethodName: "GetRun",
+			Handler:    _Worker_GetRun_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "v1/worker/worker.proto",
@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	ipb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/insight"
+	wpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/worker"
+)
+
+// ==================== //
+// == Authz Roles    == //
+// ==================== //
+
+const (
+	RoleNone     = "none" // no authentication required
+	RoleReadOnly = "read-only"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+var roleLevel = map[string]int{
+	RoleNone:     0,
+	RoleReadOnly: 1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// defaultPermissions is the minimum role of each RPC, the methods not listed here require the admin role
+var defaultPermissions = map[string]string{
+	"/grpc.health.v1.Health/Check": RoleNone,
+	"/grpc.health.v1.Health/Watch": RoleNone,
+
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": RoleReadOnly,
+
+	"/v1.worker.Worker/GetWorkerStatus": RoleReadOnly,
+	"/v1.worker.Worker/Convert":         RoleReadOnly,
+	"/v1.worker.Worker/ListRuns":        RoleReadOnly,
+	"/v1.worker.Worker/GetRun":          RoleReadOnly,
+	"/v1.worker.Worker/Start":           RoleOperator,
+	"/v1.worker.Worker/Stop":            RoleOperator,
+
+	"/v1.consumer.Consumer/GetConsumerStatus": RoleReadOnly,
+	"/v1.consumer.Consumer/Start":             RoleOperator,
+	"/v1.consumer.Consumer/Stop":              RoleOperator,
+
+	"/v1.analyzer.Analyzer/GetNetworkPolicies": RoleReadOnly,
+	"/v1.analyzer.Analyzer/GetSystemPolicies":  RoleReadOnly,
+
+	"/v1.insight.Insight/GetInsightData": RoleReadOnly,
+
+	"/v1.observability.Observability/Summary":     RoleReadOnly,
+	"/v1.observability.Observability/GetPodNames": RoleReadOnly,
+
+	"/v1.discovery.Discovery/GetPolicy": RoleReadOnly,
+
+	"/v1.publisher.Publisher/GetSummary": RoleReadOnly,
+
+	"/v1.config.ConfigStore/Get":    RoleReadOnly,
+	"/v1.config.ConfigStore/Add":    RoleAdmin,
+	"/v1.config.ConfigStore/Update": RoleAdmin,
+	"/v1.config.ConfigStore/Delete": RoleAdmin,
+	"/v1.config.ConfigStore/Apply":  RoleAdmin,
+}
+
+// ===================== //
+// == Authz Validator == //
+// ===================== //
+
+type authorizer struct {
+	identities  []types.AuthzIdentity
+	permissions map[string]string
+}
+
+func newAuthorizer(cfg types.ConfigAuthz) *authorizer {
+	permissions := map[string]string{}
+	for method, role := range defaultPermissions {
+		permissions[method] = role
+	}
+	for _, perm := range cfg.Permissions {
+		if _, ok := roleLevel[perm.Role]; !ok {
+			log.Warn().Msgf("Ignoring the permission of [%s], unknown role [%s]", perm.Method, perm.Role)
+			continue
+		}
+		permissions[perm.Method] = perm.Role
+	}
+
+	return &authorizer{
+		identities:  cfg.Identities,
+		permissions: permissions,
+	}
+}
+
+// isDestructiveRequest checks whether the request wipes data from the db
+func isDestructiveRequest(req interface{}) bool {
+	switch r := req.(type) {
+	case *wpb.WorkerRequest:
+		return r.GetReq() == "dbclear"
+	case *ipb.Request:
+		return r.GetRequest() == "dbclear"
+	}
+	return false
+}
+
+func (a *authorizer) requiredRole(fullMethod string, req interface{}) string {
+	role, ok := a.permissions[fullMethod]
+	if !ok {
+		role = RoleAdmin
+	}
+
+	if isDestructiveRequest(req) {
+		role = RoleAdmin
+	}
+
+	return role
+}
+
+// authenticate finds the identity of the caller from the bearer token or the verified client certificate
+func (a *authorizer) authenticate(ctx context.Context) (*types.AuthzIdentity, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, value := range md.Get("authorization") {
+			token := strings.TrimSpace(strings.TrimPrefix(value, "Bearer "))
+			for i := range a.identities {
+				if a.identities[i].Token != "" &&
+					subtle.ConstantTimeCompare([]byte(a.identities[i].Token), []byte(token)) == 1 {
+					return &a.identities[i], nil
+				}
+			}
+			return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			for _, chain := range tlsInfo.State.VerifiedChains {
+				if len(chain) == 0 {
+					continue
+				}
+				subject := chain[0].Subject.CommonName
+				for i := range a.identities {
+					if a.identities[i].Subject != "" && a.identities[i].Subject == subject {
+						return &a.identities[i], nil
+					}
+				}
+			}
+		}
+	}
+
+	return nil, status.Error(codes.Unauthenticated, "no valid bearer token or client certificate")
+}
+
+func (a *authorizer) authorize(ctx context.Context, fullMethod string, req interface{}) error {
+	required := a.requiredRole(fullMethod, req)
+	if required == RoleNone {
+		return nil
+	}
+
+	identity, err := a.authenticate(ctx)
+	if err != nil {
+		log.Warn().Msgf("Unauthenticated call to %s: %v", fullMethod, err)
+		return err
+	}
+
+	if roleLevel[identity.Role] < roleLevel[required] {
+		log.Warn().Msgf("Denied call to %s from [%s] with role [%s], [%s] required", fullMethod, identity.Name, identity.Role, required)
+		return status.Errorf(codes.PermissionDenied, "role [%s] is not allowed to call %s, [%s] required", identity.Role, fullMethod, required)
+	}
+
+	return nil
+}
+
+func (a *authorizer) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authorize(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authorizer) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	// the stream request is not known yet, so only the method permission is checked
+	if err := a.authorize(ss.Context(), info.FullMethod, nil); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// getAuthzInterceptors returns the server options of the authorization interceptors, or nil if authz is disabled
+func getAuthzInterceptors(cfg types.ConfigAuthz) []grpc.ServerOption {
+	if !cfg.Enable {
+		return nil
+	}
+
+	a := newAuthorizer(cfg)
+
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(a.unaryInterceptor),
+		grpc.StreamInterceptor(a.streamInterceptor),
+	}
+}
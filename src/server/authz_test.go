@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	wpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/worker"
+)
+
+func newTestAuthorizer() *authorizer {
+	return newAuthorizer(types.ConfigAuthz{
+		Enable: true,
+		Identities: []types.AuthzIdentity{
+			{Name: "viewer", Token: "viewer-token", Role: RoleReadOnly},
+			{Name: "ops", Token: "ops-token", Role: RoleOperator},
+			{Name: "root", Token: "root-token", Role: RoleAdmin},
+		},
+		Permissions: []types.AuthzPermission{
+			{Method: "/v1.insight.Insight/GetInsightData", Role: RoleOperator},
+		},
+	})
+}
+
+func tokenContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestAuthorize(t *testing.T) {
+	a := newTestAuthorizer()
+
+	// health checks are always allowed
+	assert.NoError(t, a.authorize(context.Background(), "/grpc.health.v1.Health/Check", nil))
+
+	// no credentials
+	err := a.authorize(context.Background(), "/v1.worker.Worker/GetWorkerStatus", &wpb.WorkerRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// invalid token
+	err = a.authorize(tokenContext("unknown"), "/v1.worker.Worker/GetWorkerStatus", &wpb.WorkerRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// read-only can read, but cannot start workers
+	assert.NoError(t, a.authorize(tokenContext("viewer-token"), "/v1.worker.Worker/GetWorkerStatus", &wpb.WorkerRequest{}))
+	err = a.authorize(tokenContext("viewer-token"), "/v1.worker.Worker/Start", &wpb.WorkerRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// dbclear requires admin
+	err = a.authorize(tokenContext("ops-token"), "/v1.worker.Worker/Start", &wpb.WorkerRequest{Req: "dbclear"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.NoError(t, a.authorize(tokenContext("root-token"), "/v1.worker.Worker/Start", &wpb.WorkerRequest{Req: "dbclear"}))
+
+	// configured permissions override the defaults
+	err = a.authorize(tokenContext("viewer-token"), "/v1.insight.Insight/GetInsightData", nil)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// unknown methods require admin
+	err = a.authorize(tokenContext("ops-token"), "/v1.unknown.Unknown/Call", nil)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestGetAuthzInterceptorsDisabled(t *testing.T) {
+	assert.Nil(t, getAuthzInterceptors(types.ConfigAuthz{Enable: false}))
+}
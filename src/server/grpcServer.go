@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 
@@ -11,6 +13,7 @@ import (
 	core "github.com/accuknox/auto-policy-discovery/src/config"
 	fc "github.com/accuknox/auto-policy-discovery/src/feedconsumer"
 	logger "github.com/accuknox/auto-policy-discovery/src/logging"
+	"github.com/accuknox/auto-policy-discovery/src/metrics"
 	network "github.com/accuknox/auto-policy-discovery/src/networkpolicy"
 	obs "github.com/accuknox/auto-policy-discovery/src/observability"
 	system "github.com/accuknox/auto-policy-discovery/src/systempolicy"
@@ -18,6 +21,7 @@ import (
 	"github.com/accuknox/auto-policy-discovery/src/insight"
 	"github.com/accuknox/auto-policy-discovery/src/libs"
 	apb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/analyzer"
+	cpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/config"
 	fpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/consumer"
 	dpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/discovery"
 	ipb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/insight"
@@ -27,9 +31,10 @@ import (
 	"github.com/accuknox/auto-policy-discovery/src/types"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 const PortNumber = "9089"
@@ -55,6 +60,8 @@ func (s *workerServer) Start(ctx context.Context, in *wpb.WorkerRequest) (*wpb.W
 
 	if in.GetReq() == "dbclear" {
 		libs.ClearDBTables(core.CurrentCfg.ConfigDB)
+		network.PublishDeletedPolicies(core.CurrentCfg.ConfigDB)
+		system.PublishDeletedPolicies(core.CurrentCfg.ConfigDB)
 		response += "Cleared DB."
 	}
 
@@ -65,9 +72,9 @@ func (s *workerServer) Start(ctx context.Context, in *wpb.WorkerRequest) (*wpb.W
 
 	if in.GetPolicytype() != "" {
 		if in.GetPolicytype() == "network" {
-			network.StartNetworkWorker()
+			network.StartNetworkWorker(types.DiscoveryTriggerRPC)
 		} else if in.GetPolicytype() == "system" {
-			system.StartSystemWorker()
+			system.StartSystemWorker(types.DiscoveryTriggerRPC)
 		}
 		response += "Starting " + in.GetPolicytype() + " policy discovery"
 	}
@@ -124,6 +131,70 @@ func (s *workerServer) Convert(ctx context.Context, in *wpb.WorkerRequest) (*wpb
 	return &wpb.WorkerResponse{Res: "ok"}, nil
 }
 
+// DefaultListRunsLimit is the number of runs returned when the request has no limit
+const DefaultListRunsLimit = 100
+
+func convertDiscoveryRunToGrpcRun(run types.DiscoveryRun) *wpb.DiscoveryRun {
+	return &wpb.DiscoveryRun{
+		Id:              run.ID,
+		Policytype:      run.Type,
+		Trigger:         run.Trigger,
+		Status:          run.Status,
+		StartTime:       run.StartTime,
+		EndTime:         run.EndTime,
+		LogsConsumed:    int64(run.LogsConsumed),
+		Clusters:        run.Clusters,
+		Namespaces:      run.Namespaces,
+		PoliciesCreated: int64(run.PoliciesCreated),
+		PoliciesUpdated: int64(run.PoliciesUpdated),
+		Errors:          run.Errors,
+	}
+}
+
+func (s *workerServer) ListRuns(ctx context.Context, in *wpb.ListRunsRequest) (*wpb.ListRunsResponse, error) {
+	log.Info().Msg("List runs called")
+
+	limit := int(in.GetLimit())
+	if limit <= 0 {
+		limit = DefaultListRunsLimit
+	}
+
+	filter := types.DiscoveryRun{
+		Type:    in.GetPolicytype(),
+		Trigger: in.GetTrigger(),
+		Status:  in.GetStatus(),
+	}
+
+	runs, err := libs.GetDiscoveryRuns(core.GetCfgDB(), filter, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &wpb.ListRunsResponse{}
+	for _, run := range runs {
+		resp.Runs = append(resp.Runs, convertDiscoveryRunToGrpcRun(run))
+	}
+
+	return resp, nil
+}
+
+func (s *workerServer) GetRun(ctx context.Context, in *wpb.GetRunRequest) (*wpb.DiscoveryRun, error) {
+	log.Info().Msgf("Get run [%d] called", in.GetId())
+
+	if in.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid run id")
+	}
+
+	run, err := libs.GetDiscoveryRun(core.GetCfgDB(), in.GetId())
+	if errors.Is(err, libs.ErrDiscoveryRunNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	return convertDiscoveryRunToGrpcRun(*run), nil
+}
+
 // ======================= //
 // == Discovery Service == //
 // ======================= //
@@ -138,6 +209,22 @@ func (ds *discoveryServer) GetPolicy(req *dpb.GetPolicyRequest, srv dpb.Discover
 		return errors.New("invalid request")
 	}
 
+	if req.GetFollow() {
+		metrics.StreamConsumers.WithLabelValues(metrics.StreamDiscovery).Inc()
+		defer metrics.StreamConsumers.WithLabelValues(metrics.StreamDiscovery).Dec()
+
+		// follow before reading the db, so that no policy written in between is missed
+		if consumer.IsTypeSystem() {
+			system.PolicyStore.AddConsumer(consumer)
+			defer system.PolicyStore.RemoveConsumer(consumer)
+		}
+
+		if consumer.IsTypeNetwork() {
+			network.PolicyStore.AddConsumer(consumer)
+			defer network.PolicyStore.RemoveConsumer(consumer)
+		}
+	}
+
 	var yamlFromDB []types.PolicyYaml
 	if consumer.IsTypeSystem() {
 		yamlFromDB = append(yamlFromDB, system.GetPolicyYamlFromDB(consumer)...)
@@ -146,11 +233,8 @@ func (ds *discoveryServer) GetPolicy(req *dpb.GetPolicyRequest, srv dpb.Discover
 		yamlFromDB = append(yamlFromDB, network.GetPolicyYamlFromDB(consumer)...)
 	}
 
-	for i := range yamlFromDB {
-		err := libs.SendPolicyYamlInGrpcStream(srv, &yamlFromDB[i])
-		if err != nil {
-			return err
-		}
+	if err := libs.SendPolicyYamlsInGrpcStream(srv, consumer, yamlFromDB); err != nil {
+		return err
 	}
 
 	if !req.GetFollow() {
@@ -159,16 +243,6 @@ func (ds *discoveryServer) GetPolicy(req *dpb.GetPolicyRequest, srv dpb.Discover
 		return nil
 	}
 
-	if consumer.IsTypeSystem() {
-		system.PolicyStore.AddConsumer(consumer)
-		defer system.PolicyStore.RemoveConsumer(consumer)
-	}
-
-	if consumer.IsTypeNetwork() {
-		network.PolicyStore.AddConsumer(consumer)
-		defer network.PolicyStore.RemoveConsumer(consumer)
-	}
-
 	// consume policy update events
 	return libs.RelayPolicyEventToGrpcStream(srv, consumer)
 }
@@ -277,16 +351,230 @@ func (ps *publisherServer) GetSummary(req *ppb.SummaryRequest, srv ppb.Publisher
 	// Add a new consumer
 	obs.SysSummary.AddConsumer(consumer)
 
+	metrics.StreamConsumers.WithLabelValues(metrics.StreamPublisher).Inc()
+	defer metrics.StreamConsumers.WithLabelValues(metrics.StreamPublisher).Dec()
+
 	return obs.SysSummary.RelaySummaryEventToGrpcStream(srv, consumer)
 }
 
+// =========================== //
+// == Configuration Service == //
+// =========================== //
+
+type configServer struct {
+	cpb.UnimplementedConfigStoreServer
+}
+
+// configMutex serializes configuration changes
+var configMutex sync.Mutex
+
+// workerMutex serializes the worker restarts, which swap the configuration once the workers are idle
+var workerMutex sync.Mutex
+
+// appliedConfigs counts the applied configurations, so that a pending restart is skipped once a newer one is applied
+var appliedConfigs int
+
+// convertGrpcConfigToConfiguration overlays the requested config on top of the current one,
+// so that the settings not exposed in the gRPC message are kept as they are
+func convertGrpcConfigToConfiguration(in *cpb.ConfigRequest) (types.Configuration, error) {
+	newCfg := core.GetCurrentCfg()
+	newCfg.Status = 0
+
+	if in.GetConfig() != nil {
+		if err := libs.MapToStructure(in.GetConfig(), &newCfg); err != nil {
+			return newCfg, err
+		}
+	}
+
+	if in.GetConfigName() != "" {
+		newCfg.ConfigName = in.GetConfigName()
+	} else {
+		newCfg.ConfigName = in.GetConfig().GetConfigName()
+	}
+
+	return newCfg, core.ValidateConfiguration(newCfg)
+}
+
+func convertConfigurationToGrpcConfig(c types.Configuration) (*cpb.Config, error) {
+	// do not expose the db credentials
+	c.ConfigDB.DBPass = ""
+
+	config := &cpb.Config{}
+	if err := libs.MapToStructure(c, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (s *configServer) Add(ctx context.Context, in *cpb.ConfigRequest) (*cpb.ConfigResponse, error) {
+	log.Info().Msg("Add config called")
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	newCfg, err := convertGrpcConfigToConfiguration(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := libs.AddConfiguration(core.GetCfgDB(), newCfg); err != nil {
+		return nil, err
+	}
+
+	return &cpb.ConfigResponse{Msg: "ok"}, nil
+}
+
+func (s *configServer) Get(ctx context.Context, in *cpb.ConfigRequest) (*cpb.ConfigResponse, error) {
+	log.Info().Msg("Get config called")
+
+	configs, err := libs.GetConfigurations(core.GetCfgDB(), in.GetConfigName())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &cpb.ConfigResponse{Msg: "ok"}
+	for _, c := range configs {
+		config, err := convertConfigurationToGrpcConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		resp.Config = append(resp.Config, config)
+	}
+
+	return resp, nil
+}
+
+func (s *configServer) Update(ctx context.Context, in *cpb.ConfigRequest) (*cpb.ConfigResponse, error) {
+	log.Info().Msg("Update config called")
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	newCfg, err := convertGrpcConfigToConfiguration(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := libs.UpdateConfiguration(core.GetCfgDB(), newCfg.ConfigName, newCfg); err != nil {
+		return nil, err
+	}
+
+	return &cpb.ConfigResponse{Msg: "ok"}, nil
+}
+
+func (s *configServer) Delete(ctx context.Context, in *cpb.ConfigRequest) (*cpb.ConfigResponse, error) {
+	log.Info().Msg("Delete config called")
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	if in.GetConfigName() == core.GetCurrentCfg().ConfigName {
+		return nil, errors.New("cannot delete the applied configuration [" + in.GetConfigName() + "]")
+	}
+
+	if err := libs.DeleteConfiguration(core.GetCfgDB(), in.GetConfigName()); err != nil {
+		return nil, err
+	}
+
+	return &cpb.ConfigResponse{Msg: "ok"}, nil
+}
+
+func (s *configServer) Apply(ctx context.Context, in *cpb.ConfigRequest) (*cpb.ConfigResponse, error) {
+	log.Info().Msg("Apply config called")
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	configs, err := libs.GetConfigurations(core.GetCfgDB(), in.GetConfigName())
+	if err != nil {
+		return nil, err
+	}
+	if in.GetConfigName() == "" || len(configs) == 0 {
+		return nil, libs.ErrConfigNotFound
+	}
+
+	newCfg := configs[0]
+	core.SetConfigurationDefaults(&newCfg)
+	if err := core.ValidateConfiguration(newCfg); err != nil {
+		return nil, err
+	}
+
+	if err := libs.ApplyConfiguration(core.GetCfgDB(), newCfg.ConfigName); err != nil {
+		return nil, err
+	}
+	newCfg.Status = 1
+
+	applyConfiguration(newCfg)
+
+	return &cpb.ConfigResponse{Msg: "ok applied " + newCfg.ConfigName}, nil
+}
+
+// applyConfiguration restarts the discovery workers with the configuration in the background,
+// it is called with configMutex held
+func applyConfiguration(newCfg types.Configuration) {
+	appliedConfigs++
+	go restartWorkers(newCfg, appliedConfigs)
+
+	log.Info().Msgf("Configuration [%s] applied, restarting the discovery workers", newCfg.ConfigName)
+}
+
+// restartWorkers stops the discovery workers and waits for the in-flight discovery jobs,
+// so that no job mixes the configurations, then swaps the configuration and starts the workers
+func restartWorkers(newCfg types.Configuration, applied int) {
+	workerMutex.Lock()
+	defer workerMutex.Unlock()
+
+	// the cron jobs are stopped whatever the operation mode, the previous configuration may have started them
+	network.StopNetworkCronJob()
+	system.StopSystemCronJob()
+
+	for network.NetworkWorkerStatus == network.STATUS_RUNNING || system.SystemWorkerStatus == system.STATUS_RUNNING {
+		time.Sleep(time.Millisecond * 100)
+	}
+
+	configMutex.Lock()
+	// a newer configuration is swapped by its own restart
+	if applied != appliedConfigs {
+		configMutex.Unlock()
+		log.Info().Msgf("Configuration [%s] superseded, the workers are restarted with the newer one", newCfg.ConfigName)
+		return
+	}
+	core.SetCurrentCfg(newCfg)
+	libs.CreateTablesIfNotExist(core.GetCfgDB())
+	configMutex.Unlock()
+
+	network.StartNetworkWorker(types.DiscoveryTriggerOneTime)
+	system.StartSystemWorker(types.DiscoveryTriggerOneTime)
+
+	log.Info().Msgf("Discovery workers restarted with the configuration [%s]", newCfg.ConfigName)
+}
+
 // ================= //
 // == gRPC server == //
 // ================= //
 
 func GetNewServer() *grpc.Server {
-	s := grpc.NewServer()
-	grpc_health_v1.RegisterHealthServer(s, health.NewServer())
+	opts := []grpc.ServerOption{}
+
+	tlsConfig, err := getServerTLSConfig(core.GetCfgTLS())
+	if err != nil {
+		// never fall back to plaintext when tls is requested
+		log.Panic().Msgf("Failed to load tls credentials: %v", err)
+	}
+	creds := getTLSCredentials(tlsConfig)
+	if creds != nil {
+		opts = append(opts, grpc.Creds(creds))
+		log.Info().Msgf("gRPC server tls enabled, verify client cert: %v", core.GetCfgTLS().VerifyClientCert)
+	}
+
+	if authzOpts := getAuthzInterceptors(core.GetCfgAuthz()); authzOpts != nil {
+		opts = append(opts, authzOpts...)
+		log.Info().Msgf("gRPC server authorization enabled, %d identities", len(core.GetCfgAuthz().Identities))
+	}
+
+	s := grpc.NewServer(opts...)
+	healthServer = newHealthServer()
+	grpc_health_v1.RegisterHealthServer(s, healthServer)
 
 	reflection.Register(s)
 
@@ -298,6 +586,7 @@ func GetNewServer() *grpc.Server {
 	observabilityServer := &observabilityServer{}
 	discoveryServer := &discoveryServer{}
 	publisherServer := &publisherServer{}
+	configServer := &configServer{}
 
 	// register gRPC servers
 	wpb.RegisterWorkerServer(s, workerServer)
@@ -307,6 +596,7 @@ func GetNewServer() *grpc.Server {
 	opb.RegisterObservabilityServer(s, observabilityServer)
 	dpb.RegisterDiscoveryServer(s, discoveryServer)
 	ppb.RegisterPublisherServer(s, publisherServer)
+	cpb.RegisterConfigStoreServer(s, configServer)
 
 	if core.GetCurrentCfg().ConfigClusterMgmt.ClusterInfoFrom != "k8sclient" {
 		// start consumer automatically
@@ -316,13 +606,16 @@ func GetNewServer() *grpc.Server {
 	}
 
 	// start net worker automatically
-	network.StartNetworkWorker()
+	network.StartNetworkWorker(types.DiscoveryTriggerOneTime)
 
 	// start sys worker automatically
-	system.StartSystemWorker()
+	system.StartSystemWorker(types.DiscoveryTriggerOneTime)
 
 	// start observability
 	obs.InitObservability()
 
+	// report the health of each component
+	go watchHealth(healthServer, getHealthComponents(), HealthCheckInterval, HealthStopChan)
+
 	return s
 }
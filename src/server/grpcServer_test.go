@@ -3,6 +3,10 @@ package server
 import (
 	"testing"
 
+	core "github.com/accuknox/auto-policy-discovery/src/config"
+	network "github.com/accuknox/auto-policy-discovery/src/networkpolicy"
+	system "github.com/accuknox/auto-policy-discovery/src/systempolicy"
+	"github.com/accuknox/auto-policy-discovery/src/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -10,3 +14,22 @@ func TestGetNewServer(t *testing.T) {
 	server := GetNewServer()
 	assert.NotNil(t, server)
 }
+
+func TestRestartWorkers(t *testing.T) {
+	current := core.CurrentCfg
+	t.Cleanup(func() {
+		core.CurrentCfg = current
+	})
+
+	noop := types.Configuration{ConfigName: "noop"}
+	noop.ConfigNetPolicy.OperationMode = network.OP_MODE_NOOP
+	noop.ConfigSysPolicy.OperationMode = system.OP_MODE_NOOP
+
+	// a superseded configuration is not swapped
+	appliedConfigs = 2
+	restartWorkers(noop, 1)
+	assert.Equal(t, current.ConfigName, core.CurrentCfg.ConfigName)
+
+	restartWorkers(noop, 2)
+	assert.Equal(t, "noop", core.CurrentCfg.ConfigName)
+}
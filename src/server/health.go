@@ -0,0 +1,170 @@
+package server
+
+import (
+	"time"
+
+	core "github.com/accuknox/auto-policy-discovery/src/config"
+	fc "github.com/accuknox/auto-policy-discovery/src/feedconsumer"
+	"github.com/accuknox/auto-policy-discovery/src/libs"
+	network "github.com/accuknox/auto-policy-discovery/src/networkpolicy"
+	obs "github.com/accuknox/auto-policy-discovery/src/observability"
+	"github.com/accuknox/auto-policy-discovery/src/plugin"
+	system "github.com/accuknox/auto-policy-discovery/src/systempolicy"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// health service names, the empty service name stays SERVING while the process is alive (liveness)
+const (
+	HealthReadiness         = "readiness"
+	HealthDatabase          = "database"
+	HealthHubbleRelay       = "hubble-relay"
+	HealthKubeArmorRelay    = "kubearmor-relay"
+	HealthFeedConsumer      = "feed-consumer"
+	HealthNetworkWorker     = "network-worker"
+	HealthSystemWorker      = "system-worker"
+	HealthObservabilityCron = "observability-cron"
+	HealthPublisherCron     = "publisher-cron"
+)
+
+const (
+	HealthCheckInterval = time.Second * 10
+	HealthDBPingTimeout = time.Second * 5
+)
+
+var healthServer *health.Server
+
+// HealthStopChan stops the health checks
+var HealthStopChan chan struct{}
+
+func init() {
+	HealthStopChan = make(chan struct{})
+}
+
+// healthComponent is checked only if it is enabled in the current configuration
+type healthComponent struct {
+	name    string
+	enabled func() bool
+	healthy func() bool
+}
+
+func getHealthComponents() []healthComponent {
+	always := func() bool { return true }
+
+	networkFrom := func(from string) bool {
+		return core.GetCfgNetOperationMode() != network.OP_MODE_NOOP && core.GetCfgNetworkLogFrom() == from
+	}
+	systemFrom := func(from string) bool {
+		return core.GetCfgSysOperationMode() != system.OP_MODE_NOOP && core.GetCfgSystemLogFrom() == from
+	}
+
+	return []healthComponent{
+		{
+			name:    HealthDatabase,
+			enabled: always,
+			healthy: func() bool {
+				configMutex.Lock()
+				cfgDB := core.GetCfgDB()
+				configMutex.Unlock()
+
+				err := libs.PingDB(cfgDB, HealthDBPingTimeout)
+				if err != nil {
+					log.Warn().Msgf("Database health check failed: %v", err)
+				}
+				return err == nil
+			},
+		},
+		{
+			name:    HealthHubbleRelay,
+			enabled: func() bool { return networkFrom("hubble") },
+			healthy: func() bool { return plugin.HubbleRelayStarted },
+		},
+		{
+			name:    HealthKubeArmorRelay,
+			enabled: func() bool { return systemFrom("kubearmor") },
+			healthy: func() bool { return plugin.KubeArmorRelayStarted },
+		},
+		{
+			name:    HealthFeedConsumer,
+			enabled: func() bool { return networkFrom("feed-consumer") || systemFrom("feed-consumer") },
+			healthy: func() bool { return fc.Status == fc.STATUS_RUNNING },
+		},
+		{
+			name:    HealthNetworkWorker,
+			enabled: func() bool { return core.GetCfgNetOperationMode() == network.OP_MODE_CRONJOB },
+			healthy: func() bool { return network.NetworkCronJob != nil },
+		},
+		{
+			name:    HealthSystemWorker,
+			enabled: func() bool { return core.GetCfgSysOperationMode() == system.OP_MODE_CRONJOB },
+			healthy: func() bool { return system.SystemCronJob != nil },
+		},
+		{
+			name:    HealthObservabilityCron,
+			enabled: core.GetCfgObservabilityEnable,
+			healthy: func() bool { return obs.ObsCronJob != nil },
+		},
+		{
+			name:    HealthPublisherCron,
+			enabled: core.GetCfgPublisherEnable,
+			healthy: func() bool { return obs.PublisherCronJob != nil },
+		},
+	}
+}
+
+// updateHealth sets the status of each component, and the readiness from all the enabled components
+func updateHealth(hs *health.Server, components []healthComponent) {
+	ready := true
+
+	// the configuration is swapped under configMutex
+	configMutex.Lock()
+	enabled := make([]bool, len(components))
+	for i, c := range components {
+		enabled[i] = c.enabled()
+	}
+	configMutex.Unlock()
+
+	for i, c := range components {
+		if !enabled[i] {
+			hs.SetServingStatus(c.name, grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN)
+			continue
+		}
+
+		if c.healthy() {
+			hs.SetServingStatus(c.name, grpc_health_v1.HealthCheckResponse_SERVING)
+		} else {
+			hs.SetServingStatus(c.name, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+			ready = false
+		}
+	}
+
+	if ready {
+		hs.SetServingStatus(HealthReadiness, grpc_health_v1.HealthCheckResponse_SERVING)
+	} else {
+		hs.SetServingStatus(HealthReadiness, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	}
+}
+
+// watchHealth checks the components at each interval until the stop channel is closed
+func watchHealth(hs *health.Server, components []healthComponent, interval time.Duration, stopChan chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	updateHealth(hs, components)
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			updateHealth(hs, components)
+		}
+	}
+}
+
+// newHealthServer returns the health server, not ready until the first check is done
+func newHealthServer() *health.Server {
+	hs := health.NewServer()
+	hs.SetServingStatus(HealthReadiness, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	return hs
+}
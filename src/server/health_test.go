@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func getServingStatus(t *testing.T, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	resp, err := healthServer.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+	assert.NoError(t, err)
+	return resp.Status
+}
+
+func TestUpdateHealth(t *testing.T) {
+	healthServer = newHealthServer()
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, getServingStatus(t, HealthReadiness))
+
+	dbHealthy := true
+	components := []healthComponent{
+		{name: HealthDatabase, enabled: func() bool { return true }, healthy: func() bool { return dbHealthy }},
+		{name: HealthHubbleRelay, enabled: func() bool { return false }, healthy: func() bool { return false }},
+	}
+
+	updateHealth(healthServer, components)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, getServingStatus(t, HealthDatabase))
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN, getServingStatus(t, HealthHubbleRelay))
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, getServingStatus(t, HealthReadiness))
+
+	// a disabled component does not affect the readiness, an unhealthy one does
+	dbHealthy = false
+	updateHealth(healthServer, components)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, getServingStatus(t, HealthDatabase))
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, getServingStatus(t, HealthReadiness))
+
+	// liveness is not affected
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, getServingStatus(t, ""))
+}
+
+func TestWatchHealth(t *testing.T) {
+	healthServer = newHealthServer()
+	stopChan := make(chan struct{})
+
+	checks := make(chan struct{}, 10)
+	components := []healthComponent{
+		{name: HealthDatabase, enabled: func() bool { return true }, healthy: func() bool { checks <- struct{}{}; return true }},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		watchHealth(healthServer, components, 10*time.Millisecond, stopChan)
+		close(done)
+	}()
+
+	<-checks
+	<-checks
+	close(stopChan)
+	<-done
+
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, getServingStatus(t, HealthReadiness))
+}
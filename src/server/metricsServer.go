@@ -0,0 +1,24 @@
+package server
+
+import (
+	"net/http"
+
+	core "github.com/accuknox/auto-policy-discovery/src/config"
+	"github.com/accuknox/auto-policy-discovery/src/metrics"
+)
+
+// GetNewMetricsServer returns the prometheus /metrics server, or nil if metrics are disabled
+func GetNewMetricsServer() *http.Server {
+	cfg := core.GetCfgMetrics()
+	if !cfg.Enable {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+
+	return &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: mux,
+	}
+}
@@ -0,0 +1,499 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	core "github.com/accuknox/auto-policy-discovery/src/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	apb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/analyzer"
+	dpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/discovery"
+	ipb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/insight"
+	opb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/observability"
+	ppb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/publisher"
+	wpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/worker"
+)
+
+// ==================== //
+// == Gateway Routes == //
+// ==================== //
+
+type unaryHandler func(ctx context.Context, req proto.Message) (proto.Message, error)
+type streamHandler func(req proto.Message, stream grpc.ServerStream) error
+
+// gatewayRoute maps a rest endpoint to a grpc method, exactly one of unary and stream is set
+type gatewayRoute struct {
+	path       string
+	methods    []string
+	fullMethod string
+	newRequest func() proto.Message
+	unary      unaryHandler
+	stream     streamHandler
+}
+
+func getGatewayRoutes() []gatewayRoute {
+	ws := &workerServer{}
+	ds := &discoveryServer{}
+	as := &analyzerServer{}
+	is := &insightServer{}
+	obs := &observabilityServer{}
+	ps := &publisherServer{}
+
+	newWorkerRequest := func() proto.Message { return &wpb.WorkerRequest{} }
+
+	return []gatewayRoute{
+		{
+			path: "/v1/worker/status", methods: []string{http.MethodGet},
+			fullMethod: "/v1.worker.Worker/GetWorkerStatus", newRequest: newWorkerRequest,
+			unary: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return ws.GetWorkerStatus(ctx, req.(*wpb.WorkerRequest))
+			},
+		},
+		{
+			path: "/v1/worker/start", methods: []string{http.MethodPost},
+			fullMethod: "/v1.worker.Worker/Start", newRequest: newWorkerRequest,
+			unary: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return ws.Start(ctx, req.(*wpb.WorkerRequest))
+			},
+		},
+		{
+			path: "/v1/worker/stop", methods: []string{http.MethodPost},
+			fullMethod: "/v1.worker.Worker/Stop", newRequest: newWorkerRequest,
+			unary: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return ws.Stop(ctx, req.(*wpb.WorkerRequest))
+			},
+		},
+		{
+			path: "/v1/worker/convert", methods: []string{http.MethodPost},
+			fullMethod: "/v1.worker.Worker/Convert", newRequest: newWorkerRequest,
+			unary: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return ws.Convert(ctx, req.(*wpb.WorkerRequest))
+			},
+		},
+		{
+			path: "/v1/worker/runs", methods: []string{http.MethodGet},
+			fullMethod: "/v1.worker.Worker/ListRuns",
+			newRequest: func() proto.Message { return &wpb.ListRunsRequest{} },
+			unary: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return ws.ListRuns(ctx, req.(*wpb.ListRunsRequest))
+			},
+		},
+		{
+			path: "/v1/worker/run", methods: []string{http.MethodGet},
+			fullMethod: "/v1.worker.Worker/GetRun",
+			newRequest: func() proto.Message { return &wpb.GetRunRequest{} },
+			unary: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return ws.GetRun(ctx, req.(*wpb.GetRunRequest))
+			},
+		},
+		{
+			path: "/v1/discovery/policies", methods: []string{http.MethodGet, http.MethodPost},
+			fullMethod: "/v1.discovery.Discovery/GetPolicy",
+			newRequest: func() proto.Message { return &dpb.GetPolicyRequest{} },
+			stream: func(req proto.Message, stream grpc.ServerStream) error {
+				return ds.GetPolicy(req.(*dpb.GetPolicyRequest), &policyStream{stream})
+			},
+		},
+		{
+			path: "/v1/insight", methods: []string{http.MethodGet, http.MethodPost},
+			fullMethod: "/v1.insight.Insight/GetInsightData",
+			newRequest: func() proto.Message { return &ipb.Request{} },
+			unary: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return is.GetInsightData(ctx, req.(*ipb.Request))
+			},
+		},
+		{
+			path: "/v1/observability/summary", methods: []string{http.MethodGet, http.MethodPost},
+			fullMethod: "/v1.observability.Observability/Summary",
+			newRequest: func() proto.Message { return &opb.Request{} },
+			unary: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return obs.Summary(ctx, req.(*opb.Request))
+			},
+		},
+		{
+			path: "/v1/observability/podnames", methods: []string{http.MethodGet, http.MethodPost},
+			fullMethod: "/v1.observability.Observability/GetPodNames",
+			newRequest: func() proto.Message { return &opb.Request{} },
+			unary: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return obs.GetPodNames(ctx, req.(*opb.Request))
+			},
+		},
+		{
+			path: "/v1/analyzer/network-policies", methods: []string{http.MethodPost},
+			fullMethod: "/v1.analyzer.Analyzer/GetNetworkPolicies",
+			newRequest: func() proto.Message { return &apb.NetworkLogs{} },
+			unary: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return as.GetNetworkPolicies(ctx, req.(*apb.NetworkLogs))
+			},
+		},
+		{
+			path: "/v1/analyzer/system-policies", methods: []string{http.MethodPost},
+			fullMethod: "/v1.analyzer.Analyzer/GetSystemPolicies",
+			newRequest: func() proto.Message { return &apb.SystemLogs{} },
+			unary: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return as.GetSystemPolicies(ctx, req.(*apb.SystemLogs))
+			},
+		},
+		{
+			path: "/v1/publisher/summary", methods: []string{http.MethodGet, http.MethodPost},
+			fullMethod: "/v1.publisher.Publisher/GetSummary",
+			newRequest: func() proto.Message { return &ppb.SummaryRequest{} },
+			stream: func(req proto.Message, stream grpc.ServerStream) error {
+				return ps.GetSummary(req.(*ppb.SummaryRequest), &summaryStream{stream})
+			},
+		},
+	}
+}
+
+// ===================== //
+// == Request Parsing == //
+// ===================== //
+
+// setFieldFromQuery sets a scalar or repeated scalar field of the request from a query parameter
+func setFieldFromQuery(msg protoreflect.Message, key string, values []string) error {
+	fields := msg.Descriptor().Fields()
+	fd := fields.ByJSONName(key)
+	if fd == nil {
+		fd = fields.ByName(protoreflect.Name(key))
+	}
+	if fd == nil {
+		return fmt.Errorf("unknown query parameter %s", key)
+	}
+
+	parse := func(value string) (protoreflect.Value, error) {
+		switch fd.Kind() {
+		case protoreflect.StringKind:
+			return protoreflect.ValueOfString(value), nil
+		case protoreflect.BytesKind:
+			return protoreflect.ValueOfBytes([]byte(value)), nil
+		case protoreflect.BoolKind:
+			b, err := strconv.ParseBool(value)
+			return protoreflect.ValueOfBool(b), err
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+			i, err := strconv.ParseInt(value, 10, 32)
+			return protoreflect.ValueOfInt32(int32(i)), err
+		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			i, err := strconv.ParseInt(value, 10, 64)
+			return protoreflect.ValueOfInt64(i), err
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+			i, err := strconv.ParseUint(value, 10, 32)
+			return protoreflect.ValueOfUint32(uint32(i)), err
+		case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			i, err := strconv.ParseUint(value, 10, 64)
+			return protoreflect.ValueOfUint64(i), err
+		}
+		return protoreflect.Value{}, fmt.Errorf("query parameter %s is not a scalar field", key)
+	}
+
+	if fd.IsMap() {
+		return fmt.Errorf("query parameter %s is not a scalar field", key)
+	}
+
+	if fd.IsList() {
+		list := msg.Mutable(fd).List()
+		for _, value := range values {
+			v, err := parse(value)
+			if err != nil {
+				return fmt.Errorf("invalid query parameter %s: %v", key, err)
+			}
+			list.Append(v)
+		}
+		return nil
+	}
+
+	v, err := parse(values[len(values)-1])
+	if err != nil {
+		return fmt.Errorf("invalid query parameter %s: %v", key, err)
+	}
+	msg.Set(fd, v)
+	return nil
+}
+
+// parseGatewayRequest builds the grpc request from the json body, overlaid with the query parameters
+func parseGatewayRequest(r *http.Request, req proto.Message) error {
+	if r.Body != nil {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		if len(strings.TrimSpace(string(body))) > 0 {
+			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, req); err != nil {
+				return fmt.Errorf("invalid request body: %v", err)
+			}
+		}
+	}
+
+	for key, values := range r.URL.Query() {
+		if err := setFieldFromQuery(req.ProtoReflect(), key, values); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// gatewayContext carries the bearer token and the client certificate to the authorizer, like grpc does
+func gatewayContext(r *http.Request) context.Context {
+	ctx := r.Context()
+
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", auth))
+	}
+
+	if r.TLS != nil {
+		addr, _ := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr, AuthInfo: credentials.TLSInfo{State: *r.TLS}})
+	}
+
+	return ctx
+}
+
+// ====================== //
+// == Response Writing == //
+// ====================== //
+
+var gatewayMarshaler = protojson.MarshalOptions{UseProtoNames: true}
+
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+func writeGatewayError(w http.ResponseWriter, httpStatus int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    httpStatus,
+		"message": status.Convert(err).Message(),
+	})
+}
+
+// gatewayStream sends the messages of a grpc server stream as server-sent events,
+// or as newline-delimited json if the client does not accept event streams
+type gatewayStream struct {
+	ctx     context.Context
+	w       http.ResponseWriter
+	flusher http.Flusher
+	sse     bool
+}
+
+func newGatewayStream(ctx context.Context, w http.ResponseWriter, r *http.Request) *gatewayStream {
+	s := &gatewayStream{
+		ctx: ctx,
+		w:   w,
+		sse: strings.Contains(r.Header.Get("Accept"), "text/event-stream"),
+	}
+	s.flusher, _ = w.(http.Flusher)
+
+	if s.sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	s.flush()
+
+	return s
+}
+
+func (s *gatewayStream) flush() {
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+func (s *gatewayStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Error(codes.Internal, "not a proto message")
+	}
+
+	data, err := gatewayMarshaler.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	if s.sse {
+		_, err = fmt.Fprintf(s.w, "data: %s\n\n", data)
+	} else {
+		_, err = fmt.Fprintf(s.w, "%s\n", data)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.flush()
+	return nil
+}
+
+func (s *gatewayStream) Context() context.Context     { return s.ctx }
+func (s *gatewayStream) SetHeader(metadata.MD) error  { return nil }
+func (s *gatewayStream) SendHeader(metadata.MD) error { return nil }
+func (s *gatewayStream) SetTrailer(metadata.MD)       {}
+func (s *gatewayStream) RecvMsg(m interface{}) error  { return io.EOF }
+
+type policyStream struct {
+	grpc.ServerStream
+}
+
+func (s *policyStream) Send(m *dpb.GetPolicyResponse) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+type summaryStream struct {
+	grpc.ServerStream
+}
+
+func (s *summaryStream) Send(m *ppb.SummaryResponse) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+// ===================== //
+// == Gateway Handler == //
+// ===================== //
+
+func (route gatewayRoute) handler(authz *authorizer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		allowed := false
+		for _, method := range route.methods {
+			if r.Method == method {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			w.Header().Set("Allow", strings.Join(route.methods, ", "))
+			writeGatewayError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+
+		req := route.newRequest()
+		if err := parseGatewayRequest(r, req); err != nil {
+			writeGatewayError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		ctx := gatewayContext(r)
+
+		if authz != nil {
+			if err := authz.authorize(ctx, route.fullMethod, req); err != nil {
+				writeGatewayError(w, httpStatusFromCode(status.Code(err)), err)
+				return
+			}
+		}
+
+		if route.stream != nil {
+			stream := newGatewayStream(ctx, w, r)
+			if err := route.stream(req, stream); err != nil {
+				// the status is already sent, report the error in the stream
+				log.Error().Msgf("REST gateway stream %s failed: %v", r.URL.Path, err)
+				_ = stream.SendMsg(status.Convert(err).Proto())
+			}
+			return
+		}
+
+		resp, err := route.unary(ctx, req)
+		if err != nil {
+			writeGatewayError(w, httpStatusFromCode(status.Code(err)), err)
+			return
+		}
+
+		data, err := gatewayMarshaler.Marshal(resp)
+		if err != nil {
+			writeGatewayError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}
+}
+
+func newGatewayHandler(authz *authorizer) http.Handler {
+	mux := http.NewServeMux()
+	for _, route := range getGatewayRoutes() {
+		mux.Handle(route.path, route.handler(authz))
+	}
+	return mux
+}
+
+// ================= //
+// == HTTP server == //
+// ================= //
+
+// GetNewHTTPGateway returns the rest gateway server, or nil if the gateway is disabled
+func GetNewHTTPGateway() *http.Server {
+	cfg := core.GetCfgHTTPGateway()
+	if !cfg.Enable {
+		return nil
+	}
+
+	var authz *authorizer
+	if core.GetCfgAuthz().Enable {
+		authz = newAuthorizer(core.GetCfgAuthz())
+	}
+
+	tlsConfig, err := getServerTLSConfig(core.GetCfgTLS())
+	if err != nil {
+		// never fall back to plaintext when tls is requested
+		log.Panic().Msgf("Failed to load tls config for the REST gateway: %v", err)
+	}
+
+	return &http.Server{
+		Addr:      ":" + cfg.Port,
+		Handler:   newGatewayHandler(authz),
+		TLSConfig: tlsConfig,
+	}
+}
+
+// ServeHTTPGateway listens on the gateway port, with tls if it is configured
+func ServeHTTPGateway(srv *http.Server) error {
+	lis, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+
+	if srv.TLSConfig != nil {
+		lis = tls.NewListener(lis, srv.TLSConfig)
+	}
+
+	return srv.Serve(lis)
+}
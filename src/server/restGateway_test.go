@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+
+	dpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/discovery"
+	ppb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/publisher"
+)
+
+func TestParseGatewayRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/v1/discovery/policies?follow=true&kind=CiliumNetworkPolicy&kind=KubeArmorPolicy",
+		strings.NewReader(`{"namespace": "default", "unknown": 1}`))
+
+	req := &dpb.GetPolicyRequest{}
+	assert.NoError(t, parseGatewayRequest(r, req))
+	assert.True(t, req.Follow)
+	assert.Equal(t, []string{"CiliumNetworkPolicy", "KubeArmorPolicy"}, req.Kind)
+	assert.Equal(t, "default", req.Namespace)
+
+	r = httptest.NewRequest(http.MethodGet, "/v1/publisher/summary?ClusterName=default", nil)
+	summaryReq := &ppb.SummaryRequest{}
+	assert.NoError(t, parseGatewayRequest(r, summaryReq))
+	assert.Equal(t, "default", summaryReq.ClusterName)
+
+	r = httptest.NewRequest(http.MethodGet, "/v1/discovery/policies?follow=maybe", nil)
+	assert.Error(t, parseGatewayRequest(r, &dpb.GetPolicyRequest{}))
+
+	r = httptest.NewRequest(http.MethodGet, "/v1/discovery/policies?unknown=1", nil)
+	assert.Error(t, parseGatewayRequest(r, &dpb.GetPolicyRequest{}))
+}
+
+func TestGatewayAuthz(t *testing.T) {
+	handler := newGatewayHandler(newAuthorizer(types.ConfigAuthz{
+		Enable:     true,
+		Identities: []types.AuthzIdentity{{Name: "viewer", Token: "viewer-token", Role: RoleReadOnly}},
+	}))
+
+	// no token
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/worker/start", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// read-only cannot start workers
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/v1/worker/start", nil)
+	r.Header.Set("Authorization", "Bearer viewer-token")
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// wrong http method
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/worker/start", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestGatewayStream(t *testing.T) {
+	resp := &dpb.GetPolicyResponse{Kind: "CiliumNetworkPolicy", Name: "test"}
+
+	// server-sent events
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/v1/discovery/policies", nil)
+	r.Header.Set("Accept", "text/event-stream")
+	stream := newGatewayStream(context.Background(), w, r)
+	assert.NoError(t, (&policyStream{stream}).Send(resp))
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "data: {"))
+	assert.True(t, strings.HasSuffix(w.Body.String(), "}\n\n"))
+
+	// newline-delimited json
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/v1/discovery/policies", nil)
+	stream = newGatewayStream(context.Background(), w, r)
+	assert.NoError(t, (&policyStream{stream}).Send(resp))
+	assert.NoError(t, (&policyStream{stream}).Send(resp))
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Equal(t, 2, strings.Count(w.Body.String(), "\n"))
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	core "github.com/accuknox/auto-policy-discovery/src/config"
+	fc "github.com/accuknox/auto-policy-discovery/src/feedconsumer"
+	network "github.com/accuknox/auto-policy-discovery/src/networkpolicy"
+	obs "github.com/accuknox/auto-policy-discovery/src/observability"
+	system "github.com/accuknox/auto-policy-discovery/src/systempolicy"
+	"google.golang.org/grpc"
+)
+
+// DefaultShutdownTimeout is used when shutdown.timeout is not a valid duration
+const DefaultShutdownTimeout = 30 * time.Second
+
+// getShutdownTimeout returns the deadline of the graceful shutdown
+func getShutdownTimeout() time.Duration {
+	timeout, err := time.ParseDuration(core.GetCfgShutdown().Timeout)
+	if err != nil || timeout <= 0 {
+		return DefaultShutdownTimeout
+	}
+	return timeout
+}
+
+// waitForIdleWorkers waits for the in-flight discovery jobs, or until the context is done
+func waitForIdleWorkers(ctx context.Context) {
+	for network.NetworkWorkerStatus == network.STATUS_RUNNING || system.SystemWorkerStatus == system.STATUS_RUNNING {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Millisecond * 100):
+		}
+	}
+}
+
+// drain stops the log receivers and the cron jobs, then persists what is still buffered
+func drain(ctx context.Context) {
+	// stop receiving logs, the buffers are not filled anymore
+	network.StopNetworkWorker()
+	system.StopSystemWorker()
+
+	fc.ConsumerMutex.Lock()
+	fc.StopConsumer()
+	fc.ConsumerMutex.Unlock()
+
+	waitForIdleWorkers(ctx)
+
+	if core.GetCfgShutdown().FinalDiscovery {
+		network.DiscoverNetworkPolicyPending()
+		system.DiscoverSystemPolicyPending()
+	}
+
+	obs.StopObservability()
+}
+
+// Shutdown stops accepting RPCs, drains the discovery and observability buffers, and stops the servers.
+// The servers are stopped forcibly once the shutdown timeout expires.
+func Shutdown(grpcServer *grpc.Server, httpServers ...*http.Server) {
+	timeout := getShutdownTimeout()
+	log.Info().Msgf("Graceful shutdown started, timeout %v", timeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// 1. report not ready, and stop accepting RPCs, the streams still receive the policies of the final discovery
+	close(HealthStopChan)
+	if healthServer != nil {
+		healthServer.Shutdown()
+	}
+
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+
+	for _, srv := range httpServers {
+		if srv == nil {
+			continue
+		}
+		go func(srv *http.Server) {
+			if err := srv.Shutdown(ctx); err != nil {
+				log.Error().Msgf("Failed to shut down the http server on %s: %v", srv.Addr, err)
+			}
+		}(srv)
+	}
+
+	// 2. drain the buffers
+	drained := make(chan struct{})
+	go func() {
+		drain(ctx)
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		log.Info().Msg("Buffers drained")
+	case <-ctx.Done():
+		log.Warn().Msg("Shutdown timeout expired before the buffers were drained")
+	}
+
+	// 3. close the remaining streams, the db connections are opened per query so none is left open
+	select {
+	case <-grpcStopped:
+	case <-time.After(time.Second):
+		grpcServer.Stop()
+	}
+
+	for _, srv := range httpServers {
+		if srv != nil {
+			_ = srv.Close()
+		}
+	}
+
+	close(TLSStopChan)
+
+	log.Info().Msg("Graceful shutdown done")
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	core "github.com/accuknox/auto-policy-discovery/src/config"
+	network "github.com/accuknox/auto-policy-discovery/src/networkpolicy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetShutdownTimeout(t *testing.T) {
+	prev := core.CurrentCfg.ConfigShutdown
+	defer func() { core.CurrentCfg.ConfigShutdown = prev }()
+
+	core.CurrentCfg.ConfigShutdown.Timeout = "5s"
+	assert.Equal(t, 5*time.Second, getShutdownTimeout())
+
+	core.CurrentCfg.ConfigShutdown.Timeout = "invalid"
+	assert.Equal(t, DefaultShutdownTimeout, getShutdownTimeout())
+
+	core.CurrentCfg.ConfigShutdown.Timeout = "-1s"
+	assert.Equal(t, DefaultShutdownTimeout, getShutdownTimeout())
+}
+
+func TestWaitForIdleWorkers(t *testing.T) {
+	prev := network.NetworkWorkerStatus
+	defer func() { network.NetworkWorkerStatus = prev }()
+
+	// a running job is waited until the deadline
+	network.NetworkWorkerStatus = network.STATUS_RUNNING
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	waitForIdleWorkers(ctx)
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+
+	// returns as soon as the job is done
+	network.NetworkWorkerStatus = network.STATUS_RUNNING
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		network.NetworkWorkerStatus = network.STATUS_IDLE
+	}()
+
+	start = time.Now()
+	waitForIdleWorkers(context.Background())
+	assert.True(t, time.Since(start) < time.Second)
+}
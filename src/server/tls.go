@@ -0,0 +1,227 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"google.golang.org/grpc/credentials"
+)
+
+// ========================== //
+// == Certificate Reloader == //
+// ========================== //
+
+// certReloader keeps the server certificate and client CA pool loaded from files,
+// and reloads them whenever one of the files is modified
+type certReloader struct {
+	cfg types.ConfigTLS
+
+	mutex    sync.RWMutex
+	cert     *tls.Certificate
+	caPool   *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+func newCertReloader(cfg types.ConfigTLS) (*certReloader, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("tls cert-file and key-file are required")
+	}
+	if cfg.VerifyClientCert && cfg.CAFile == "" {
+		return nil, errors.New("tls ca-file is required to verify client certificates")
+	}
+
+	r := &certReloader{cfg: cfg}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.CAFile != "" {
+		files = append(files, r.cfg.CAFile)
+	}
+	return files
+}
+
+func (r *certReloader) getModTimes() (map[string]time.Time, error) {
+	modTimes := map[string]time.Time{}
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[file] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func (r *certReloader) load() error {
+	modTimes, err := r.getModTimes()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	var caPool *x509.CertPool
+	if r.cfg.CAFile != "" {
+		caPEM, err := os.ReadFile(r.cfg.CAFile)
+		if err != nil {
+			return err
+		}
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caPEM) {
+			return errors.New("no valid certificate found in tls ca-file " + r.cfg.CAFile)
+		}
+	}
+
+	r.mutex.Lock()
+	r.cert = &cert
+	r.caPool = caPool
+	r.modTimes = modTimes
+	r.mutex.Unlock()
+
+	return nil
+}
+
+// isModified checks whether any of the files changed since the last load
+func (r *certReloader) isModified() bool {
+	modTimes, err := r.getModTimes()
+	if err != nil {
+		// the files may be in the middle of being replaced, retry later
+		return false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for file, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+// watch polls the files and reloads them on change, the current certificates are kept if the new ones are invalid
+func (r *certReloader) watch(interval time.Duration, stopChan chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			if !r.isModified() {
+				continue
+			}
+			if err := r.load(); err != nil {
+				log.Error().Msgf("Failed to reload tls certificates, keeping the current ones: %v", err)
+				continue
+			}
+			log.Info().Msg("tls certificates reloaded")
+		}
+	}
+}
+
+// getConfigForClient builds the tls config of each handshake from the base config and the latest loaded certificates,
+// the handshake only uses the returned config, so it keeps the alpn protocols of the base one
+func (r *certReloader) getConfigForClient(base *tls.Config) *tls.Config {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	tlsConfig := &tls.Config{
+		MinVersion:   base.MinVersion,
+		NextProtos:   base.NextProtos,
+		Certificates: []tls.Certificate{*r.cert},
+		ClientAuth:   tls.NoClientCert,
+	}
+
+	if r.caPool != nil {
+		tlsConfig.ClientCAs = r.caPool
+		if r.cfg.VerifyClientCert {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		} else {
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	return tlsConfig
+}
+
+// ===================== //
+// == TLS Credentials == //
+// ===================== //
+
+// TLSStopChan stops the certificate reloader
+var TLSStopChan chan struct{}
+
+func init() {
+	TLSStopChan = make(chan struct{})
+}
+
+// getTLSConfig returns the server tls config backed by a certificate reloader, or nil if tls is disabled
+func getTLSConfig(cfg types.ConfigTLS) (*tls.Config, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+
+	reloader, err := newCertReloader(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ReloadInterval != "" {
+		interval, err := time.ParseDuration(cfg.ReloadInterval)
+		if err != nil {
+			return nil, err
+		}
+		if interval > 0 {
+			go reloader.watch(interval, TLSStopChan)
+		}
+	}
+
+	// h2 for grpc and the rest gateway, http/1.1 for the gateway clients without http2
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+	tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return reloader.getConfigForClient(tlsConfig), nil
+	}
+
+	return tlsConfig, nil
+}
+
+var serverTLSOnce sync.Once
+var serverTLSConfig *tls.Config
+var serverTLSErr error
+
+// getServerTLSConfig returns the tls config of the configured servers, built once,
+// so that the grpc server and the rest gateway share one certificate reloader
+func getServerTLSConfig(cfg types.ConfigTLS) (*tls.Config, error) {
+	serverTLSOnce.Do(func() {
+		serverTLSConfig, serverTLSErr = getTLSConfig(cfg)
+	})
+	return serverTLSConfig, serverTLSErr
+}
+
+// getTLSCredentials returns the grpc server credentials, or nil if tls is disabled
+func getTLSCredentials(tlsConfig *tls.Config) credentials.TransportCredentials {
+	if tlsConfig == nil {
+		return nil
+	}
+
+	return credentials.NewTLS(tlsConfig)
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/accuknox/auto-policy-discovery/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeSelfSignedCert writes a self-signed certificate and its key, and returns their paths
+func writeSelfSignedCert(t *testing.T, dir, commonName string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	return certFile, keyFile
+}
+
+func TestGetTLSConfigDisabled(t *testing.T) {
+	tlsConfig, err := getTLSConfig(types.ConfigTLS{Enable: false})
+	assert.NoError(t, err)
+	assert.Nil(t, tlsConfig)
+	assert.Nil(t, getTLSCredentials(tlsConfig))
+}
+
+func TestGetTLSConfigInvalid(t *testing.T) {
+	_, err := getTLSConfig(types.ConfigTLS{Enable: true})
+	assert.Error(t, err, "cert and key files should be required")
+
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir, "server")
+
+	_, err = getTLSConfig(types.ConfigTLS{Enable: true, CertFile: certFile, KeyFile: keyFile, VerifyClientCert: true})
+	assert.Error(t, err, "ca file should be required to verify client certs")
+}
+
+func TestGetServerTLSConfigOnce(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir, "server")
+	cfg := types.ConfigTLS{Enable: true, CertFile: certFile, KeyFile: keyFile}
+
+	serverTLSOnce = sync.Once{}
+	t.Cleanup(func() { serverTLSOnce = sync.Once{} })
+
+	// the grpc server and the rest gateway share one config
+	grpcConfig, err := getServerTLSConfig(cfg)
+	assert.NoError(t, err)
+	gatewayConfig, err := getServerTLSConfig(cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, grpcConfig)
+	assert.True(t, grpcConfig == gatewayConfig)
+}
+
+func TestGetTLSConfigALPN(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir, "server")
+
+	tlsConfig, err := getTLSConfig(types.ConfigTLS{Enable: true, CertFile: certFile, KeyFile: keyFile})
+	assert.NoError(t, err)
+
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	// the config of the handshake, built by the reloader, offers h2
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- tls.Server(serverConn, tlsConfig).Handshake()
+	}()
+
+	// #nosec G402 the self signed test certificate
+	client := tls.Client(clientConn, &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"h2"}})
+	assert.NoError(t, client.Handshake())
+	assert.NoError(t, <-serverErr)
+	assert.Equal(t, "h2", client.ConnectionState().NegotiatedProtocol)
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir, "server-1")
+
+	reloader, err := newCertReloader(types.ConfigTLS{
+		Enable:           true,
+		CertFile:         certFile,
+		KeyFile:          keyFile,
+		CAFile:           certFile,
+		VerifyClientCert: true,
+	})
+	assert.NoError(t, err)
+
+	tlsConfig := reloader.getConfigForClient(&tls.Config{MinVersion: tls.VersionTLS12})
+	assert.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+	assert.NotNil(t, tlsConfig.ClientCAs)
+
+	leaf, err := x509.ParseCertificate(tlsConfig.Certificates[0].Certificate[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "server-1", leaf.Subject.CommonName)
+
+	assert.False(t, reloader.isModified())
+
+	// replace the certificate, and make sure the modification time changes
+	writeSelfSignedCert(t, dir, "server-2")
+	future := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(certFile, future, future))
+
+	assert.True(t, reloader.isModified())
+	assert.NoError(t, reloader.load())
+
+	tlsConfig = reloader.getConfigForClient(&tls.Config{MinVersion: tls.VersionTLS12})
+
+	leaf, err = x509.ParseCertificate(tlsConfig.Certificates[0].Certificate[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "server-2", leaf.Subject.CommonName)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/accuknox/auto-policy-discovery/src/common"
 	"github.com/accuknox/auto-policy-discovery/src/config"
 	"github.com/accuknox/auto-policy-discovery/src/libs"
+	"github.com/accuknox/auto-policy-discovery/src/metrics"
 	types "github.com/accuknox/auto-policy-discovery/src/types"
 	"github.com/google/go-cmp/cmp"
 )
@@ -159,6 +160,7 @@ func UpdateProcessOperation(newPolicy types.KnoxSystemPolicy, existingPolicies [
 
 	// step 5: update latest -> outdated
 	libs.UpdateOutdatedSystemPolicy(config.GetCfgDB(), latestPolicy.Metadata["name"], newPolicy.Metadata["name"])
+	setReplacedPolicy(latestPolicy.Metadata["name"], newPolicy.Metadata["name"])
 
 	return newPolicy, true
 }
@@ -298,6 +300,7 @@ func UpdateFileOperation(newPolicy types.KnoxSystemPolicy, existingPolicies []ty
 
 	// step 5: update latest -> outdated
 	libs.UpdateOutdatedSystemPolicy(config.GetCfgDB(), latestPolicy.Metadata["name"], newPolicy.Metadata["name"])
+	setReplacedPolicy(latestPolicy.Metadata["name"], newPolicy.Metadata["name"])
 
 	return newPolicy, true
 }
@@ -308,6 +311,7 @@ func UpdateFileOperation(newPolicy types.KnoxSystemPolicy, existingPolicies []ty
 
 func UpdateDuplicatedPolicy(existingPolicies []types.KnoxSystemPolicy, discoveredPolicies []types.KnoxSystemPolicy, clusterName string) []types.KnoxSystemPolicy {
 	newPolicies := []types.KnoxSystemPolicy{}
+	updatedCount := 0
 
 	// update policy name map
 	policyNamesMap := map[string]bool{}
@@ -329,6 +333,7 @@ func UpdateDuplicatedPolicy(existingPolicies []types.KnoxSystemPolicy, discovere
 		if policy.Metadata["type"] == SYS_OP_FILE {
 			if updatedPolicy, updated := UpdateFileOperation(namedPolicy, existingPolicies); updated {
 				namedPolicy = updatedPolicy
+				updatedCount++
 			}
 		}
 
@@ -336,6 +341,7 @@ func UpdateDuplicatedPolicy(existingPolicies []types.KnoxSystemPolicy, discovere
 		if policy.Metadata["type"] == SYS_OP_PROCESS {
 			if updatedPolicy, updated := UpdateProcessOperation(namedPolicy, existingPolicies); updated {
 				namedPolicy = updatedPolicy
+				updatedCount++
 			}
 		}
 
@@ -352,5 +358,9 @@ func UpdateDuplicatedPolicy(existingPolicies []types.KnoxSystemPolicy, discovere
 		return newPolicies[i].Metadata["name"] < newPolicies[j].Metadata["name"]
 	})
 
+	// the updated policies replace the outdated ones, so they are inserted as well
+	metrics.AddDiscoveredPolicies(metrics.PolicyTypeSystem, clusterName, len(newPolicies)-updatedCount, updatedCount)
+	currentRun.AddPolicies(len(newPolicies)-updatedCount, updatedCount)
+
 	return newPolicies
 }
@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/clarketm/json"
@@ -21,6 +22,7 @@ import (
 	fc "github.com/accuknox/auto-policy-discovery/src/feedconsumer"
 	"github.com/accuknox/auto-policy-discovery/src/libs"
 	logger "github.com/accuknox/auto-policy-discovery/src/logging"
+	"github.com/accuknox/auto-policy-discovery/src/metrics"
 	"github.com/accuknox/auto-policy-discovery/src/plugin"
 	wpb "github.com/accuknox/auto-policy-discovery/src/protobuf/v1/worker"
 	types "github.com/accuknox/auto-policy-discovery/src/types"
@@ -75,6 +77,14 @@ var SystemCronJob *cron.Cron
 var SystemStopChan chan struct{} // for hubble
 var OperationTrigger int
 
+// currentRun is the record of the discovery run in progress
+var currentRun *types.DiscoveryRun
+
+// replacedPolicies maps the name of each policy outdated by the deduplication to the name of the policy replacing it,
+// the analyzer may run the discovery along with the worker
+var replacedPolicies = map[string]string{}
+var replacedPoliciesMutex sync.Mutex
+
 var SystemLogLimit int
 var SystemLogFrom string
 var SystemLogFile string
@@ -1049,9 +1059,12 @@ func PopulateSystemPoliciesFromSystemLogs(sysLogs []types.KnoxSystemLog) []types
 			pod, err := getPodInstance(sysKey, pods)
 			if err != nil {
 				log.Error().Msg(err.Error())
+				currentRun.AddError(err)
 				continue
 			}
+			currentRun.AddNamespace(clusterName, pod.Namespace)
 
+			discoveryStart := time.Now()
 			polCnt := 0
 			isWpfsDbUpdated := false
 			// 1. discover file operation system policy
@@ -1103,17 +1116,26 @@ func PopulateSystemPoliciesFromSystemLogs(sysLogs []types.KnoxSystemLog) []types
 					// insert discovered policies to db
 					if strings.Contains(SystemPolicyTo, "db") {
 						libs.InsertSystemPolicies(CfgDB, newPolicies)
+						// the yamls name the policies they replace, before these are marked outdated
+						insertSysPoliciesYamlToDB(newPolicies)
 					}
+					markOutdatedPolicyYamls()
 
 					log.Info().Msgf("system policy discovery done for [%s/%s/%s], [%d] policies discovered",
 						clusterName, pod.Namespace, pod.PodName, len(newPolicies))
 				}
 			}
 
+			metrics.ObserveDiscovery(metrics.PolicyTypeSystem, clusterName, pod.Namespace, discoveryStart)
+
 			if strings.Contains(SystemPolicyTo, "file") {
 				WriteSystemPoliciesToFile(sysKey.Namespace, "", "", "")
 			}
 		}
+
+		// record the progress of the run at each cluster
+		currentRun.AddNamespace(clusterName, "")
+		libs.SaveDiscoveryRun(CfgDB, currentRun)
 	}
 
 	return discoveredSystemPolicies
@@ -1341,19 +1363,82 @@ func insertSysPoliciesYamlToDB(policies []types.KnoxSystemPolicy) {
 			Namespace: kubearmorPolicy.Metadata["namespace"],
 			Cluster:   clusters[i],
 			Labels:    kubearmorPolicy.Spec.Selector.MatchLabels,
+			Replaces:  getReplacedPolicy(kubearmorPolicy.Metadata["name"]),
 			Yaml:      yamlBytes,
 		}
 		res = append(res, policyYaml)
-
-		PolicyStore.Publish(&policyYaml)
 	}
 
-	if err := libs.UpdateOrInsertPolicyYamls(CfgDB, res); err != nil {
+	// the policies are published once written, with their revisions
+	if err := PolicyStore.UpdateAndPublish(CfgDB, res); err != nil {
 		log.Error().Msgf(err.Error())
+		currentRun.AddError(err)
+	}
+}
+
+// setReplacedPolicy records the policy outdated by the new one
+func setReplacedPolicy(outdated, newName string) {
+	replacedPoliciesMutex.Lock()
+	defer replacedPoliciesMutex.Unlock()
+
+	replacedPolicies[outdated] = newName
+}
+
+// getReplacedPolicy returns the name of the policy outdated by the given one, if any
+func getReplacedPolicy(name string) string {
+	replacedPoliciesMutex.Lock()
+	defer replacedPoliciesMutex.Unlock()
+
+	for outdated, newName := range replacedPolicies {
+		if newName == name {
+			return outdated
+		}
+	}
+	return ""
+}
+
+// markOutdatedPolicyYamls publishes the outdated event of the policies replaced since the last call
+func markOutdatedPolicyYamls() {
+	replacedPoliciesMutex.Lock()
+	replaced := replacedPolicies
+	replacedPolicies = map[string]string{}
+	replacedPoliciesMutex.Unlock()
+
+	if len(replaced) == 0 {
+		return
+	}
+
+	if err := PolicyStore.MarkPolicyYamls(CfgDB, types.PolicyTypeSystem, types.PolicyEventOutdated, replaced); err != nil {
+		log.Error().Msgf("Failed to mark the outdated system policies: %v", err)
+		currentRun.AddError(err)
 	}
 }
 
+// PublishDeletedPolicies publishes the deleted event of all the live system policies, once they are cleared from the db
+func PublishDeletedPolicies(cfgDB types.ConfigDB) {
+	if err := PolicyStore.MarkPolicyYamls(cfgDB, types.PolicyTypeSystem, types.PolicyEventDeleted, nil); err != nil {
+		log.Error().Msgf("Failed to mark the deleted system policies: %v", err)
+	}
+}
+
+// discoverSystemPolicyRun discovers the policies from the system logs, and records the run in the db
+func discoverSystemPolicyRun(trigger string, systemLogs []types.KnoxSystemLog) {
+	currentRun = libs.StartDiscoveryRun(CfgDB, types.PolicyTypeSystem, trigger, len(systemLogs))
+	defer func() {
+		currentRun.Finish()
+		libs.SaveDiscoveryRun(CfgDB, currentRun)
+		currentRun = nil
+	}()
+
+	PopulateSystemPoliciesFromSystemLogs(systemLogs)
+}
+
+// DiscoverSystemPolicyMain is the discovery job of the cron
 func DiscoverSystemPolicyMain() {
+	discoverSystemPolicyMain(types.DiscoveryTriggerCron)
+}
+
+func discoverSystemPolicyMain(trigger string) {
 	if SystemWorkerStatus == STATUS_RUNNING {
 		return
 	}
@@ -1372,17 +1457,53 @@ func DiscoverSystemPolicyMain() {
 		return
 	}
 
-	PopulateSystemPoliciesFromSystemLogs(allSystemkLogs)
+	discoverSystemPolicyRun(trigger, allSystemkLogs)
+}
+
+// DiscoverSystemPolicyPending runs a last discovery on the buffered system logs regardless of the operation trigger,
+// so that the logs received since the last run are not lost on shutdown
+func DiscoverSystemPolicyPending() {
+	if SystemWorkerStatus == STATUS_RUNNING {
+		return
+	}
+
+	SystemWorkerStatus = STATUS_RUNNING
+	defer func() {
+		SystemWorkerStatus = STATUS_IDLE
+	}()
+
+	InitSysPolicyDiscoveryConfiguration()
+
+	// the file is read from the beginning at each run, there is nothing pending
+	if SystemLogFrom != "kubearmor" && SystemLogFrom != "feed-consumer" {
+		return
+	}
+
+	OperationTrigger = 1
+
+	allSystemLogs := getSystemLogs()
+	if len(allSystemLogs) == 0 {
+		return
+	}
+
+	log.Info().Msgf("Discovering system policies from [%d] pending system logs", len(allSystemLogs))
+	discoverSystemPolicyRun(types.DiscoveryTriggerShutdown, allSystemLogs)
 }
 
 // ==================================== //
 // == System Policy Discovery Worker == //
 // ==================================== //
 
-func StartSystemLogRcvr() {
+func StartSystemLogRcvr(stopChan chan struct{}) {
 	for {
+		select {
+		case <-stopChan:
+			return
+		default:
+		}
+
 		if cfg.GetCfgSystemLogFrom() == "kubearmor" {
-			plugin.StartKubeArmorRelay(SystemStopChan, cfg.GetCfgKubeArmor())
+			plugin.StartKubeArmorRelay(stopChan, cfg.GetCfgKubeArmor())
 		} else if cfg.GetCfgSystemLogFrom() == "feed-consumer" {
 			fc.ConsumerMutex.Lock()
 			fc.StartConsumer()
@@ -1393,7 +1514,9 @@ func StartSystemLogRcvr() {
 }
 
 func StartSystemCronJob() {
-	go StartSystemLogRcvr()
+	// the previous stop channel is closed if the worker has been stopped before
+	SystemStopChan = make(chan struct{})
+	go StartSystemLogRcvr(SystemStopChan)
 
 	// init cron job
 	SystemCronJob = cron.New()
@@ -1419,7 +1542,8 @@ func StopSystemCronJob() {
 	}
 }
 
-func StartSystemWorker() {
+// StartSystemWorker starts the cron job, or runs the one-time discovery recorded with the given trigger
+func StartSystemWorker(trigger string) {
 	if SystemWorkerStatus != STATUS_IDLE {
 		log.Info().Msg("There is no idle system policy discovery worker")
 
@@ -1431,7 +1555,7 @@ func StartSystemWorker() {
 	} else if cfg.GetCfgSysOperationMode() == OP_MODE_CRONJOB { // every time intervals
 		StartSystemCronJob()
 	} else { // one-time generation
-		DiscoverSystemPolicyMain()
+		discoverSystemPolicyMain(trigger)
 		log.Info().Msgf("Auto system policy discovery onetime job done")
 	}
 }
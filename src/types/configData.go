@@ -29,6 +29,14 @@ type NetworkLogFilter struct {
 	PortNumber           string   `json:"port_number,omitempty" bson:"port_number,omitempty"`
 }
 
+// HTTPOpenAPISpec points the pods of a service, by name or labels, at the openapi document of their http api
+type HTTPOpenAPISpec struct {
+	Namespace string   `json:"namespace,omitempty" bson:"namespace,omitempty"`
+	Service   string   `json:"service,omitempty" bson:"service,omitempty"`
+	Labels    []string `json:"labels,omitempty" bson:"labels,omitempty"`
+	File      string   `json:"file,omitempty" bson:"file,omitempty"`
+}
+
 type ConfigNetworkPolicy struct {
 	OperationMode           int `json:"operation_mode,omitempty" bson:"operation_mode,omitempty"`
 	OperationTrigger        int
@@ -41,6 +49,10 @@ type ConfigNetworkPolicy struct {
 	NetworkPolicyTo  string `json:"network_policy_to,omitempty" bson:"network_policy_to,omitempty"`
 	NetworkPolicyDir string `json:"network_policy_dir,omitempty" bson:"network_policy_dir,omitempty"`
 
+	NetworkPolicyFormats []string `json:"network_policy_formats,omitempty" bson:"network_policy_formats,omitempty"`
+
+	AdminPolicyMinNamespaces int `json:"admin_policy_min_namespaces,omitempty" bson:"admin_policy_min_namespaces,omitempty"`
+
 	NsFilter    []string `json:"network_policy_ns_filter,omitempty" bson:"network_policy_ns_filter,omitempty"`
 	NsNotFilter []string `json:"network_policy_ns_not_filter,omitempty" bson:"network_policy_ns_not_filter,omitempty"`
 
@@ -48,6 +60,30 @@ type ConfigNetworkPolicy struct {
 	NetPolicyRuleTypes int `json:"network_policy_rule_types,omitempty" bson:"network_policy_rule_types,omitempty"`
 	NetPolicyCIDRBits  int `json:"network_policy_cidrbits,omitempty" bson:"network_policy_cidrbits,omitempty"`
 
+	NetPolicyCIDRv6Bits int  `json:"network_policy_cidrv6bits,omitempty" bson:"network_policy_cidrv6bits,omitempty"`
+	WorldCIDRs          bool `json:"world_cidrs,omitempty" bson:"world_cidrs,omitempty"`
+
+	CIDRAggregationMinIPs      int    `json:"cidr_aggregation_min_ips,omitempty" bson:"cidr_aggregation_min_ips,omitempty"`
+	CIDRAggregationMaxPrefix   int    `json:"cidr_aggregation_max_prefix,omitempty" bson:"cidr_aggregation_max_prefix,omitempty"`
+	CIDRAggregationMaxPrefixV6 int    `json:"cidr_aggregation_max_prefix_v6,omitempty" bson:"cidr_aggregation_max_prefix_v6,omitempty"`
+	CIDRProviderRangesFile     string `json:"cidr_provider_ranges_file,omitempty" bson:"cidr_provider_ranges_file,omitempty"`
+
+	HTTPOpenAPISpecs []HTTPOpenAPISpec `json:"http_openapi_specs,omitempty" bson:"http_openapi_specs,omitempty"`
+
+	FQDNPatternMinNames int    `json:"fqdn_pattern_min_names,omitempty" bson:"fqdn_pattern_min_names,omitempty"`
+	ClusterDomain       string `json:"cluster_domain,omitempty" bson:"cluster_domain,omitempty"`
+
+	HTTPRuleHost    bool     `json:"http_rule_host,omitempty" bson:"http_rule_host,omitempty"`
+	HTTPRuleHeaders []string `json:"http_rule_headers,omitempty" bson:"http_rule_headers,omitempty"`
+	HTTPSkipErrors  bool     `json:"http_skip_errors,omitempty" bson:"http_skip_errors,omitempty"`
+
+	PortRangeMinPorts int `json:"port_range_min_ports,omitempty" bson:"port_range_min_ports,omitempty"`
+	PortRangeMaxGap   int `json:"port_range_max_gap,omitempty" bson:"port_range_max_gap,omitempty"`
+
+	LabelPolicyInclude       []string `json:"label_policy_include,omitempty" bson:"label_policy_include,omitempty"`
+	LabelPolicyExclude       []string `json:"label_policy_exclude,omitempty" bson:"label_policy_exclude,omitempty"`
+	LabelPolicyPreferredKeys []string `json:"label_policy_preferred_keys,omitempty" bson:"label_policy_preferred_keys,omitempty"`
+
 	NetLogFilters []NetworkLogFilter `json:"network_policy_log_filters,omitempty" bson:"network_policy_log_filters,omitempty"`
 
 	NetPolicyL3Level int `json:"network_policy_l3_level,omitempty" bson:"network_policy_l3_level,omitempty"`
@@ -110,6 +146,53 @@ type ConfigPublisher struct {
 	CronJobTimeInterval string `json:"cronjob_time_interval,omitempty" bson:"cronjob_time_interval,omitempty"`
 }
 
+type ConfigTLS struct {
+	Enable           bool   `json:"enable,omitempty" bson:"enable,omitempty"`
+	CertFile         string `json:"cert_file,omitempty" bson:"cert_file,omitempty"`
+	KeyFile          string `json:"key_file,omitempty" bson:"key_file,omitempty"`
+	CAFile           string `json:"ca_file,omitempty" bson:"ca_file,omitempty"`
+	VerifyClientCert bool   `json:"verify_client_cert,omitempty" bson:"verify_client_cert,omitempty"`
+	ReloadInterval   string `json:"reload_interval,omitempty" bson:"reload_interval,omitempty"`
+}
+
+type AuthzIdentity struct {
+	Name    string `json:"name,omitempty" bson:"name,omitempty"`
+	Token   string `json:"token,omitempty" bson:"token,omitempty"`     // static bearer token
+	Subject string `json:"subject,omitempty" bson:"subject,omitempty"` // mTLS client certificate common name
+	Role    string `json:"role,omitempty" bson:"role,omitempty"`
+}
+
+type AuthzPermission struct {
+	Method string `json:"method,omitempty" bson:"method,omitempty"` // e.g., /v1.worker.Worker/Start
+	Role   string `json:"role,omitempty" bson:"role,omitempty"`     // minimum role required
+}
+
+type ConfigAuthz struct {
+	Enable      bool              `json:"enable,omitempty" bson:"enable,omitempty"`
+	Identities  []AuthzIdentity   `json:"identities,omitempty" bson:"identities,omitempty"`
+	Permissions []AuthzPermission `json:"permissions,omitempty" bson:"permissions,omitempty"`
+}
+
+type ConfigHTTPGateway struct {
+	Enable bool   `json:"enable,omitempty" bson:"enable,omitempty"`
+	Port   string `json:"port,omitempty" bson:"port,omitempty"`
+}
+
+type ConfigMetrics struct {
+	Enable bool   `json:"enable,omitempty" bson:"enable,omitempty"`
+	Port   string `json:"port,omitempty" bson:"port,omitempty"`
+}
+
+type ConfigShutdown struct {
+	Timeout        string `json:"timeout,omitempty" bson:"timeout,omitempty"`
+	FinalDiscovery bool   `json:"final_discovery,omitempty" bson:"final_discovery,omitempty"`
+}
+
+type ConfigPolicyStream struct {
+	QueueSize      int    `json:"queue_size,omitempty" bson:"queue_size,omitempty"`
+	OverflowPolicy string `json:"overflow_policy,omitempty" bson:"overflow_policy,omitempty"`
+}
+
 type Configuration struct {
 	ConfigName string `json:"config_name,omitempty" bson:"config_name,omitempty"`
 	Status     int    `json:"status,omitempty" bson:"status,omitempty"`
@@ -126,4 +209,14 @@ type Configuration struct {
 	ConfigClusterMgmt   ConfigClusterMgmt   `json:"config_cluster_mgmt,omitempty" bson:"config_cluster_mgmt,omitempty"`
 	ConfigObservability ConfigObservability `json:"config_observability,omitempty" bson:"config_observability,omitempty"`
 	ConfigPublisher     ConfigPublisher     `json:"config_summarizer,omitempty" bson:"config_summarizer,omitempty"`
+
+	// server settings, loaded only from the configuration file
+	ConfigTLS   ConfigTLS   `json:"-" bson:"-"`
+	ConfigAuthz ConfigAuthz `json:"-" bson:"-"`
+
+	ConfigHTTPGateway ConfigHTTPGateway `json:"-" bson:"-"`
+	ConfigMetrics     ConfigMetrics     `json:"-" bson:"-"`
+	ConfigShutdown    ConfigShutdown    `json:"-" bson:"-"`
+
+	ConfigPolicyStream ConfigPolicyStream `json:"-" bson:"-"`
 }
@@ -28,6 +28,17 @@ const (
 	// Kubernetes Policy
 	KindK8sNetworkPolicy = "NetworkPolicy"
 
+	// Calico Policy, the yaml kinds are NetworkPolicy and GlobalNetworkPolicy of projectcalico.org/v3
+	KindCalicoNetworkPolicy       = "CalicoNetworkPolicy"
+	KindCalicoGlobalNetworkPolicy = "CalicoGlobalNetworkPolicy"
+
+	// Istio Policy
+	KindIstioAuthorizationPolicy = "AuthorizationPolicy"
+
+	// Admin Network Policy, cluster scoped
+	KindAdminNetworkPolicy         = "AdminNetworkPolicy"
+	KindBaselineAdminNetworkPolicy = "BaselineAdminNetworkPolicy"
+
 	// KubeArmor Policy
 	KindKubeArmorPolicy     = "KubeArmorPolicy"
 	KindKubeArmorHostPolicy = "KubeArmorHostPolicy"
@@ -41,4 +52,23 @@ const (
 	// K8sNetworkPolicy
 	K8sNwPolicyAPIVersion = "networking.k8s.io/v1"
 	K8sNwPolicyKind       = "NetworkPolicy"
+
+	// CalicoNetworkPolicy
+	CalicoPolicyAPIVersion   = "projectcalico.org/v3"
+	CalicoNwPolicyKind       = "NetworkPolicy"
+	CalicoGlobalNwPolicyKind = "GlobalNetworkPolicy"
+
+	// IstioAuthorizationPolicy
+	IstioPolicyAPIVersion = "security.istio.io/v1beta1"
+	IstioActionAllow      = "ALLOW"
+
+	// AdminNetworkPolicy
+	AdminNwPolicyAPIVersion  = "policy.networking.k8s.io/v1alpha1"
+	AdminNwPolicyActionAllow = "Allow"
+	AdminNwPolicyActionDeny  = "Deny"
+
+	// Network policy formats stored in the db
+	NetworkPolicyFormatCilium = "cilium"
+	NetworkPolicyFormatCalico = "calico"
+	NetworkPolicyFormatIstio  = "istio"
 )
@@ -14,7 +14,7 @@ type KnoxNetworkLog struct {
 	DstReservedLabels []string `json:"dst_reserved_labels,omitempty" bson:"dst_reserved_labels"`
 	DstPodName        string   `json:"dst_pod_name,omitempty" bson:"dst_pod_name"`
 
-	EtherType int `json:"ether_type,omitempty" bson:"ether_type"` // not used, we assume all the ipv4
+	EtherType int `json:"ether_type,omitempty" bson:"ether_type"` // ipv4 (0x0800) or ipv6 (0x86DD)
 
 	Protocol int    `json:"protocol,omitempty" bson:"protocol"`
 	SrcIP    string `json:"src_ip,omitempty" bson:"src_ip"`
@@ -35,6 +35,13 @@ type KnoxNetworkLog struct {
 	HTTPMethod string `json:"http_method,omitempty" bson:"http_method"` // for L7 http
 	HTTPPath   string `json:"http_path,omitempty" bson:"http_path"`     // for L7 http
 
+	HTTPHost    string   `json:"http_host,omitempty" bson:"http_host"`       // for L7 http
+	HTTPHeaders []string `json:"http_headers,omitempty" bson:"http_headers"` // for L7 http, the configured headers
+	HTTPCode    int      `json:"http_code,omitempty" bson:"http_code"`       // for L7 http, the code of a response
+
+	KafkaAPIKey string `json:"kafka_api_key,omitempty" bson:"kafka_api_key"` // for L7 kafka
+	KafkaTopic  string `json:"kafka_topic,omitempty" bson:"kafka_topic"`     // for L7 kafka
+
 	Direction string `json:"direction,omitempty" bson:"direction"` // ingress or egress
 
 	Action string `json:"action,omitempty" bson:"action"`
@@ -1,5 +1,10 @@
 package types
 
+import (
+	"strconv"
+	"time"
+)
+
 // LabelMap stores the label of an endpoint
 type LabelMap = map[string]string
 
@@ -27,10 +32,38 @@ func (x SpecICMP) Equal(y SpecICMP) bool {
 type SpecPort struct {
 	Port     string `json:"port,omitempty" yaml:"port,omitempty" bson:"port,omitempty"`
 	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty" bson:"protocol,omitempty"`
+
+	// the ports from Port to EndPort, if EndPort is set
+	EndPort int `json:"endPort,omitempty" yaml:"endPort,omitempty" bson:"endPort,omitempty"`
 }
 
 func (x SpecPort) Equal(y SpecPort) bool {
-	return x.Port == y.Port && x.Protocol == y.Protocol
+	return x.Port == y.Port && x.Protocol == y.Protocol && x.EndPort == y.EndPort
+}
+
+// Contains checks if the port or the range of y is in the range of x
+func (x SpecPort) Contains(y SpecPort) bool {
+	if x.Equal(y) {
+		return true
+	}
+	if x.EndPort == 0 || x.Protocol != y.Protocol {
+		return false
+	}
+
+	start, err := strconv.Atoi(x.Port)
+	if err != nil {
+		return false
+	}
+	port, err := strconv.Atoi(y.Port)
+	if err != nil {
+		return false
+	}
+
+	end := port
+	if y.EndPort != 0 {
+		end = y.EndPort
+	}
+	return start <= port && end <= x.EndPort
 }
 
 // SpecService Structure
@@ -41,7 +74,8 @@ type SpecService struct {
 
 // SpecFQDN Structure
 type SpecFQDN struct {
-	MatchNames []string `json:"matchNames,omitempty" yaml:"matchNames,omitempty" bson:"matchNames,omitempty"`
+	MatchNames    []string `json:"matchNames,omitempty" yaml:"matchNames,omitempty" bson:"matchNames,omitempty"`
+	MatchPatterns []string `json:"matchPatterns,omitempty" yaml:"matchPatterns,omitempty" bson:"matchPatterns,omitempty"`
 }
 
 // SpecHTTP Structure
@@ -49,6 +83,18 @@ type SpecHTTP struct {
 	Method     string `json:"method,omitempty" yaml:"method,omitempty" bson:"method,omitempty"`
 	Path       string `json:"path,omitempty" yaml:"path,omitempty" bson:"path,omitempty"`
 	Aggregated bool   `json:"aggregated,omitempty" yaml:"aggregated,omitempty" bson:"aggregated,omitempty"`
+
+	// the headers are "name: value"
+	Host    string   `json:"host,omitempty" yaml:"host,omitempty" bson:"host,omitempty"`
+	Headers []string `json:"headers,omitempty" yaml:"headers,omitempty" bson:"headers,omitempty"`
+}
+
+// SpecKafka Structure, a role covers the api keys of producing or consuming the topic
+type SpecKafka struct {
+	Role     string `json:"role,omitempty" yaml:"role,omitempty" bson:"role,omitempty"`
+	APIKey   string `json:"apiKey,omitempty" yaml:"apiKey,omitempty" bson:"apiKey,omitempty"`
+	Topic    string `json:"topic,omitempty" yaml:"topic,omitempty" bson:"topic,omitempty"`
+	ClientID string `json:"clientID,omitempty" yaml:"clientID,omitempty" bson:"clientID,omitempty"`
 }
 
 // Selector Structure
@@ -62,6 +108,7 @@ type Ingress struct {
 	ICMPs       []SpecICMP        `json:"icmps,omitempty" yaml:"icmps,omitempty" bson:"icmps,omitempty"`
 	ToPorts     []SpecPort        `json:"toPorts,omitempty" yaml:"toPorts,omitempty" bson:"toPorts,omitempty"`
 	ToHTTPs     []SpecHTTP        `json:"toHTTPs,omitempty" yaml:"toHTTPs,omitempty" bson:"toHTTPs,omitempty"`
+	ToKafkas    []SpecKafka       `json:"toKafkas,omitempty" yaml:"toKafkas,omitempty" bson:"toKafkas,omitempty"`
 
 	FromCIDRs    []SpecCIDR `json:"fromCIDRs,omitempty" yaml:"fromCIDRs,omitempty" bson:"fromCIDRs,omitempty"`
 	FromEntities []string   `json:"fromEntities,omitempty" yaml:"fromEntities,omitempty" bson:"fromEntities,omitempty"`
@@ -78,12 +125,14 @@ type Egress struct {
 	ToServices []SpecService `json:"toServices,omitempty" yaml:"toServices,omitempty" bson:"toServices,omitempty"`
 	ToFQDNs    []SpecFQDN    `json:"toFQDNs,omitempty" yaml:"toFQDNs,omitempty" bson:"toFQDNs,omitempty"`
 	ToHTTPs    []SpecHTTP    `json:"toHTTPs,omitempty" yaml:"toHTTPs,omitempty" bson:"toHTTPs,omitempty"`
+	ToKafkas   []SpecKafka   `json:"toKafkas,omitempty" yaml:"toKafkas,omitempty" bson:"toKafkas,omitempty"`
 }
 
 type L47Rule interface {
 	GetICMPRules() []SpecICMP
 	GetPortRules() []SpecPort
 	GetHTTPRules() []SpecHTTP
+	GetKafkaRules() []SpecKafka
 }
 
 func (x Ingress) GetICMPRules() []SpecICMP {
@@ -98,6 +147,10 @@ func (x Ingress) GetHTTPRules() []SpecHTTP {
 	return x.ToHTTPs
 }
 
+func (x Ingress) GetKafkaRules() []SpecKafka {
+	return x.ToKafkas
+}
+
 func (x Egress) GetICMPRules() []SpecICMP {
 	return x.ICMPs
 }
@@ -110,6 +163,10 @@ func (x Egress) GetHTTPRules() []SpecHTTP {
 	return x.ToHTTPs
 }
 
+func (x Egress) GetKafkaRules() []SpecKafka {
+	return x.ToKafkas
+}
+
 // Spec Structure
 type Spec struct {
 	Selector Selector `json:"selector,omitempty" yaml:"selector,omitempty" bson:"selector,omitempty"`
@@ -134,6 +191,15 @@ type KnoxNetworkPolicy struct {
 	UpdatedTime   int64 `json:"updatedTime,omitempty" yaml:"updatedTime,omitempty" bson:"updatedTime,omitempty"`
 }
 
+// UnsupportedPolicyRule is a knox rule the target policy format cannot express
+type UnsupportedPolicyRule struct {
+	PolicyName string `json:"policy_name"`
+	Namespace  string `json:"namespace"`
+	Direction  string `json:"direction"`
+	Rule       string `json:"rule"`
+	Reason     string `json:"reason"`
+}
+
 // =========================== //
 // == Cilium Network Policy == //
 // =========================== //
@@ -147,11 +213,12 @@ type CiliumCIDRSet struct {
 // CiliumPort Structure
 type CiliumPort struct {
 	Port     string `json:"port,omitempty" yaml:"port,omitempty"`
+	EndPort  int    `json:"endPort,omitempty" yaml:"endPort,omitempty"`
 	Protocol string `json:"protocol" yaml:"protocol"`
 }
 
-// SubRule ...
-type SubRule map[string]string
+// SubRule ..., the values are strings, or lists as the http headers
+type SubRule map[string]interface{}
 
 // CiliumFQDN ...
 type CiliumFQDN map[string]string
@@ -226,6 +293,196 @@ type CiliumNetworkPolicy struct {
 	Spec       CiliumSpec        `json:"spec" yaml:"spec"`
 }
 
+// =========================== //
+// == Calico Network Policy == //
+// =========================== //
+
+// CalicoServiceMatch Structure
+type CalicoServiceMatch struct {
+	Name      string `json:"name" yaml:"name"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+// CalicoEntityRule Structure
+type CalicoEntityRule struct {
+	Selector          string              `json:"selector,omitempty" yaml:"selector,omitempty"`
+	NamespaceSelector string              `json:"namespaceSelector,omitempty" yaml:"namespaceSelector,omitempty"`
+	Nets              []string            `json:"nets,omitempty" yaml:"nets,omitempty"`
+	NotNets           []string            `json:"notNets,omitempty" yaml:"notNets,omitempty"`
+	Ports             []interface{}       `json:"ports,omitempty" yaml:"ports,omitempty"`
+	Domains           []string            `json:"domains,omitempty" yaml:"domains,omitempty"`
+	Services          *CalicoServiceMatch `json:"services,omitempty" yaml:"services,omitempty"`
+}
+
+// CalicoICMP Structure
+type CalicoICMP struct {
+	Type int `json:"type" yaml:"type"`
+}
+
+// CalicoHTTPPath Structure
+type CalicoHTTPPath struct {
+	Exact  string `json:"exact,omitempty" yaml:"exact,omitempty"`
+	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+}
+
+// CalicoHTTPMatch Structure
+type CalicoHTTPMatch struct {
+	Methods []string         `json:"methods,omitempty" yaml:"methods,omitempty"`
+	Paths   []CalicoHTTPPath `json:"paths,omitempty" yaml:"paths,omitempty"`
+}
+
+// CalicoRule Structure
+type CalicoRule struct {
+	Action      string           `json:"action" yaml:"action"`
+	Protocol    string           `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	ICMP        *CalicoICMP      `json:"icmp,omitempty" yaml:"icmp,omitempty"`
+	Source      CalicoEntityRule `json:"source,omitempty" yaml:"source,omitempty"`
+	Destination CalicoEntityRule `json:"destination,omitempty" yaml:"destination,omitempty"`
+	HTTP        *CalicoHTTPMatch `json:"http,omitempty" yaml:"http,omitempty"`
+}
+
+// CalicoSpec Structure
+type CalicoSpec struct {
+	Selector string       `json:"selector" yaml:"selector"`
+	Types    []string     `json:"types,omitempty" yaml:"types,omitempty"`
+	Ingress  []CalicoRule `json:"ingress,omitempty" yaml:"ingress,omitempty"`
+	Egress   []CalicoRule `json:"egress,omitempty" yaml:"egress,omitempty"`
+}
+
+// CalicoNetworkPolicy Structure, a NetworkPolicy or a GlobalNetworkPolicy
+type CalicoNetworkPolicy struct {
+	APIVersion string            `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string            `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Spec       CalicoSpec        `json:"spec" yaml:"spec"`
+}
+
+// ================================ //
+// == Istio Authorization Policy == //
+// ================================ //
+
+// IstioSource Structure
+type IstioSource struct {
+	Principals  []string `json:"principals,omitempty" yaml:"principals,omitempty"`
+	Namespaces  []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	IPBlocks    []string `json:"ipBlocks,omitempty" yaml:"ipBlocks,omitempty"`
+	NotIPBlocks []string `json:"notIpBlocks,omitempty" yaml:"notIpBlocks,omitempty"`
+}
+
+// IstioFrom Structure
+type IstioFrom struct {
+	Source IstioSource `json:"source" yaml:"source"`
+}
+
+// IstioOperation Structure
+type IstioOperation struct {
+	Ports   []string `json:"ports,omitempty" yaml:"ports,omitempty"`
+	Methods []string `json:"methods,omitempty" yaml:"methods,omitempty"`
+	Paths   []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+	Hosts   []string `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+}
+
+// IstioTo Structure
+type IstioTo struct {
+	Operation IstioOperation `json:"operation" yaml:"operation"`
+}
+
+// IstioRule Structure
+type IstioRule struct {
+	From []IstioFrom `json:"from,omitempty" yaml:"from,omitempty"`
+	To   []IstioTo   `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// IstioSpec Structure
+type IstioSpec struct {
+	Selector Selector    `json:"selector" yaml:"selector"`
+	Action   string      `json:"action" yaml:"action"`
+	Rules    []IstioRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// IstioAuthorizationPolicy Structure
+type IstioAuthorizationPolicy struct {
+	APIVersion string            `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string            `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Spec       IstioSpec         `json:"spec" yaml:"spec"`
+}
+
+// ========================== //
+// == Admin Network Policy == //
+// ========================== //
+
+// LabelSelectorRequirement Structure
+type LabelSelectorRequirement struct {
+	Key      string   `json:"key" yaml:"key"`
+	Operator string   `json:"operator" yaml:"operator"`
+	Values   []string `json:"values,omitempty" yaml:"values,omitempty"`
+}
+
+// AdminNetworkPolicySelector Structure, a label selector, empty selects all
+type AdminNetworkPolicySelector struct {
+	MatchLabels      map[string]string          `json:"matchLabels,omitempty" yaml:"matchLabels,omitempty"`
+	MatchExpressions []LabelSelectorRequirement `json:"matchExpressions,omitempty" yaml:"matchExpressions,omitempty"`
+}
+
+// AdminNetworkPolicyPods Structure
+type AdminNetworkPolicyPods struct {
+	NamespaceSelector AdminNetworkPolicySelector `json:"namespaceSelector" yaml:"namespaceSelector"`
+	PodSelector       AdminNetworkPolicySelector `json:"podSelector" yaml:"podSelector"`
+}
+
+// AdminNetworkPolicyPeer Structure, also used as the subject of the policy
+type AdminNetworkPolicyPeer struct {
+	Namespaces *AdminNetworkPolicySelector `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Pods       *AdminNetworkPolicyPods     `json:"pods,omitempty" yaml:"pods,omitempty"`
+	Networks   []string                    `json:"networks,omitempty" yaml:"networks,omitempty"`
+}
+
+// AdminNetworkPolicyPortNumber Structure
+type AdminNetworkPolicyPortNumber struct {
+	Protocol string `json:"protocol" yaml:"protocol"`
+	Port     int    `json:"port" yaml:"port"`
+}
+
+// AdminNetworkPolicyPort Structure
+type AdminNetworkPolicyPort struct {
+	PortNumber *AdminNetworkPolicyPortNumber `json:"portNumber,omitempty" yaml:"portNumber,omitempty"`
+	NamedPort  string                        `json:"namedPort,omitempty" yaml:"namedPort,omitempty"`
+	PortRange  *AdminNetworkPolicyPortRange  `json:"portRange,omitempty" yaml:"portRange,omitempty"`
+}
+
+// AdminNetworkPolicyPortRange Structure
+type AdminNetworkPolicyPortRange struct {
+	Protocol string `json:"protocol" yaml:"protocol"`
+	Start    int    `json:"start" yaml:"start"`
+	End      int    `json:"end" yaml:"end"`
+}
+
+// AdminNetworkPolicyRule Structure, the peers are To in the egress rules and From in the ingress rules
+type AdminNetworkPolicyRule struct {
+	Name   string                   `json:"name" yaml:"name"`
+	Action string                   `json:"action" yaml:"action"`
+	To     []AdminNetworkPolicyPeer `json:"to,omitempty" yaml:"to,omitempty"`
+	From   []AdminNetworkPolicyPeer `json:"from,omitempty" yaml:"from,omitempty"`
+	Ports  []AdminNetworkPolicyPort `json:"ports,omitempty" yaml:"ports,omitempty"`
+}
+
+// AdminNetworkPolicySpec Structure
+type AdminNetworkPolicySpec struct {
+	Priority int                      `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Subject  AdminNetworkPolicyPeer   `json:"subject" yaml:"subject"`
+	Ingress  []AdminNetworkPolicyRule `json:"ingress,omitempty" yaml:"ingress,omitempty"`
+	Egress   []AdminNetworkPolicyRule `json:"egress,omitempty" yaml:"egress,omitempty"`
+}
+
+// AdminNetworkPolicy Structure, an AdminNetworkPolicy or a BaselineAdminNetworkPolicy
+type AdminNetworkPolicy struct {
+	APIVersion string                 `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string                 `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Metadata   map[string]string      `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Spec       AdminNetworkPolicySpec `json:"spec" yaml:"spec"`
+}
+
 // ======================== //
 // == Knox System Policy == //
 // ======================== //
@@ -318,15 +575,126 @@ type PolicyFilter struct {
 	Cluster   string
 	Namespace string
 	Labels    LabelMap
+	Revision  int64 // only the policies written after this revision
 }
 
+// policy yaml lifecycle events
+const (
+	PolicyEventAdded    = "added"
+	PolicyEventUpdated  = "updated"
+	PolicyEventOutdated = "outdated"
+	PolicyEventDeleted  = "deleted"
+
+	// PolicyEventResync ends a stream whose queue dropped policies, the client re-lists from its revision
+	PolicyEventResync = "resync"
+)
+
 // PolicyYaml stores a policy in YAML format along with its metadata
 type PolicyYaml struct {
-	Type      string   `json:"type,omitempty"`
-	Kind      string   `json:"kind,omitempty"`
-	Name      string   `json:"name,omitempty"`
-	Namespace string   `json:"namespace,omitempty"`
-	Cluster   string   `json:"cluster,omitempty"`
-	Labels    LabelMap `json:"labels,omitempty"`
-	Yaml      []byte   `json:"yaml,omitempty"`
+	Type       string   `json:"type,omitempty"`
+	Kind       string   `json:"kind,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Cluster    string   `json:"cluster,omitempty"`
+	Labels     LabelMap `json:"labels,omitempty"`
+	Yaml       []byte   `json:"yaml,omitempty"`
+	Revision   int64    `json:"revision,omitempty"`
+	Event      string   `json:"event,omitempty"`
+	Replaces   string   `json:"replaces,omitempty"`
+	ReplacedBy string   `json:"replaced_by,omitempty"`
+}
+
+// IsLive returns false if the policy has been outdated or deleted
+func (p *PolicyYaml) IsLive() bool {
+	return p.Event != PolicyEventOutdated && p.Event != PolicyEventDeleted
+}
+
+// =================== //
+// == Discovery Run == //
+// =================== //
+
+// discovery run triggers and statuses
+const (
+	DiscoveryTriggerCron     = "cron"
+	DiscoveryTriggerOneTime  = "onetime"
+	DiscoveryTriggerRPC      = "rpc"
+	DiscoveryTriggerShutdown = "shutdown"
+
+	DiscoveryRunRunning   = "running"
+	DiscoveryRunCompleted = "completed"
+	DiscoveryRunFailed    = "failed"
+)
+
+// DiscoveryRun is the record of a discovery job, from the logs consumed to the policies discovered
+type DiscoveryRun struct {
+	ID              int64    `json:"id,omitempty"`
+	Type            string   `json:"type,omitempty"`
+	Trigger         string   `json:"trigger,omitempty"`
+	Status          string   `json:"status,omitempty"`
+	StartTime       int64    `json:"start_time,omitempty"`
+	EndTime         int64    `json:"end_time,omitempty"`
+	LogsConsumed    int      `json:"logs_consumed,omitempty"`
+	Clusters        []string `json:"clusters,omitempty"`
+	Namespaces      []string `json:"namespaces,omitempty"`
+	PoliciesCreated int      `json:"policies_created,omitempty"`
+	PoliciesUpdated int      `json:"policies_updated,omitempty"`
+	Errors          []string `json:"errors,omitempty"`
+}
+
+// AddNamespace records a processed cluster/namespace once, the namespaces are kept as cluster/namespace
+func (r *DiscoveryRun) AddNamespace(cluster, namespace string) {
+	if r == nil {
+		return
+	}
+
+	if !containsString(r.Clusters, cluster) {
+		r.Clusters = append(r.Clusters, cluster)
+	}
+
+	if ns := cluster + "/" + namespace; namespace != "" && !containsString(r.Namespaces, ns) {
+		r.Namespaces = append(r.Namespaces, ns)
+	}
+}
+
+// AddPolicies counts the created and updated policies
+func (r *DiscoveryRun) AddPolicies(created, updated int) {
+	if r == nil {
+		return
+	}
+
+	r.PoliciesCreated += created
+	r.PoliciesUpdated += updated
+}
+
+// AddError records an error that did not stop the run
+func (r *DiscoveryRun) AddError(err error) {
+	if r == nil || err == nil {
+		return
+	}
+
+	r.Errors = append(r.Errors, err.Error())
+}
+
+// Finish ends the run, it failed if nothing could be processed because of the errors
+func (r *DiscoveryRun) Finish() {
+	if r == nil {
+		return
+	}
+
+	r.EndTime = time.Now().Unix()
+
+	if len(r.Errors) > 0 && len(r.Clusters) == 0 {
+		r.Status = DiscoveryRunFailed
+	} else {
+		r.Status = DiscoveryRunCompleted
+	}
+}
+
+func containsString(slice []string, str string) bool {
+	for _, s := range slice {
+		if s == str {
+			return true
+		}
+	}
+	return false
 }